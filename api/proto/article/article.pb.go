@@ -9,6 +9,8 @@ package article
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	_ "gopress/api/proto/options"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +23,109 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ArticleView int32
+
+const (
+	// the same as FULL
+	ArticleView_ARTICLE_VIEW_UNSPECIFIED ArticleView = 0
+	ArticleView_ARTICLE_VIEW_FULL        ArticleView = 1
+	// without content and content_html, for index pages
+	ArticleView_ARTICLE_VIEW_SUMMARY ArticleView = 2
+)
+
+// Enum value maps for ArticleView.
+var (
+	ArticleView_name = map[int32]string{
+		0: "ARTICLE_VIEW_UNSPECIFIED",
+		1: "ARTICLE_VIEW_FULL",
+		2: "ARTICLE_VIEW_SUMMARY",
+	}
+	ArticleView_value = map[string]int32{
+		"ARTICLE_VIEW_UNSPECIFIED": 0,
+		"ARTICLE_VIEW_FULL":        1,
+		"ARTICLE_VIEW_SUMMARY":     2,
+	}
+)
+
+func (x ArticleView) Enum() *ArticleView {
+	p := new(ArticleView)
+	*p = x
+	return p
+}
+
+func (x ArticleView) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ArticleView) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_article_proto_enumTypes[0].Descriptor()
+}
+
+func (ArticleView) Type() protoreflect.EnumType {
+	return &file_api_proto_article_proto_enumTypes[0]
+}
+
+func (x ArticleView) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ArticleView.Descriptor instead.
+func (ArticleView) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{0}
+}
+
+type ArticleEventType int32
+
+const (
+	ArticleEventType_ARTICLE_EVENT_TYPE_UNSPECIFIED ArticleEventType = 0
+	ArticleEventType_ARTICLE_EVENT_TYPE_CREATED     ArticleEventType = 1
+	ArticleEventType_ARTICLE_EVENT_TYPE_UPDATED     ArticleEventType = 2
+	ArticleEventType_ARTICLE_EVENT_TYPE_DELETED     ArticleEventType = 3
+)
+
+// Enum value maps for ArticleEventType.
+var (
+	ArticleEventType_name = map[int32]string{
+		0: "ARTICLE_EVENT_TYPE_UNSPECIFIED",
+		1: "ARTICLE_EVENT_TYPE_CREATED",
+		2: "ARTICLE_EVENT_TYPE_UPDATED",
+		3: "ARTICLE_EVENT_TYPE_DELETED",
+	}
+	ArticleEventType_value = map[string]int32{
+		"ARTICLE_EVENT_TYPE_UNSPECIFIED": 0,
+		"ARTICLE_EVENT_TYPE_CREATED":     1,
+		"ARTICLE_EVENT_TYPE_UPDATED":     2,
+		"ARTICLE_EVENT_TYPE_DELETED":     3,
+	}
+)
+
+func (x ArticleEventType) Enum() *ArticleEventType {
+	p := new(ArticleEventType)
+	*p = x
+	return p
+}
+
+func (x ArticleEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ArticleEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_article_proto_enumTypes[1].Descriptor()
+}
+
+func (ArticleEventType) Type() protoreflect.EnumType {
+	return &file_api_proto_article_proto_enumTypes[1]
+}
+
+func (x ArticleEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ArticleEventType.Descriptor instead.
+func (ArticleEventType) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{1}
+}
+
 type Article struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -30,6 +135,21 @@ type Article struct {
 	AuthorUsername string                 `protobuf:"bytes,5,opt,name=author_username,json=authorUsername,proto3" json:"author_username,omitempty"`
 	CreatedAtUnix  int64                  `protobuf:"varint,6,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
 	UpdatedAtUnix  int64                  `protobuf:"varint,7,opt,name=updated_at_unix,json=updatedAtUnix,proto3" json:"updated_at_unix,omitempty"`
+	// set for articles in the trash
+	DeletedAtUnix int64 `protobuf:"varint,8,opt,name=deleted_at_unix,json=deletedAtUnix,proto3" json:"deleted_at_unix,omitempty"`
+	// grows with every update, see expected_version
+	Version int64 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	// "markdown", "html" or "plain"
+	ContentFormat string `protobuf:"bytes,10,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"`
+	// content rendered to sanitized HTML
+	ContentHtml string `protobuf:"bytes,11,opt,name=content_html,json=contentHtml,proto3" json:"content_html,omitempty"`
+	// URL name made from the title, see GetBySlug
+	Slug string `protobuf:"bytes,12,opt,name=slug,proto3" json:"slug,omitempty"`
+	// derived from content: its start as plain text, its words and the
+	// minutes it takes to read at 200 words per minute
+	Excerpt        string `protobuf:"bytes,13,opt,name=excerpt,proto3" json:"excerpt,omitempty"`
+	WordCount      int32  `protobuf:"varint,14,opt,name=word_count,json=wordCount,proto3" json:"word_count,omitempty"`
+	ReadingMinutes int32  `protobuf:"varint,15,opt,name=reading_minutes,json=readingMinutes,proto3" json:"reading_minutes,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -113,10 +233,67 @@ func (x *Article) GetUpdatedAtUnix() int64 {
 	return 0
 }
 
+func (x *Article) GetDeletedAtUnix() int64 {
+	if x != nil {
+		return x.DeletedAtUnix
+	}
+	return 0
+}
+
+func (x *Article) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Article) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
+func (x *Article) GetContentHtml() string {
+	if x != nil {
+		return x.ContentHtml
+	}
+	return ""
+}
+
+func (x *Article) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Article) GetExcerpt() string {
+	if x != nil {
+		return x.Excerpt
+	}
+	return ""
+}
+
+func (x *Article) GetWordCount() int32 {
+	if x != nil {
+		return x.WordCount
+	}
+	return 0
+}
+
+func (x *Article) GetReadingMinutes() int32 {
+	if x != nil {
+		return x.ReadingMinutes
+	}
+	return 0
+}
+
 type ListArticlesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
 	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	View          ArticleView            `protobuf:"varint,3,opt,name=view,proto3,enum=article.ArticleView" json:"view,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -165,6 +342,13 @@ func (x *ListArticlesRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *ListArticlesRequest) GetView() ArticleView {
+	if x != nil {
+		return x.View
+	}
+	return ArticleView_ARTICLE_VIEW_UNSPECIFIED
+}
+
 type ListArticlesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Articles      []*Article             `protobuf:"bytes,1,rep,name=articles,proto3" json:"articles,omitempty"`
@@ -297,17 +481,116 @@ func (x *GetArticleResponse) GetArticle() *Article {
 	return nil
 }
 
-type CreateArticleRequest struct {
+type GetArticleBySlugRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Slug          string                 `protobuf:"bytes,1,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArticleBySlugRequest) Reset() {
+	*x = GetArticleBySlugRequest{}
+	mi := &file_api_proto_article_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArticleBySlugRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArticleBySlugRequest) ProtoMessage() {}
+
+func (x *GetArticleBySlugRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArticleBySlugRequest.ProtoReflect.Descriptor instead.
+func (*GetArticleBySlugRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetArticleBySlugRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type GetArticleBySlugResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Article *Article               `protobuf:"bytes,1,opt,name=article,proto3" json:"article,omitempty"`
+	// the slug asked for is a former one, article.slug is the permalink
+	Moved         bool `protobuf:"varint,2,opt,name=moved,proto3" json:"moved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArticleBySlugResponse) Reset() {
+	*x = GetArticleBySlugResponse{}
+	mi := &file_api_proto_article_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArticleBySlugResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArticleBySlugResponse) ProtoMessage() {}
+
+func (x *GetArticleBySlugResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArticleBySlugResponse.ProtoReflect.Descriptor instead.
+func (*GetArticleBySlugResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetArticleBySlugResponse) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+func (x *GetArticleBySlugResponse) GetMoved() bool {
+	if x != nil {
+		return x.Moved
+	}
+	return false
+}
+
+type CreateArticleRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Title   string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Content string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// "markdown" (default), "html" or "plain"
+	ContentFormat string `protobuf:"bytes,3,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateArticleRequest) Reset() {
 	*x = CreateArticleRequest{}
-	mi := &file_api_proto_article_proto_msgTypes[5]
+	mi := &file_api_proto_article_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -319,7 +602,7 @@ func (x *CreateArticleRequest) String() string {
 func (*CreateArticleRequest) ProtoMessage() {}
 
 func (x *CreateArticleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_article_proto_msgTypes[5]
+	mi := &file_api_proto_article_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -332,7 +615,7 @@ func (x *CreateArticleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateArticleRequest.ProtoReflect.Descriptor instead.
 func (*CreateArticleRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_article_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_article_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateArticleRequest) GetTitle() string {
@@ -349,6 +632,13 @@ func (x *CreateArticleRequest) GetContent() string {
 	return ""
 }
 
+func (x *CreateArticleRequest) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
 type CreateArticleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -359,7 +649,7 @@ type CreateArticleResponse struct {
 
 func (x *CreateArticleResponse) Reset() {
 	*x = CreateArticleResponse{}
-	mi := &file_api_proto_article_proto_msgTypes[6]
+	mi := &file_api_proto_article_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -371,7 +661,7 @@ func (x *CreateArticleResponse) String() string {
 func (*CreateArticleResponse) ProtoMessage() {}
 
 func (x *CreateArticleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_article_proto_msgTypes[6]
+	mi := &file_api_proto_article_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -384,7 +674,7 @@ func (x *CreateArticleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateArticleResponse.ProtoReflect.Descriptor instead.
 func (*CreateArticleResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_article_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_article_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CreateArticleResponse) GetStatus() string {
@@ -402,17 +692,25 @@ func (x *CreateArticleResponse) GetId() int64 {
 }
 
 type UpdateArticleRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title   string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Content string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// fail with FAILED_PRECONDITION unless the article is at this version;
+	// 0 = any version
+	ExpectedVersion int64 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	// fields of this message to change, e.g. ["title"]; the others are
+	// kept. Empty = replace all editable fields.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,5,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// empty keeps the current format unless named in update_mask
+	ContentFormat string `protobuf:"bytes,6,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateArticleRequest) Reset() {
 	*x = UpdateArticleRequest{}
-	mi := &file_api_proto_article_proto_msgTypes[7]
+	mi := &file_api_proto_article_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -424,7 +722,7 @@ func (x *UpdateArticleRequest) String() string {
 func (*UpdateArticleRequest) ProtoMessage() {}
 
 func (x *UpdateArticleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_article_proto_msgTypes[7]
+	mi := &file_api_proto_article_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -437,7 +735,7 @@ func (x *UpdateArticleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateArticleRequest.ProtoReflect.Descriptor instead.
 func (*UpdateArticleRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_article_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_article_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UpdateArticleRequest) GetId() int64 {
@@ -461,16 +759,39 @@ func (x *UpdateArticleRequest) GetContent() string {
 	return ""
 }
 
+func (x *UpdateArticleRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *UpdateArticleRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateArticleRequest) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
 type UpdateArticleResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// the new version
+	Version       int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateArticleResponse) Reset() {
 	*x = UpdateArticleResponse{}
-	mi := &file_api_proto_article_proto_msgTypes[8]
+	mi := &file_api_proto_article_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -482,7 +803,7 @@ func (x *UpdateArticleResponse) String() string {
 func (*UpdateArticleResponse) ProtoMessage() {}
 
 func (x *UpdateArticleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_article_proto_msgTypes[8]
+	mi := &file_api_proto_article_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -495,7 +816,7 @@ func (x *UpdateArticleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateArticleResponse.ProtoReflect.Descriptor instead.
 func (*UpdateArticleResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_article_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_article_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UpdateArticleResponse) GetStatus() string {
@@ -505,16 +826,25 @@ func (x *UpdateArticleResponse) GetStatus() string {
 	return ""
 }
 
+func (x *UpdateArticleResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type DeleteArticleRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// as in UpdateArticleRequest
+	ExpectedVersion int64 `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *DeleteArticleRequest) Reset() {
 	*x = DeleteArticleRequest{}
-	mi := &file_api_proto_article_proto_msgTypes[9]
+	mi := &file_api_proto_article_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -526,7 +856,7 @@ func (x *DeleteArticleRequest) String() string {
 func (*DeleteArticleRequest) ProtoMessage() {}
 
 func (x *DeleteArticleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_article_proto_msgTypes[9]
+	mi := &file_api_proto_article_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -539,7 +869,7 @@ func (x *DeleteArticleRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteArticleRequest.ProtoReflect.Descriptor instead.
 func (*DeleteArticleRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_article_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_article_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DeleteArticleRequest) GetId() int64 {
@@ -549,6 +879,13 @@ func (x *DeleteArticleRequest) GetId() int64 {
 	return 0
 }
 
+func (x *DeleteArticleRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type DeleteArticleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -558,7 +895,7 @@ type DeleteArticleResponse struct {
 
 func (x *DeleteArticleResponse) Reset() {
 	*x = DeleteArticleResponse{}
-	mi := &file_api_proto_article_proto_msgTypes[10]
+	mi := &file_api_proto_article_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -570,7 +907,7 @@ func (x *DeleteArticleResponse) String() string {
 func (*DeleteArticleResponse) ProtoMessage() {}
 
 func (x *DeleteArticleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_article_proto_msgTypes[10]
+	mi := &file_api_proto_article_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -583,7 +920,7 @@ func (x *DeleteArticleResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteArticleResponse.ProtoReflect.Descriptor instead.
 func (*DeleteArticleResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_article_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_article_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DeleteArticleResponse) GetStatus() string {
@@ -593,11 +930,389 @@ func (x *DeleteArticleResponse) GetStatus() string {
 	return ""
 }
 
+type ListTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	mi := &file_api_proto_article_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{13}
+}
+
+type ListTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Articles      []*Article             `protobuf:"bytes,1,rep,name=articles,proto3" json:"articles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_api_proto_article_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListTrashResponse) GetArticles() []*Article {
+	if x != nil {
+		return x.Articles
+	}
+	return nil
+}
+
+type RestoreArticleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreArticleRequest) Reset() {
+	*x = RestoreArticleRequest{}
+	mi := &file_api_proto_article_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreArticleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreArticleRequest) ProtoMessage() {}
+
+func (x *RestoreArticleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreArticleRequest.ProtoReflect.Descriptor instead.
+func (*RestoreArticleRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestoreArticleRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreArticleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreArticleResponse) Reset() {
+	*x = RestoreArticleResponse{}
+	mi := &file_api_proto_article_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreArticleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreArticleResponse) ProtoMessage() {}
+
+func (x *RestoreArticleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreArticleResponse.ProtoReflect.Descriptor instead.
+func (*RestoreArticleResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RestoreArticleResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type PurgeArticleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeArticleRequest) Reset() {
+	*x = PurgeArticleRequest{}
+	mi := &file_api_proto_article_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeArticleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeArticleRequest) ProtoMessage() {}
+
+func (x *PurgeArticleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeArticleRequest.ProtoReflect.Descriptor instead.
+func (*PurgeArticleRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PurgeArticleRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PurgeArticleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeArticleResponse) Reset() {
+	*x = PurgeArticleResponse{}
+	mi := &file_api_proto_article_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeArticleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeArticleResponse) ProtoMessage() {}
+
+func (x *PurgeArticleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeArticleResponse.ProtoReflect.Descriptor instead.
+func (*PurgeArticleResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PurgeArticleResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type WatchArticlesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// resume after this event id; 0 streams only new events
+	LastEventId   int64 `protobuf:"varint,1,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchArticlesRequest) Reset() {
+	*x = WatchArticlesRequest{}
+	mi := &file_api_proto_article_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchArticlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchArticlesRequest) ProtoMessage() {}
+
+func (x *WatchArticlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchArticlesRequest.ProtoReflect.Descriptor instead.
+func (*WatchArticlesRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchArticlesRequest) GetLastEventId() int64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type ArticleEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type      ArticleEventType       `protobuf:"varint,2,opt,name=type,proto3,enum=article.ArticleEventType" json:"type,omitempty"`
+	ArticleId int64                  `protobuf:"varint,3,opt,name=article_id,json=articleId,proto3" json:"article_id,omitempty"`
+	// empty for deleted articles
+	Article        *Article `protobuf:"bytes,4,opt,name=article,proto3" json:"article,omitempty"`
+	OccurredAtUnix int64    `protobuf:"varint,5,opt,name=occurred_at_unix,json=occurredAtUnix,proto3" json:"occurred_at_unix,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ArticleEvent) Reset() {
+	*x = ArticleEvent{}
+	mi := &file_api_proto_article_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArticleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArticleEvent) ProtoMessage() {}
+
+func (x *ArticleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_article_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArticleEvent.ProtoReflect.Descriptor instead.
+func (*ArticleEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_article_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ArticleEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ArticleEvent) GetType() ArticleEventType {
+	if x != nil {
+		return x.Type
+	}
+	return ArticleEventType_ARTICLE_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *ArticleEvent) GetArticleId() int64 {
+	if x != nil {
+		return x.ArticleId
+	}
+	return 0
+}
+
+func (x *ArticleEvent) GetArticle() *Article {
+	if x != nil {
+		return x.Article
+	}
+	return nil
+}
+
+func (x *ArticleEvent) GetOccurredAtUnix() int64 {
+	if x != nil {
+		return x.OccurredAtUnix
+	}
+	return 0
+}
+
 var File_api_proto_article_proto protoreflect.FileDescriptor
 
 const file_api_proto_article_proto_rawDesc = "" +
 	"\n" +
-	"\x17api/proto/article.proto\x12\aarticle\"\xdf\x01\n" +
+	"\x17api/proto/article.proto\x12\aarticle\x1a\x17api/proto/options.proto\x1a google/protobuf/field_mask.proto\"\xe1\x03\n" +
 	"\aArticle\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
@@ -605,38 +1320,95 @@ const file_api_proto_article_proto_rawDesc = "" +
 	"\tauthor_id\x18\x04 \x01(\tR\bauthorId\x12'\n" +
 	"\x0fauthor_username\x18\x05 \x01(\tR\x0eauthorUsername\x12&\n" +
 	"\x0fcreated_at_unix\x18\x06 \x01(\x03R\rcreatedAtUnix\x12&\n" +
-	"\x0fupdated_at_unix\x18\a \x01(\x03R\rupdatedAtUnix\"C\n" +
+	"\x0fupdated_at_unix\x18\a \x01(\x03R\rupdatedAtUnix\x12&\n" +
+	"\x0fdeleted_at_unix\x18\b \x01(\x03R\rdeletedAtUnix\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x12%\n" +
+	"\x0econtent_format\x18\n" +
+	" \x01(\tR\rcontentFormat\x12!\n" +
+	"\fcontent_html\x18\v \x01(\tR\vcontentHtml\x12\x12\n" +
+	"\x04slug\x18\f \x01(\tR\x04slug\x12\x18\n" +
+	"\aexcerpt\x18\r \x01(\tR\aexcerpt\x12\x1d\n" +
+	"\n" +
+	"word_count\x18\x0e \x01(\x05R\twordCount\x12'\n" +
+	"\x0freading_minutes\x18\x0f \x01(\x05R\x0ereadingMinutes\"m\n" +
 	"\x13ListArticlesRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"D\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12(\n" +
+	"\x04view\x18\x03 \x01(\x0e2\x14.article.ArticleViewR\x04view\"D\n" +
 	"\x14ListArticlesResponse\x12,\n" +
 	"\barticles\x18\x01 \x03(\v2\x10.article.ArticleR\barticles\"#\n" +
 	"\x11GetArticleRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"@\n" +
 	"\x12GetArticleResponse\x12*\n" +
-	"\aarticle\x18\x01 \x01(\v2\x10.article.ArticleR\aarticle\"F\n" +
+	"\aarticle\x18\x01 \x01(\v2\x10.article.ArticleR\aarticle\"-\n" +
+	"\x17GetArticleBySlugRequest\x12\x12\n" +
+	"\x04slug\x18\x01 \x01(\tR\x04slug\"\\\n" +
+	"\x18GetArticleBySlugResponse\x12*\n" +
+	"\aarticle\x18\x01 \x01(\v2\x10.article.ArticleR\aarticle\x12\x14\n" +
+	"\x05moved\x18\x02 \x01(\bR\x05moved\"m\n" +
 	"\x14CreateArticleRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
-	"\acontent\x18\x02 \x01(\tR\acontent\"?\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\x12%\n" +
+	"\x0econtent_format\x18\x03 \x01(\tR\rcontentFormat\"?\n" +
 	"\x15CreateArticleResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\x03R\x02id\"V\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"\xe5\x01\n" +
 	"\x14UpdateArticleRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
-	"\acontent\x18\x03 \x01(\tR\acontent\"/\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x03R\x0fexpectedVersion\x12;\n" +
+	"\vupdate_mask\x18\x05 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12%\n" +
+	"\x0econtent_format\x18\x06 \x01(\tR\rcontentFormat\"I\n" +
 	"\x15UpdateArticleResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"&\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"Q\n" +
 	"\x14DeleteArticleRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"/\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"/\n" +
 	"\x15DeleteArticleResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status2\xf0\x02\n" +
-	"\x0eArticleService\x12C\n" +
-	"\x04List\x12\x1c.article.ListArticlesRequest\x1a\x1d.article.ListArticlesResponse\x12>\n" +
-	"\x03Get\x12\x1a.article.GetArticleRequest\x1a\x1b.article.GetArticleResponse\x12G\n" +
-	"\x06Create\x12\x1d.article.CreateArticleRequest\x1a\x1e.article.CreateArticleResponse\x12G\n" +
-	"\x06Update\x12\x1d.article.UpdateArticleRequest\x1a\x1e.article.UpdateArticleResponse\x12G\n" +
-	"\x06Delete\x12\x1d.article.DeleteArticleRequest\x1a\x1e.article.DeleteArticleResponseB\x1bZ\x19api/proto/article;articleb\x06proto3"
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x12\n" +
+	"\x10ListTrashRequest\"A\n" +
+	"\x11ListTrashResponse\x12,\n" +
+	"\barticles\x18\x01 \x03(\v2\x10.article.ArticleR\barticles\"'\n" +
+	"\x15RestoreArticleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"0\n" +
+	"\x16RestoreArticleResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"%\n" +
+	"\x13PurgeArticleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\".\n" +
+	"\x14PurgeArticleResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\":\n" +
+	"\x14WatchArticlesRequest\x12\"\n" +
+	"\rlast_event_id\x18\x01 \x01(\x03R\vlastEventId\"\xc2\x01\n" +
+	"\fArticleEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12-\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x19.article.ArticleEventTypeR\x04type\x12\x1d\n" +
+	"\n" +
+	"article_id\x18\x03 \x01(\x03R\tarticleId\x12*\n" +
+	"\aarticle\x18\x04 \x01(\v2\x10.article.ArticleR\aarticle\x12(\n" +
+	"\x10occurred_at_unix\x18\x05 \x01(\x03R\x0eoccurredAtUnix*\\\n" +
+	"\vArticleView\x12\x1c\n" +
+	"\x18ARTICLE_VIEW_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11ARTICLE_VIEW_FULL\x10\x01\x12\x18\n" +
+	"\x14ARTICLE_VIEW_SUMMARY\x10\x02*\xba\x01\n" +
+	"\x10ArticleEventType\x12\"\n" +
+	"\x1eARTICLE_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aARTICLE_EVENT_TYPE_CREATED\x10\x01\x12\x1e\n" +
+	"\x1aARTICLE_EVENT_TYPE_UPDATED\x10\x02\x12\x1e\n" +
+	"\x1aARTICLE_EVENT_TYPE_DELETED\x10\x03\"\x04\b\x04\x10\x04*\x1cARTICLE_EVENT_TYPE_PUBLISHED2\x88\a\n" +
+	"\x0eArticleService\x12I\n" +
+	"\x04List\x12\x1c.article.ListArticlesRequest\x1a\x1d.article.ListArticlesResponse\"\x04\x88\xb5\x18\x01\x12D\n" +
+	"\x03Get\x12\x1a.article.GetArticleRequest\x1a\x1b.article.GetArticleResponse\"\x04\x88\xb5\x18\x01\x12V\n" +
+	"\tGetBySlug\x12 .article.GetArticleBySlugRequest\x1a!.article.GetArticleBySlugResponse\"\x04\x88\xb5\x18\x01\x12M\n" +
+	"\rWatchArticles\x12\x1d.article.WatchArticlesRequest\x1a\x15.article.ArticleEvent\"\x04\x88\xb5\x18\x010\x01\x12_\n" +
+	"\x06Create\x12\x1d.article.CreateArticleRequest\x1a\x1e.article.CreateArticleResponse\"\x16\x88\xb5\x18\x02\x92\xb5\x18\x0earticles:write\x12_\n" +
+	"\x06Update\x12\x1d.article.UpdateArticleRequest\x1a\x1e.article.UpdateArticleResponse\"\x16\x88\xb5\x18\x02\x92\xb5\x18\x0earticles:write\x12_\n" +
+	"\x06Delete\x12\x1d.article.DeleteArticleRequest\x1a\x1e.article.DeleteArticleResponse\"\x16\x88\xb5\x18\x02\x92\xb5\x18\x0earticles:write\x12Y\n" +
+	"\tListTrash\x12\x19.article.ListTrashRequest\x1a\x1a.article.ListTrashResponse\"\x15\x88\xb5\x18\x02\x92\xb5\x18\rarticles:read\x12b\n" +
+	"\aRestore\x12\x1e.article.RestoreArticleRequest\x1a\x1f.article.RestoreArticleResponse\"\x16\x88\xb5\x18\x02\x92\xb5\x18\x0earticles:write\x12\\\n" +
+	"\x05Purge\x12\x1c.article.PurgeArticleRequest\x1a\x1d.article.PurgeArticleResponse\"\x16\x88\xb5\x18\x02\x92\xb5\x18\x0earticles:writeB\x1bZ\x19api/proto/article;articleb\x06proto3"
 
 var (
 	file_api_proto_article_proto_rawDescOnce sync.Once
@@ -650,38 +1422,68 @@ func file_api_proto_article_proto_rawDescGZIP() []byte {
 	return file_api_proto_article_proto_rawDescData
 }
 
-var file_api_proto_article_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_proto_article_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_proto_article_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_api_proto_article_proto_goTypes = []any{
-	(*Article)(nil),               // 0: article.Article
-	(*ListArticlesRequest)(nil),   // 1: article.ListArticlesRequest
-	(*ListArticlesResponse)(nil),  // 2: article.ListArticlesResponse
-	(*GetArticleRequest)(nil),     // 3: article.GetArticleRequest
-	(*GetArticleResponse)(nil),    // 4: article.GetArticleResponse
-	(*CreateArticleRequest)(nil),  // 5: article.CreateArticleRequest
-	(*CreateArticleResponse)(nil), // 6: article.CreateArticleResponse
-	(*UpdateArticleRequest)(nil),  // 7: article.UpdateArticleRequest
-	(*UpdateArticleResponse)(nil), // 8: article.UpdateArticleResponse
-	(*DeleteArticleRequest)(nil),  // 9: article.DeleteArticleRequest
-	(*DeleteArticleResponse)(nil), // 10: article.DeleteArticleResponse
+	(ArticleView)(0),                 // 0: article.ArticleView
+	(ArticleEventType)(0),            // 1: article.ArticleEventType
+	(*Article)(nil),                  // 2: article.Article
+	(*ListArticlesRequest)(nil),      // 3: article.ListArticlesRequest
+	(*ListArticlesResponse)(nil),     // 4: article.ListArticlesResponse
+	(*GetArticleRequest)(nil),        // 5: article.GetArticleRequest
+	(*GetArticleResponse)(nil),       // 6: article.GetArticleResponse
+	(*GetArticleBySlugRequest)(nil),  // 7: article.GetArticleBySlugRequest
+	(*GetArticleBySlugResponse)(nil), // 8: article.GetArticleBySlugResponse
+	(*CreateArticleRequest)(nil),     // 9: article.CreateArticleRequest
+	(*CreateArticleResponse)(nil),    // 10: article.CreateArticleResponse
+	(*UpdateArticleRequest)(nil),     // 11: article.UpdateArticleRequest
+	(*UpdateArticleResponse)(nil),    // 12: article.UpdateArticleResponse
+	(*DeleteArticleRequest)(nil),     // 13: article.DeleteArticleRequest
+	(*DeleteArticleResponse)(nil),    // 14: article.DeleteArticleResponse
+	(*ListTrashRequest)(nil),         // 15: article.ListTrashRequest
+	(*ListTrashResponse)(nil),        // 16: article.ListTrashResponse
+	(*RestoreArticleRequest)(nil),    // 17: article.RestoreArticleRequest
+	(*RestoreArticleResponse)(nil),   // 18: article.RestoreArticleResponse
+	(*PurgeArticleRequest)(nil),      // 19: article.PurgeArticleRequest
+	(*PurgeArticleResponse)(nil),     // 20: article.PurgeArticleResponse
+	(*WatchArticlesRequest)(nil),     // 21: article.WatchArticlesRequest
+	(*ArticleEvent)(nil),             // 22: article.ArticleEvent
+	(*fieldmaskpb.FieldMask)(nil),    // 23: google.protobuf.FieldMask
 }
 var file_api_proto_article_proto_depIdxs = []int32{
-	0,  // 0: article.ListArticlesResponse.articles:type_name -> article.Article
-	0,  // 1: article.GetArticleResponse.article:type_name -> article.Article
-	1,  // 2: article.ArticleService.List:input_type -> article.ListArticlesRequest
-	3,  // 3: article.ArticleService.Get:input_type -> article.GetArticleRequest
-	5,  // 4: article.ArticleService.Create:input_type -> article.CreateArticleRequest
-	7,  // 5: article.ArticleService.Update:input_type -> article.UpdateArticleRequest
-	9,  // 6: article.ArticleService.Delete:input_type -> article.DeleteArticleRequest
-	2,  // 7: article.ArticleService.List:output_type -> article.ListArticlesResponse
-	4,  // 8: article.ArticleService.Get:output_type -> article.GetArticleResponse
-	6,  // 9: article.ArticleService.Create:output_type -> article.CreateArticleResponse
-	8,  // 10: article.ArticleService.Update:output_type -> article.UpdateArticleResponse
-	10, // 11: article.ArticleService.Delete:output_type -> article.DeleteArticleResponse
-	7,  // [7:12] is the sub-list for method output_type
-	2,  // [2:7] is the sub-list for method input_type
-	2,  // [2:2] is the sub-list for extension type_name
-	2,  // [2:2] is the sub-list for extension extendee
-	0,  // [0:2] is the sub-list for field type_name
+	0,  // 0: article.ListArticlesRequest.view:type_name -> article.ArticleView
+	2,  // 1: article.ListArticlesResponse.articles:type_name -> article.Article
+	2,  // 2: article.GetArticleResponse.article:type_name -> article.Article
+	2,  // 3: article.GetArticleBySlugResponse.article:type_name -> article.Article
+	23, // 4: article.UpdateArticleRequest.update_mask:type_name -> google.protobuf.FieldMask
+	2,  // 5: article.ListTrashResponse.articles:type_name -> article.Article
+	1,  // 6: article.ArticleEvent.type:type_name -> article.ArticleEventType
+	2,  // 7: article.ArticleEvent.article:type_name -> article.Article
+	3,  // 8: article.ArticleService.List:input_type -> article.ListArticlesRequest
+	5,  // 9: article.ArticleService.Get:input_type -> article.GetArticleRequest
+	7,  // 10: article.ArticleService.GetBySlug:input_type -> article.GetArticleBySlugRequest
+	21, // 11: article.ArticleService.WatchArticles:input_type -> article.WatchArticlesRequest
+	9,  // 12: article.ArticleService.Create:input_type -> article.CreateArticleRequest
+	11, // 13: article.ArticleService.Update:input_type -> article.UpdateArticleRequest
+	13, // 14: article.ArticleService.Delete:input_type -> article.DeleteArticleRequest
+	15, // 15: article.ArticleService.ListTrash:input_type -> article.ListTrashRequest
+	17, // 16: article.ArticleService.Restore:input_type -> article.RestoreArticleRequest
+	19, // 17: article.ArticleService.Purge:input_type -> article.PurgeArticleRequest
+	4,  // 18: article.ArticleService.List:output_type -> article.ListArticlesResponse
+	6,  // 19: article.ArticleService.Get:output_type -> article.GetArticleResponse
+	8,  // 20: article.ArticleService.GetBySlug:output_type -> article.GetArticleBySlugResponse
+	22, // 21: article.ArticleService.WatchArticles:output_type -> article.ArticleEvent
+	10, // 22: article.ArticleService.Create:output_type -> article.CreateArticleResponse
+	12, // 23: article.ArticleService.Update:output_type -> article.UpdateArticleResponse
+	14, // 24: article.ArticleService.Delete:output_type -> article.DeleteArticleResponse
+	16, // 25: article.ArticleService.ListTrash:output_type -> article.ListTrashResponse
+	18, // 26: article.ArticleService.Restore:output_type -> article.RestoreArticleResponse
+	20, // 27: article.ArticleService.Purge:output_type -> article.PurgeArticleResponse
+	18, // [18:28] is the sub-list for method output_type
+	8,  // [8:18] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_article_proto_init() }
@@ -694,13 +1496,14 @@ func file_api_proto_article_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_article_proto_rawDesc), len(file_api_proto_article_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   11,
+			NumEnums:      2,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_proto_article_proto_goTypes,
 		DependencyIndexes: file_api_proto_article_proto_depIdxs,
+		EnumInfos:         file_api_proto_article_proto_enumTypes,
 		MessageInfos:      file_api_proto_article_proto_msgTypes,
 	}.Build()
 	File_api_proto_article_proto = out.File
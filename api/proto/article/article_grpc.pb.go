@@ -19,11 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ArticleService_List_FullMethodName   = "/article.ArticleService/List"
-	ArticleService_Get_FullMethodName    = "/article.ArticleService/Get"
-	ArticleService_Create_FullMethodName = "/article.ArticleService/Create"
-	ArticleService_Update_FullMethodName = "/article.ArticleService/Update"
-	ArticleService_Delete_FullMethodName = "/article.ArticleService/Delete"
+	ArticleService_List_FullMethodName          = "/article.ArticleService/List"
+	ArticleService_Get_FullMethodName           = "/article.ArticleService/Get"
+	ArticleService_GetBySlug_FullMethodName     = "/article.ArticleService/GetBySlug"
+	ArticleService_WatchArticles_FullMethodName = "/article.ArticleService/WatchArticles"
+	ArticleService_Create_FullMethodName        = "/article.ArticleService/Create"
+	ArticleService_Update_FullMethodName        = "/article.ArticleService/Update"
+	ArticleService_Delete_FullMethodName        = "/article.ArticleService/Delete"
+	ArticleService_ListTrash_FullMethodName     = "/article.ArticleService/ListTrash"
+	ArticleService_Restore_FullMethodName       = "/article.ArticleService/Restore"
+	ArticleService_Purge_FullMethodName         = "/article.ArticleService/Purge"
 )
 
 // ArticleServiceClient is the client API for ArticleService service.
@@ -33,10 +38,19 @@ type ArticleServiceClient interface {
 	// public
 	List(ctx context.Context, in *ListArticlesRequest, opts ...grpc.CallOption) (*ListArticlesResponse, error)
 	Get(ctx context.Context, in *GetArticleRequest, opts ...grpc.CallOption) (*GetArticleResponse, error)
+	// by current or former slug
+	GetBySlug(ctx context.Context, in *GetArticleBySlugRequest, opts ...grpc.CallOption) (*GetArticleBySlugResponse, error)
+	WatchArticles(ctx context.Context, in *WatchArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArticleEvent], error)
 	// protected (JWT в metadata: authorization: Bearer <token>)
 	Create(ctx context.Context, in *CreateArticleRequest, opts ...grpc.CallOption) (*CreateArticleResponse, error)
 	Update(ctx context.Context, in *UpdateArticleRequest, opts ...grpc.CallOption) (*UpdateArticleResponse, error)
+	// moves the article to the trash
 	Delete(ctx context.Context, in *DeleteArticleRequest, opts ...grpc.CallOption) (*DeleteArticleResponse, error)
+	// trash of the caller
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	Restore(ctx context.Context, in *RestoreArticleRequest, opts ...grpc.CallOption) (*RestoreArticleResponse, error)
+	// deletes a trashed article for good
+	Purge(ctx context.Context, in *PurgeArticleRequest, opts ...grpc.CallOption) (*PurgeArticleResponse, error)
 }
 
 type articleServiceClient struct {
@@ -67,6 +81,35 @@ func (c *articleServiceClient) Get(ctx context.Context, in *GetArticleRequest, o
 	return out, nil
 }
 
+func (c *articleServiceClient) GetBySlug(ctx context.Context, in *GetArticleBySlugRequest, opts ...grpc.CallOption) (*GetArticleBySlugResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetArticleBySlugResponse)
+	err := c.cc.Invoke(ctx, ArticleService_GetBySlug_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) WatchArticles(ctx context.Context, in *WatchArticlesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArticleEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ArticleService_ServiceDesc.Streams[0], ArticleService_WatchArticles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchArticlesRequest, ArticleEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArticleService_WatchArticlesClient = grpc.ServerStreamingClient[ArticleEvent]
+
 func (c *articleServiceClient) Create(ctx context.Context, in *CreateArticleRequest, opts ...grpc.CallOption) (*CreateArticleResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateArticleResponse)
@@ -97,6 +140,36 @@ func (c *articleServiceClient) Delete(ctx context.Context, in *DeleteArticleRequ
 	return out, nil
 }
 
+func (c *articleServiceClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, ArticleService_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) Restore(ctx context.Context, in *RestoreArticleRequest, opts ...grpc.CallOption) (*RestoreArticleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleService_Restore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *articleServiceClient) Purge(ctx context.Context, in *PurgeArticleRequest, opts ...grpc.CallOption) (*PurgeArticleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeArticleResponse)
+	err := c.cc.Invoke(ctx, ArticleService_Purge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ArticleServiceServer is the server API for ArticleService service.
 // All implementations must embed UnimplementedArticleServiceServer
 // for forward compatibility.
@@ -104,10 +177,19 @@ type ArticleServiceServer interface {
 	// public
 	List(context.Context, *ListArticlesRequest) (*ListArticlesResponse, error)
 	Get(context.Context, *GetArticleRequest) (*GetArticleResponse, error)
+	// by current or former slug
+	GetBySlug(context.Context, *GetArticleBySlugRequest) (*GetArticleBySlugResponse, error)
+	WatchArticles(*WatchArticlesRequest, grpc.ServerStreamingServer[ArticleEvent]) error
 	// protected (JWT в metadata: authorization: Bearer <token>)
 	Create(context.Context, *CreateArticleRequest) (*CreateArticleResponse, error)
 	Update(context.Context, *UpdateArticleRequest) (*UpdateArticleResponse, error)
+	// moves the article to the trash
 	Delete(context.Context, *DeleteArticleRequest) (*DeleteArticleResponse, error)
+	// trash of the caller
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	Restore(context.Context, *RestoreArticleRequest) (*RestoreArticleResponse, error)
+	// deletes a trashed article for good
+	Purge(context.Context, *PurgeArticleRequest) (*PurgeArticleResponse, error)
 	mustEmbedUnimplementedArticleServiceServer()
 }
 
@@ -124,6 +206,12 @@ func (UnimplementedArticleServiceServer) List(context.Context, *ListArticlesRequ
 func (UnimplementedArticleServiceServer) Get(context.Context, *GetArticleRequest) (*GetArticleResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Get not implemented")
 }
+func (UnimplementedArticleServiceServer) GetBySlug(context.Context, *GetArticleBySlugRequest) (*GetArticleBySlugResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBySlug not implemented")
+}
+func (UnimplementedArticleServiceServer) WatchArticles(*WatchArticlesRequest, grpc.ServerStreamingServer[ArticleEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchArticles not implemented")
+}
 func (UnimplementedArticleServiceServer) Create(context.Context, *CreateArticleRequest) (*CreateArticleResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Create not implemented")
 }
@@ -133,6 +221,15 @@ func (UnimplementedArticleServiceServer) Update(context.Context, *UpdateArticleR
 func (UnimplementedArticleServiceServer) Delete(context.Context, *DeleteArticleRequest) (*DeleteArticleResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedArticleServiceServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedArticleServiceServer) Restore(context.Context, *RestoreArticleRequest) (*RestoreArticleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedArticleServiceServer) Purge(context.Context, *PurgeArticleRequest) (*PurgeArticleResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Purge not implemented")
+}
 func (UnimplementedArticleServiceServer) mustEmbedUnimplementedArticleServiceServer() {}
 func (UnimplementedArticleServiceServer) testEmbeddedByValue()                        {}
 
@@ -190,6 +287,35 @@ func _ArticleService_Get_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ArticleService_GetBySlug_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArticleBySlugRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).GetBySlug(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_GetBySlug_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).GetBySlug(ctx, req.(*GetArticleBySlugRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_WatchArticles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchArticlesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ArticleServiceServer).WatchArticles(m, &grpc.GenericServerStream[WatchArticlesRequest, ArticleEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ArticleService_WatchArticlesServer = grpc.ServerStreamingServer[ArticleEvent]
+
 func _ArticleService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateArticleRequest)
 	if err := dec(in); err != nil {
@@ -244,6 +370,60 @@ func _ArticleService_Delete_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ArticleService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).Restore(ctx, req.(*RestoreArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArticleService_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArticleServiceServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ArticleService_Purge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArticleServiceServer).Purge(ctx, req.(*PurgeArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ArticleService_ServiceDesc is the grpc.ServiceDesc for ArticleService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -259,6 +439,10 @@ var ArticleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Get",
 			Handler:    _ArticleService_Get_Handler,
 		},
+		{
+			MethodName: "GetBySlug",
+			Handler:    _ArticleService_GetBySlug_Handler,
+		},
 		{
 			MethodName: "Create",
 			Handler:    _ArticleService_Create_Handler,
@@ -271,7 +455,25 @@ var ArticleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _ArticleService_Delete_Handler,
 		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _ArticleService_ListTrash_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _ArticleService_Restore_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _ArticleService_Purge_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchArticles",
+			Handler:       _ArticleService_WatchArticles_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/proto/article.proto",
 }
@@ -0,0 +1,370 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.2
+// source: api/proto/media.proto
+
+package media
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	_ "gopress/api/proto/options"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Media struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// served by the HTTP API
+	Url      string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Filename string `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	// sniffed from the content
+	ContentType   string `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Size          int64  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Sha256        string `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	CreatedAtUnix int64  `protobuf:"varint,7,opt,name=created_at_unix,json=createdAtUnix,proto3" json:"created_at_unix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Media) Reset() {
+	*x = Media{}
+	mi := &file_api_proto_media_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Media) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Media) ProtoMessage() {}
+
+func (x *Media) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_media_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Media.ProtoReflect.Descriptor instead.
+func (*Media) Descriptor() ([]byte, []int) {
+	return file_api_proto_media_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Media) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Media) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Media) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Media) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Media) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Media) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Media) GetCreatedAtUnix() int64 {
+	if x != nil {
+		return x.CreatedAtUnix
+	}
+	return 0
+}
+
+type UploadMediaInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadMediaInfo) Reset() {
+	*x = UploadMediaInfo{}
+	mi := &file_api_proto_media_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMediaInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMediaInfo) ProtoMessage() {}
+
+func (x *UploadMediaInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_media_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMediaInfo.ProtoReflect.Descriptor instead.
+func (*UploadMediaInfo) Descriptor() ([]byte, []int) {
+	return file_api_proto_media_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UploadMediaInfo) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+type UploadMediaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadMediaRequest_Info
+	//	*UploadMediaRequest_Chunk
+	Data          isUploadMediaRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadMediaRequest) Reset() {
+	*x = UploadMediaRequest{}
+	mi := &file_api_proto_media_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMediaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMediaRequest) ProtoMessage() {}
+
+func (x *UploadMediaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_media_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMediaRequest.ProtoReflect.Descriptor instead.
+func (*UploadMediaRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_media_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UploadMediaRequest) GetData() isUploadMediaRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadMediaRequest) GetInfo() *UploadMediaInfo {
+	if x != nil {
+		if x, ok := x.Data.(*UploadMediaRequest_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *UploadMediaRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*UploadMediaRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadMediaRequest_Data interface {
+	isUploadMediaRequest_Data()
+}
+
+type UploadMediaRequest_Info struct {
+	Info *UploadMediaInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type UploadMediaRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadMediaRequest_Info) isUploadMediaRequest_Data() {}
+
+func (*UploadMediaRequest_Chunk) isUploadMediaRequest_Data() {}
+
+type UploadMediaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Media         *Media                 `protobuf:"bytes,1,opt,name=media,proto3" json:"media,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadMediaResponse) Reset() {
+	*x = UploadMediaResponse{}
+	mi := &file_api_proto_media_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMediaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMediaResponse) ProtoMessage() {}
+
+func (x *UploadMediaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_media_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMediaResponse.ProtoReflect.Descriptor instead.
+func (*UploadMediaResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_media_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UploadMediaResponse) GetMedia() *Media {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+var File_api_proto_media_proto protoreflect.FileDescriptor
+
+const file_api_proto_media_proto_rawDesc = "" +
+	"\n" +
+	"\x15api/proto/media.proto\x12\x05media\x1a\x17api/proto/options.proto\"\xbc\x01\n" +
+	"\x05Media\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\tR\x06sha256\x12&\n" +
+	"\x0fcreated_at_unix\x18\a \x01(\x03R\rcreatedAtUnix\"-\n" +
+	"\x0fUploadMediaInfo\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\"b\n" +
+	"\x12UploadMediaRequest\x12,\n" +
+	"\x04info\x18\x01 \x01(\v2\x16.media.UploadMediaInfoH\x00R\x04info\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"9\n" +
+	"\x13UploadMediaResponse\x12\"\n" +
+	"\x05media\x18\x01 \x01(\v2\f.media.MediaR\x05media2n\n" +
+	"\fMediaService\x12^\n" +
+	"\vUploadMedia\x12\x19.media.UploadMediaRequest\x1a\x1a.media.UploadMediaResponse\"\x16\x88\xb5\x18\x02\x92\xb5\x18\x0earticles:write(\x01B\x17Z\x15api/proto/media;mediab\x06proto3"
+
+var (
+	file_api_proto_media_proto_rawDescOnce sync.Once
+	file_api_proto_media_proto_rawDescData []byte
+)
+
+func file_api_proto_media_proto_rawDescGZIP() []byte {
+	file_api_proto_media_proto_rawDescOnce.Do(func() {
+		file_api_proto_media_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_media_proto_rawDesc), len(file_api_proto_media_proto_rawDesc)))
+	})
+	return file_api_proto_media_proto_rawDescData
+}
+
+var file_api_proto_media_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_api_proto_media_proto_goTypes = []any{
+	(*Media)(nil),               // 0: media.Media
+	(*UploadMediaInfo)(nil),     // 1: media.UploadMediaInfo
+	(*UploadMediaRequest)(nil),  // 2: media.UploadMediaRequest
+	(*UploadMediaResponse)(nil), // 3: media.UploadMediaResponse
+}
+var file_api_proto_media_proto_depIdxs = []int32{
+	1, // 0: media.UploadMediaRequest.info:type_name -> media.UploadMediaInfo
+	0, // 1: media.UploadMediaResponse.media:type_name -> media.Media
+	2, // 2: media.MediaService.UploadMedia:input_type -> media.UploadMediaRequest
+	3, // 3: media.MediaService.UploadMedia:output_type -> media.UploadMediaResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_media_proto_init() }
+func file_api_proto_media_proto_init() {
+	if File_api_proto_media_proto != nil {
+		return
+	}
+	file_api_proto_media_proto_msgTypes[2].OneofWrappers = []any{
+		(*UploadMediaRequest_Info)(nil),
+		(*UploadMediaRequest_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_media_proto_rawDesc), len(file_api_proto_media_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_proto_media_proto_goTypes,
+		DependencyIndexes: file_api_proto_media_proto_depIdxs,
+		MessageInfos:      file_api_proto_media_proto_msgTypes,
+	}.Build()
+	File_api_proto_media_proto = out.File
+	file_api_proto_media_proto_goTypes = nil
+	file_api_proto_media_proto_depIdxs = nil
+}
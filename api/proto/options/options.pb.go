@@ -0,0 +1,174 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.2
+// source: api/proto/options.proto
+
+package options
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AuthPolicy declares who may call an RPC. Every registered method must
+// declare one, the server refuses to start otherwise.
+type AuthPolicy int32
+
+const (
+	AuthPolicy_AUTH_POLICY_UNSPECIFIED AuthPolicy = 0
+	// no credentials required
+	AuthPolicy_AUTH_POLICY_PUBLIC AuthPolicy = 1
+	// valid JWT or API key in metadata: authorization: Bearer <token>
+	AuthPolicy_AUTH_POLICY_AUTHENTICATED AuthPolicy = 2
+)
+
+// Enum value maps for AuthPolicy.
+var (
+	AuthPolicy_name = map[int32]string{
+		0: "AUTH_POLICY_UNSPECIFIED",
+		1: "AUTH_POLICY_PUBLIC",
+		2: "AUTH_POLICY_AUTHENTICATED",
+	}
+	AuthPolicy_value = map[string]int32{
+		"AUTH_POLICY_UNSPECIFIED":   0,
+		"AUTH_POLICY_PUBLIC":        1,
+		"AUTH_POLICY_AUTHENTICATED": 2,
+	}
+)
+
+func (x AuthPolicy) Enum() *AuthPolicy {
+	p := new(AuthPolicy)
+	*p = x
+	return p
+}
+
+func (x AuthPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AuthPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_options_proto_enumTypes[0].Descriptor()
+}
+
+func (AuthPolicy) Type() protoreflect.EnumType {
+	return &file_api_proto_options_proto_enumTypes[0]
+}
+
+func (x AuthPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AuthPolicy.Descriptor instead.
+func (AuthPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_options_proto_rawDescGZIP(), []int{0}
+}
+
+var file_api_proto_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*AuthPolicy)(nil),
+		Field:         50001,
+		Name:          "options.auth_policy",
+		Tag:           "varint,50001,opt,name=auth_policy,enum=options.AuthPolicy",
+		Filename:      "api/proto/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50002,
+		Name:          "options.api_key_scope",
+		Tag:           "bytes,50002,opt,name=api_key_scope",
+		Filename:      "api/proto/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// optional options.AuthPolicy auth_policy = 50001;
+	E_AuthPolicy = &file_api_proto_options_proto_extTypes[0]
+	// scope an API key or OAuth client token needs for an authenticated
+	// method, e.g. "articles:write". Methods without one accept session
+	// tokens only.
+	//
+	// optional string api_key_scope = 50002;
+	E_ApiKeyScope = &file_api_proto_options_proto_extTypes[1]
+)
+
+var File_api_proto_options_proto protoreflect.FileDescriptor
+
+const file_api_proto_options_proto_rawDesc = "" +
+	"\n" +
+	"\x17api/proto/options.proto\x12\aoptions\x1a google/protobuf/descriptor.proto*`\n" +
+	"\n" +
+	"AuthPolicy\x12\x1b\n" +
+	"\x17AUTH_POLICY_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12AUTH_POLICY_PUBLIC\x10\x01\x12\x1d\n" +
+	"\x19AUTH_POLICY_AUTHENTICATED\x10\x02:V\n" +
+	"\vauth_policy\x12\x1e.google.protobuf.MethodOptions\x18ц\x03 \x01(\x0e2\x13.options.AuthPolicyR\n" +
+	"authPolicy:D\n" +
+	"\rapi_key_scope\x12\x1e.google.protobuf.MethodOptions\x18҆\x03 \x01(\tR\vapiKeyScopeB#Z!gopress/api/proto/options;optionsb\x06proto3"
+
+var (
+	file_api_proto_options_proto_rawDescOnce sync.Once
+	file_api_proto_options_proto_rawDescData []byte
+)
+
+func file_api_proto_options_proto_rawDescGZIP() []byte {
+	file_api_proto_options_proto_rawDescOnce.Do(func() {
+		file_api_proto_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_options_proto_rawDesc), len(file_api_proto_options_proto_rawDesc)))
+	})
+	return file_api_proto_options_proto_rawDescData
+}
+
+var file_api_proto_options_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_proto_options_proto_goTypes = []any{
+	(AuthPolicy)(0),                    // 0: options.AuthPolicy
+	(*descriptorpb.MethodOptions)(nil), // 1: google.protobuf.MethodOptions
+}
+var file_api_proto_options_proto_depIdxs = []int32{
+	1, // 0: options.auth_policy:extendee -> google.protobuf.MethodOptions
+	1, // 1: options.api_key_scope:extendee -> google.protobuf.MethodOptions
+	0, // 2: options.auth_policy:type_name -> options.AuthPolicy
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	2, // [2:3] is the sub-list for extension type_name
+	0, // [0:2] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_options_proto_init() }
+func file_api_proto_options_proto_init() {
+	if File_api_proto_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_options_proto_rawDesc), len(file_api_proto_options_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   0,
+			NumExtensions: 2,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_options_proto_goTypes,
+		DependencyIndexes: file_api_proto_options_proto_depIdxs,
+		EnumInfos:         file_api_proto_options_proto_enumTypes,
+		ExtensionInfos:    file_api_proto_options_proto_extTypes,
+	}.Build()
+	File_api_proto_options_proto = out.File
+	file_api_proto_options_proto_goTypes = nil
+	file_api_proto_options_proto_depIdxs = nil
+}
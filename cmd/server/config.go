@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	articleSvc "gopress/internal/app/article"
+	authSvc "gopress/internal/app/auth"
+	mediaSvc "gopress/internal/app/media"
+	"gopress/internal/infra/oidc"
+	"gopress/internal/infra/repository"
+	"gopress/internal/transport/http/handlers"
+	"gopress/internal/transport/http/httpcache"
+	"gopress/pkg/env"
+	"gopress/pkg/password"
+)
+
+func authConfigFromEnv() (authSvc.Config, error) {
+	cfg := authSvc.Config{
+		ResetURL:  env.String("PASSWORD_RESET_URL", "http://localhost:8080/password/reset?token="),
+		VerifyURL: env.String("EMAIL_VERIFY_URL", "http://localhost:8080/email/verify?token="),
+		MFAIssuer: env.String("MFA_ISSUER", "gopress"),
+	}
+
+	var errs []error
+	var err error
+
+	cfg.ResetTokenTTL, err = env.Duration("PASSWORD_RESET_TTL", time.Hour)
+	errs = append(errs, err)
+
+	cfg.VerifyTokenTTL, err = env.Duration("EMAIL_VERIFY_TTL", 48*time.Hour)
+	errs = append(errs, err)
+	cfg.VerifyResendInterval, err = env.Duration("EMAIL_VERIFY_RESEND_INTERVAL", time.Minute)
+	errs = append(errs, err)
+	cfg.MFAChallengeTTL, err = env.Duration("MFA_CHALLENGE_TTL", 5*time.Minute)
+	errs = append(errs, err)
+	cfg.SessionCacheTTL, err = env.Duration("SESSION_CACHE_TTL", time.Minute)
+	errs = append(errs, err)
+	cfg.SessionTouchInterval, err = env.Duration("SESSION_TOUCH_INTERVAL", time.Minute)
+	errs = append(errs, err)
+	cfg.OIDCStateTTL, err = env.Duration("OIDC_STATE_TTL", 10*time.Minute)
+	errs = append(errs, err)
+	cfg.OIDCProviders, err = oidcProvidersFromEnv()
+	errs = append(errs, err)
+	cfg.OAuthCodeTTL, err = env.Duration("OAUTH_CODE_TTL", time.Minute)
+	errs = append(errs, err)
+	cfg.OAuthAccessTokenTTL, err = env.Duration("OAUTH_ACCESS_TOKEN_TTL", time.Hour)
+	errs = append(errs, err)
+	cfg.OAuthRefreshTokenTTL, err = env.Duration("OAUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	errs = append(errs, err)
+	cfg.AuditRetention, err = env.Duration("AUDIT_RETENTION", 365*24*time.Hour)
+	errs = append(errs, err)
+	cfg.AuditPurgeInterval, err = env.Duration("AUDIT_PURGE_INTERVAL", time.Hour)
+	errs = append(errs, err)
+
+	cfg.UserThrottle, err = throttlePolicyFromEnv("LOGIN_USER_", authSvc.ThrottlePolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	})
+	errs = append(errs, err)
+	cfg.IPThrottle, err = throttlePolicyFromEnv("LOGIN_IP_", authSvc.ThrottlePolicy{
+		FreeAttempts:     20,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Minute,
+		LockoutThreshold: 100,
+		LockoutDuration:  time.Hour,
+		Window:           time.Hour,
+	})
+	errs = append(errs, err)
+	cfg.ResetEmailThrottle, err = throttlePolicyFromEnv("RESET_EMAIL_", authSvc.ThrottlePolicy{
+		FreeAttempts: 3,
+		BaseDelay:    5 * time.Minute,
+		MaxDelay:     time.Hour,
+		Window:       24 * time.Hour,
+	})
+	errs = append(errs, err)
+	cfg.ResetIPThrottle, err = throttlePolicyFromEnv("RESET_IP_", authSvc.ThrottlePolicy{
+		FreeAttempts: 20,
+		BaseDelay:    time.Minute,
+		MaxDelay:     time.Hour,
+		Window:       time.Hour,
+	})
+	errs = append(errs, err)
+	cfg.ThrottleCleanupInterval, err = env.Duration("LOGIN_THROTTLE_CLEANUP_INTERVAL", time.Hour)
+	errs = append(errs, err)
+
+	cfg.Hasher, err = passwordHasherFromEnv()
+	errs = append(errs, err)
+	cfg.PasswordPolicy, err = passwordPolicyFromEnv()
+	errs = append(errs, err)
+
+	return cfg, errors.Join(errs...)
+}
+
+// oidcProvidersFromEnv builds the providers of OIDC_PROVIDERS with their
+// OIDC_<NAME>_AUTO_PROVISION and OIDC_<NAME>_LINK_BY_EMAIL settings.
+func oidcProvidersFromEnv() ([]authSvc.OIDCProviderConfig, error) {
+	configs, err := oidc.ConfigsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	providers := make([]authSvc.OIDCProviderConfig, 0, len(configs))
+	for _, c := range configs {
+		p := authSvc.OIDCProviderConfig{Provider: oidc.NewProvider(c)}
+		prefix := oidc.EnvPrefix(c.Name)
+
+		p.AutoProvision, err = env.Bool(prefix+"AUTO_PROVISION", true)
+		errs = append(errs, err)
+		p.LinkByEmail, err = env.Bool(prefix+"LINK_BY_EMAIL", false)
+		errs = append(errs, err)
+
+		providers = append(providers, p)
+	}
+	return providers, errors.Join(errs...)
+}
+
+// passwordHasherFromEnv picks the scheme for new hashes; hashes of the
+// other scheme still verify and are upgraded on the next login.
+func passwordHasherFromEnv() (*password.Hasher, error) {
+	var errs []error
+
+	p := password.DefaultArgon2Params
+	memory, err := env.Int("ARGON2_MEMORY_KIB", int(p.Memory))
+	errs = append(errs, err)
+	iterations, err := env.Int("ARGON2_ITERATIONS", int(p.Iterations))
+	errs = append(errs, err)
+	parallelism, err := env.Int("ARGON2_PARALLELISM", int(p.Parallelism))
+	errs = append(errs, err)
+	bcryptCost, err := env.Int("BCRYPT_COST", password.DefaultBcryptCost)
+	errs = append(errs, err)
+
+	if memory < 8*parallelism || iterations < 1 || parallelism < 1 || parallelism > 255 {
+		errs = append(errs, fmt.Errorf("invalid argon2 parameters m=%d t=%d p=%d", memory, iterations, parallelism))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	p.Memory = uint32(memory)
+	p.Iterations = uint32(iterations)
+	p.Parallelism = uint8(parallelism)
+
+	argon := password.NewArgon2id(p)
+	bcrypt := password.NewBcrypt(bcryptCost)
+
+	switch algo := env.String("PASSWORD_HASH", "argon2id"); algo {
+	case "argon2id":
+		return password.NewHasher(argon, bcrypt), nil
+	case "bcrypt":
+		return password.NewHasher(bcrypt, argon), nil
+	default:
+		return nil, fmt.Errorf("PASSWORD_HASH: unknown algorithm %q", algo)
+	}
+}
+
+func passwordPolicyFromEnv() (password.Policy, error) {
+	p := password.DefaultPolicy
+	var errs []error
+	var err error
+
+	p.MinLength, err = env.Int("PASSWORD_MIN_LENGTH", p.MinLength)
+	errs = append(errs, err)
+	p.MaxLength, err = env.Int("PASSWORD_MAX_LENGTH", p.MaxLength)
+	errs = append(errs, err)
+	p.MinScore, err = env.Int("PASSWORD_MIN_SCORE", p.MinScore)
+	errs = append(errs, err)
+	p.DisallowUserInfo, err = env.Bool("PASSWORD_DISALLOW_USER_INFO", p.DisallowUserInfo)
+	errs = append(errs, err)
+
+	if p.MaxLength <= 0 || p.MaxLength > password.MaxLengthLimit {
+		errs = append(errs, fmt.Errorf("PASSWORD_MAX_LENGTH: must be between 1 and %d", password.MaxLengthLimit))
+	} else if p.MinLength > p.MaxLength {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_LENGTH: must not exceed PASSWORD_MAX_LENGTH"))
+	}
+	if p.MinScore < 0 || p.MinScore > 4 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_SCORE: must be between 0 and 4"))
+	}
+
+	return p, errors.Join(errs...)
+}
+
+func throttlePolicyFromEnv(prefix string, def authSvc.ThrottlePolicy) (authSvc.ThrottlePolicy, error) {
+	p := def
+	var errs []error
+	var err error
+
+	p.FreeAttempts, err = env.Int(prefix+"FREE_ATTEMPTS", def.FreeAttempts)
+	errs = append(errs, err)
+	p.BaseDelay, err = env.Duration(prefix+"BASE_DELAY", def.BaseDelay)
+	errs = append(errs, err)
+	p.MaxDelay, err = env.Duration(prefix+"MAX_DELAY", def.MaxDelay)
+	errs = append(errs, err)
+	p.LockoutThreshold, err = env.Int(prefix+"LOCKOUT_THRESHOLD", def.LockoutThreshold)
+	errs = append(errs, err)
+	p.LockoutDuration, err = env.Duration(prefix+"LOCKOUT_DURATION", def.LockoutDuration)
+	errs = append(errs, err)
+	p.Window, err = env.Duration(prefix+"FAILURE_WINDOW", def.Window)
+	errs = append(errs, err)
+
+	return p, errors.Join(errs...)
+}
+
+func articleConfigFromEnv() (articleSvc.Config, error) {
+	var cfg articleSvc.Config
+	var errs []error
+	var err error
+
+	cfg.RequireVerifiedEmail, err = env.Bool("REQUIRE_VERIFIED_EMAIL", false)
+	errs = append(errs, err)
+	cfg.TrashRetention, err = env.Duration("ARTICLE_TRASH_RETENTION", 30*24*time.Hour)
+	errs = append(errs, err)
+	cfg.TrashPurgeInterval, err = env.Duration("ARTICLE_TRASH_PURGE_INTERVAL", time.Hour)
+	errs = append(errs, err)
+	cfg.EventRetention, err = env.Duration("ARTICLE_EVENT_RETENTION", 7*24*time.Hour)
+	errs = append(errs, err)
+	cfg.EventPurgeInterval, err = env.Duration("ARTICLE_EVENT_PURGE_INTERVAL", time.Hour)
+	errs = append(errs, err)
+	cfg.RequireVersion, err = env.Bool("ARTICLE_REQUIRE_IF_MATCH", false)
+	errs = append(errs, err)
+	cfg.RenderCacheSize, err = env.Int("ARTICLE_RENDER_CACHE_SIZE", 1000)
+	errs = append(errs, err)
+
+	return cfg, errors.Join(errs...)
+}
+
+func articleCacheFromEnv() (handlers.CacheConfig, error) {
+	var cfg handlers.CacheConfig
+	var errs []error
+
+	size, err := env.Int("ARTICLE_CACHE_SIZE", 1000)
+	errs = append(errs, err)
+	ttl, err := env.Duration("ARTICLE_CACHE_TTL", 5*time.Minute)
+	errs = append(errs, err)
+	cfg.MaxAge, err = env.Duration("ARTICLE_CACHE_MAX_AGE", time.Minute)
+	errs = append(errs, err)
+
+	// 0 turns the server-side cache off
+	if size > 0 {
+		cfg.Cache = httpcache.NewMemory(size, ttl)
+	}
+	return cfg, errors.Join(errs...)
+}
+
+func repoCacheFromEnv() (repository.CacheConfig, error) {
+	var cfg repository.CacheConfig
+	var errs []error
+	var err error
+
+	// 0 turns the read-through caches off
+	cfg.Size, err = env.Int("REPO_CACHE_SIZE", 10000)
+	errs = append(errs, err)
+	cfg.TTL, err = env.Duration("REPO_CACHE_TTL", 5*time.Minute)
+	errs = append(errs, err)
+
+	return cfg, errors.Join(errs...)
+}
+
+func mediaConfigFromEnv() (mediaSvc.Config, error) {
+	var cfg mediaSvc.Config
+	var errs []error
+
+	maxSize, err := env.Int("MEDIA_MAX_SIZE", 10<<20)
+	errs = append(errs, err)
+	quota, err := env.Int("MEDIA_QUOTA", 100<<20)
+	errs = append(errs, err)
+	cfg.PurgeInterval, err = env.Duration("MEDIA_PURGE_INTERVAL", time.Hour)
+	errs = append(errs, err)
+	if maxSize <= 0 {
+		errs = append(errs, fmt.Errorf("MEDIA_MAX_SIZE must be positive"))
+	}
+
+	cfg.MaxSize, cfg.Quota = int64(maxSize), int64(quota)
+	return cfg, errors.Join(errs...)
+}
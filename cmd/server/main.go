@@ -3,15 +3,23 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	articleSvc "gopress/internal/app/article"
 	authSvc "gopress/internal/app/auth"
+	mediaSvc "gopress/internal/app/media"
+	"gopress/internal/infra/blob"
+	"gopress/internal/infra/breach"
 	"gopress/internal/infra/database"
+	"gopress/internal/infra/mailer"
+	"gopress/internal/infra/pubsub"
+	"gopress/internal/infra/render"
 	"gopress/internal/infra/repository"
 	"gopress/internal/transport/grpc"
 	httptransport "gopress/internal/transport/http"
 	"gopress/internal/transport/http/handlers"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -19,6 +27,7 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"gopress/pkg/env"
 	jwtpkg "gopress/pkg/jwt"
 )
 
@@ -27,7 +36,8 @@ func main() {
 		fmt.Println("Warning: .env not loaded:", err)
 	}
 
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
 	db, err := database.NewDB(ctx)
 	if err != nil {
@@ -44,34 +54,134 @@ func main() {
 
 	userRepo := repository.NewUserRepo(pool)
 	articleRepo := repository.NewArticleRepo(pool)
+	articleEventRepo := repository.NewArticleEventRepo(pool)
+
+	listener := database.NewListener(pool, pubsub.ArticleEventsChannel, pubsub.SessionRevokedChannel, pubsub.UserChangedChannel)
+	go listener.Run(ctx)
+
+	repoCache, err := repoCacheFromEnv()
+	if err != nil {
+		log.Fatal("Invalid repository cache config: ", err)
+	}
+	if repoCache.Size > 0 {
+		cachedUsers := repository.NewCachedUserRepo(userRepo, repoCache)
+		cachedArticles := repository.NewCachedArticleRepo(articleRepo, repoCache)
+		go cachedUsers.Run(ctx, pubsub.NewUserBus(listener))
+		go cachedArticles.Run(ctx, pubsub.NewArticleBus(listener), pubsub.NewUserBus(listener))
+		userRepo, articleRepo = cachedUsers, cachedArticles
+	}
+
+	mail, err := mailer.FromEnv()
+	if err != nil {
+		log.Fatal("Invalid mailer config: ", err)
+	}
+	authConfig, err := authConfigFromEnv()
+	if err != nil {
+		log.Fatal("Invalid auth config: ", err)
+	}
 
-	userService := authSvc.NewService(userRepo, jwtManager)
-	articleService := articleSvc.NewService(articleRepo)
+	articleConfig, err := articleConfigFromEnv()
+	if err != nil {
+		log.Fatal("Invalid article config: ", err)
+	}
+
+	mediaConfig, err := mediaConfigFromEnv()
+	if err != nil {
+		log.Fatal("Invalid media config: ", err)
+	}
+	blobs, err := blob.FromEnv()
+	if err != nil {
+		log.Fatal("Invalid media storage config: ", err)
+	}
+
+	breached, err := breach.FromEnv()
+	if err != nil {
+		log.Fatal("Invalid breached passwords list: ", err)
+	}
+
+	auditLog := repository.NewAuditRepo(pool)
+	tx := repository.NewTransactor(pool)
+
+	userService := authSvc.NewService(
+		userRepo,
+		repository.NewSessionRepo(pool),
+		repository.NewAPIKeyRepo(pool),
+		repository.NewUserIdentityRepo(pool),
+		repository.NewOIDCStateRepo(pool),
+		repository.NewOAuthClientRepo(pool),
+		repository.NewOAuthCodeRepo(pool),
+		repository.NewOAuthGrantRepo(pool),
+		repository.NewPasswordResetRepo(pool),
+		repository.NewEmailVerificationRepo(pool),
+		repository.NewMFARepo(pool),
+		repository.NewLoginThrottleRepo(pool),
+		breached,
+		auditLog,
+		tx,
+		mail,
+		jwtManager,
+		authConfig,
+	)
+	go userService.WatchSessions(ctx, pubsub.NewSessionBus(listener))
+	go userService.RunAuditRetention(ctx)
+	go userService.RunThrottleCleanup(ctx)
+
+	articleService := articleSvc.NewService(
+		articleRepo,
+		userRepo,
+		articleEventRepo,
+		pubsub.NewArticleBus(listener),
+		render.New(),
+		auditLog,
+		tx,
+		articleConfig,
+	)
+	go articleService.RunTrashPurge(ctx)
+	go articleService.RunEventPurge(ctx)
+
+	mediaService := mediaSvc.NewService(repository.NewMediaRepo(pool), blobs, auditLog, tx, mediaConfig)
+	go mediaService.RunPurge(ctx)
 
 	authHandler := handlers.NewAuthHandler(userService)
-	articleHandler := handlers.NewArticleHandler(articleService)
+	articleCache, err := articleCacheFromEnv()
+	if err != nil {
+		log.Fatal("Invalid article cache config: ", err)
+	}
+	articleHandler := handlers.NewArticleHandler(articleService, articleCache)
+	go articleHandler.RunCacheInvalidation(ctx, pubsub.NewArticleBus(listener))
 	httpHandlers := httptransport.Handlers{
 		Auth:    authHandler,
 		Article: articleHandler,
+		Media:   handlers.NewMediaHandler(mediaService),
 	}
 
-	router := httptransport.NewRouter(httpHandlers, jwtManager)
+	trustProxy, err := env.Bool("TRUST_PROXY_HEADERS", false)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	router := httptransport.NewRouter(httpHandlers, userService, trustProxy)
 	httpServer := &http.Server{
 		Addr:         ":8080",
 		Handler:      router.Handler(),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  120 * time.Second,
+		// long-lived requests (SSE) end when ctx is cancelled on shutdown
+		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 
-	grpcPort := os.Getenv("GRPC_PORT")
-	if grpcPort == "" {
-		grpcPort = ":50051"
-	} else if grpcPort[0] != ':' {
-		grpcPort = ":" + grpcPort
+	grpcConfig, err := grpc.ConfigFromEnv()
+	if err != nil {
+		log.Fatal("Invalid gRPC config: ", err)
 	}
 
-	grpcServer, err := grpc.NewServer(userRepo, articleRepo, jwtManager, grpcPort)
+	grpcServer, err := grpc.NewServer(grpcConfig, grpc.Deps{
+		ArticleRepo:    articleRepo,
+		AuthService:    userService,
+		ArticleService: articleService,
+		MediaService:   mediaService,
+	})
 	if err != nil {
 		log.Fatal("Failed to create gRPC server:", err)
 	}
@@ -88,14 +198,26 @@ func main() {
 		}
 	}()
 
+	// cache hit/miss counters and runtime stats, keep it off public networks
+	if addr := env.String("METRICS_ADDR", ""); addr != "" {
+		go func() {
+			if err := http.ListenAndServe(addr, expvar.Handler()); err != nil {
+				log.Printf("metrics server error: %v", err)
+			}
+		}()
+		log.Println("Metrics are served on", addr+"/debug/vars")
+	}
+
 	log.Println("HTTP server is listening on", httpServer.Addr)
-	log.Println("gRPC server is listening on", grpcPort)
+	log.Println("gRPC server is listening on", grpcConfig.Addr)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	<-sigChan
 
 	log.Println("Shutting down servers...")
+	// stops the postgres listener and ends open event streams
+	cancel()
 
 	ctxShutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -0,0 +1,43 @@
+package article
+
+import (
+	"gopress/internal/domain/article"
+)
+
+// revision names one version of an article: its content never changes, so
+// neither does its HTML.
+type revision struct {
+	id, version int64
+}
+
+// summarize sets the excerpt, word count and reading time from Content.
+func (s *Service) summarize(a *article.Article) error {
+	text, err := s.renderer.Text(a.ContentFormat, a.Content)
+	if err != nil {
+		return err
+	}
+	a.Excerpt, a.WordCount, a.ReadingMinutes = article.Summarize(text)
+	return nil
+}
+
+// renderHTML sets ContentHTML, rendering every revision only once.
+func (s *Service) renderHTML(articles ...*article.Article) error {
+	for _, a := range articles {
+		if a == nil {
+			continue
+		}
+		key := revision{a.ID, a.Version}
+		if html, ok := s.rendered.Get(key); ok {
+			a.ContentHTML = html
+			continue
+		}
+
+		html, err := s.renderer.Render(a.ContentFormat, a.Content)
+		if err != nil {
+			return err
+		}
+		s.rendered.Add(key, html)
+		a.ContentHTML = html
+	}
+	return nil
+}
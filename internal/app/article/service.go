@@ -3,41 +3,156 @@ package article
 import (
 	"context"
 	"errors"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
+	"gopress/internal/app/audited"
 	"gopress/internal/app/ports"
+	"gopress/internal/app/requestinfo"
 	"gopress/internal/domain/article"
+	"gopress/internal/domain/audit"
+	"gopress/pkg/lru"
 )
 
 var (
-	ErrNotFound    = errors.New("article not found")
-	ErrInvalidData = errors.New("invalid data")
+	ErrNotFound         = errors.New("article not found")
+	ErrInvalidData      = errors.New("invalid data")
+	ErrEmailNotVerified = errors.New("email not verified")
+	// ErrVersionMismatch means the article changed since the caller read it.
+	ErrVersionMismatch = errors.New("article version mismatch")
+	ErrVersionRequired = errors.New("article version required")
 )
 
+type Config struct {
+	// RequireVerifiedEmail forbids unverified accounts to create articles.
+	RequireVerifiedEmail bool
+
+	// TrashRetention is how long deleted articles stay restorable, 0 keeps
+	// them until purged by hand. RunTrashPurge checks every TrashPurgeInterval.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// EventRetention is how long article events stay in the log for
+	// resuming watchers, 0 keeps them forever. RunEventPurge checks every
+	// EventPurgeInterval.
+	EventRetention     time.Duration
+	EventPurgeInterval time.Duration
+
+	// RequireVersion makes Update and Delete fail with ErrVersionRequired
+	// unless the caller names the version it changes.
+	RequireVersion bool
+
+	// RenderCacheSize is how many rendered revisions are kept in memory.
+	RenderCacheSize int
+}
+
 type Service struct {
-	repo ports.ArticleRepo
+	repo   ports.ArticleRepo
+	users  ports.UserRepo
+	events ports.ArticleEventRepo
+	bus    ports.ArticleEventBus
+	audit  ports.AuditLog
+	tx     ports.Transactor
+	cfg    Config
+
+	renderer ports.ContentRenderer
+	rendered *lru.Cache[revision, string]
 }
 
-func NewService(repo ports.ArticleRepo) *Service {
-	return &Service{repo: repo}
+func NewService(
+	repo ports.ArticleRepo,
+	users ports.UserRepo,
+	events ports.ArticleEventRepo,
+	bus ports.ArticleEventBus,
+	renderer ports.ContentRenderer,
+	auditLog ports.AuditLog,
+	tx ports.Transactor,
+	cfg Config,
+) *Service {
+	return &Service{
+		repo:   repo,
+		users:  users,
+		events: events,
+		bus:    bus,
+		audit:  auditLog,
+		tx:     tx,
+		cfg:    cfg,
+
+		renderer: renderer,
+		rendered: lru.New[revision, string](cfg.RenderCacheSize, 0),
+	}
 }
 
-func (s *Service) Create(ctx context.Context, userID uuid.UUID, title, content string) error {
-	if title == "" || content == "" {
-		return ErrInvalidData
+// Create stores a new article; an empty format means Markdown.
+func (s *Service) Create(ctx context.Context, userID uuid.UUID, title, content string, format article.Format) (*article.Article, error) {
+	if format == "" {
+		format = article.FormatMarkdown
+	}
+	if title == "" || content == "" || !article.ValidFormat(format) {
+		return nil, ErrInvalidData
+	}
+
+	if s.cfg.RequireVerifiedEmail {
+		u, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if u == nil || !u.EmailVerified() {
+			return nil, ErrEmailNotVerified
+		}
 	}
 
 	a := &article.Article{
-		Title:    title,
-		Content:  content,
-		AuthorID: userID,
+		Title:         title,
+		Content:       content,
+		ContentFormat: format,
+		AuthorID:      userID,
+	}
+	if err := s.summarize(a); err != nil {
+		return nil, err
 	}
 
-	return s.repo.Create(ctx, a)
+	ev := s.auditEvent(ctx, audit.ActionArticleCreate, userID)
+	err := s.withAudit(ctx, ev, func(ctx context.Context) error {
+		var err error
+		if a.Slug, err = s.uniqueSlug(ctx, title, 0); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, a); err != nil {
+			return err
+		}
+		ev.Target, ev.After = articleTarget(a.ID), snapshot(a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
 }
 
-func (s *Service) List(ctx context.Context, limit, offset int) ([]*article.Article, error) {
-	return s.repo.List(ctx, limit, offset)
+// View selects how much of each article List returns.
+type View int
+
+const (
+	ViewFull View = iota
+	// ViewSummary leaves out Content and ContentHTML, for index pages.
+	ViewSummary
+)
+
+func (s *Service) List(ctx context.Context, limit, offset int, view View) ([]*article.Article, error) {
+	if view == ViewSummary {
+		return s.repo.ListSummaries(ctx, limit, offset)
+	}
+
+	articles, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.renderHTML(articles...); err != nil {
+		return nil, err
+	}
+	return articles, nil
 }
 
 func (s *Service) GetByID(ctx context.Context, id int64) (*article.Article, error) {
@@ -48,31 +163,172 @@ func (s *Service) GetByID(ctx context.Context, id int64) (*article.Article, erro
 	if a == nil {
 		return nil, ErrNotFound
 	}
+	if err := s.renderHTML(a); err != nil {
+		return nil, err
+	}
 	return a, nil
 }
 
-func (s *Service) Update(ctx context.Context, userID uuid.UUID, id int64, title, content string) error {
+// Update replaces the editable fields of the article if it is still at
+// version, whatever its version if that is 0, and returns it updated. An
+// empty format keeps the current one.
+func (s *Service) Update(ctx context.Context, userID uuid.UUID, id int64, title, content string, format article.Format, version int64) (*article.Article, error) {
 	if title == "" || content == "" {
-		return ErrInvalidData
+		return nil, ErrInvalidData
+	}
+	p := article.Patch{Title: &title, Content: &content}
+	if format != "" {
+		p.ContentFormat = &format
+	}
+	return s.Patch(ctx, userID, id, p, version)
+}
+
+// Patch changes the fields set in p and keeps the others; version works as
+// in Update. An empty patch changes nothing.
+func (s *Service) Patch(ctx context.Context, userID uuid.UUID, id int64, p article.Patch, version int64) (*article.Article, error) {
+	if err := s.checkVersion(version); err != nil {
+		return nil, err
+	}
+	if p.Empty() {
+		return s.ownedArticle(ctx, userID, id, version)
 	}
 
-	ok, err := s.repo.UpdateOwned(ctx, id, userID, title, content)
+	var after *article.Article
+	ev := s.auditEvent(ctx, audit.ActionArticleUpdate, userID)
+	err := s.withAudit(ctx, ev, func(ctx context.Context) error {
+		before, err := s.ownedArticle(ctx, userID, id, version)
+		if err != nil {
+			return err
+		}
+
+		patched := *before
+		p.Apply(&patched)
+		if patched.Title == "" || patched.Content == "" || !article.ValidFormat(patched.ContentFormat) {
+			return ErrInvalidData
+		}
+		if patched.Content != before.Content || patched.ContentFormat != before.ContentFormat {
+			if err := s.summarize(&patched); err != nil {
+				return err
+			}
+		}
+		// a new slug only if the title no longer gives the current one
+		if patched.Title != before.Title && !article.SlugOf(before.Slug, article.Slugify(patched.Title)) {
+			if patched.Slug, err = s.uniqueSlug(ctx, patched.Title, id); err != nil {
+				return err
+			}
+		}
+
+		// the version read above: a change in between is a conflict, not
+		// something to merge into
+		ok, err := s.repo.UpdateOwned(ctx, &patched, before.Version)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVersionMismatch
+		}
+		if patched.Slug != before.Slug {
+			if err := s.repo.AddSlugRedirect(ctx, id, before.Slug, patched.Slug); err != nil {
+				return err
+			}
+		}
+
+		if after, err = s.repo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		ev.Target, ev.Before, ev.After = articleTarget(id), snapshot(before), snapshot(after)
+		return nil
+	})
 	if err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// Delete moves the article to the trash, see Restore and Purge. version
+// works as in Update.
+func (s *Service) Delete(ctx context.Context, userID uuid.UUID, id int64, version int64) error {
+	if err := s.checkVersion(version); err != nil {
 		return err
 	}
-	if !ok {
-		return ErrNotFound
+
+	ev := s.auditEvent(ctx, audit.ActionArticleDelete, userID)
+	return s.withAudit(ctx, ev, func(ctx context.Context) error {
+		before, err := s.ownedArticle(ctx, userID, id, version)
+		if err != nil {
+			return err
+		}
+
+		ok, err := s.repo.DeleteOwned(ctx, id, userID, version)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVersionMismatch
+		}
+
+		ev.Target, ev.Before = articleTarget(id), snapshot(before)
+		return nil
+	})
+}
+
+func (s *Service) checkVersion(version int64) error {
+	if version < 0 {
+		return ErrInvalidData
+	}
+	if version == 0 && s.cfg.RequireVersion {
+		return ErrVersionRequired
 	}
 	return nil
 }
 
-func (s *Service) Delete(ctx context.Context, userID uuid.UUID, id int64) error {
-	ok, err := s.repo.DeleteOwned(ctx, id, userID)
+// ownedArticle returns the article if userID wrote it, ErrNotFound
+// otherwise, and ErrVersionMismatch if it is not at version (unless 0).
+func (s *Service) ownedArticle(ctx context.Context, userID uuid.UUID, id int64, version int64) (*article.Article, error) {
+	a, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if !ok {
-		return ErrNotFound
+	if a == nil || a.AuthorID != userID {
+		return nil, ErrNotFound
+	}
+	if version != 0 && a.Version != version {
+		return nil, ErrVersionMismatch
+	}
+	return a, nil
+}
+
+func (s *Service) auditEvent(ctx context.Context, action string, actorID uuid.UUID) *audit.Event {
+	info := requestinfo.From(ctx)
+	return &audit.Event{
+		Action:    action,
+		ActorID:   &actorID,
+		IP:        info.IP,
+		RequestID: info.RequestID,
+	}
+}
+
+// withAudit runs fn and records e as audited.Run does.
+func (s *Service) withAudit(ctx context.Context, e *audit.Event, fn func(ctx context.Context) error) error {
+	return audited.Run(ctx, s.tx, s.audit, e, fn)
+}
+
+func articleTarget(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// snapshot is the state of an article kept in audit events.
+func snapshot(a *article.Article) map[string]any {
+	if a == nil {
+		return nil
+	}
+	return map[string]any{
+		"id":             a.ID,
+		"title":          a.Title,
+		"slug":           a.Slug,
+		"content":        a.Content,
+		"content_format": a.ContentFormat,
+		"author_id":      a.AuthorID,
+		"version":        a.Version,
 	}
-	return nil
 }
@@ -0,0 +1,51 @@
+package article
+
+import (
+	"context"
+	"strconv"
+
+	"gopress/internal/domain/article"
+)
+
+// ResolveSlug returns the article a current or former slug names and its
+// current slug; callers redirect when the two differ.
+func (s *Service) ResolveSlug(ctx context.Context, slug string) (int64, string, error) {
+	id, current, err := s.repo.ResolveSlug(ctx, slug)
+	if err != nil {
+		return 0, "", err
+	}
+	if id == 0 {
+		return 0, "", ErrNotFound
+	}
+	return id, current, nil
+}
+
+// GetBySlug returns the article a current or former slug names.
+func (s *Service) GetBySlug(ctx context.Context, slug string) (*article.Article, error) {
+	id, _, err := s.ResolveSlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// uniqueSlug makes the slug of a title that no other article uses or used,
+// adding "-2", "-3"... when needed. id is the article itself, 0 for a new
+// one. Call it in the transaction that stores the slug.
+func (s *Service) uniqueSlug(ctx context.Context, title string, id int64) (string, error) {
+	base := article.Slugify(title)
+	slugs, err := s.repo.TakenSlugs(ctx, base, id)
+	if err != nil {
+		return "", err
+	}
+
+	taken := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		taken[slug] = true
+	}
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	return slug, nil
+}
@@ -0,0 +1,96 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/periodic"
+	"gopress/internal/domain/article"
+	"gopress/internal/domain/audit"
+)
+
+// ListTrash returns the user's deleted articles, most recently deleted first.
+func (s *Service) ListTrash(ctx context.Context, userID uuid.UUID) ([]*article.Article, error) {
+	articles, err := s.repo.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.renderHTML(articles...); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// Restore takes an article of the user out of the trash.
+func (s *Service) Restore(ctx context.Context, userID uuid.UUID, id int64) error {
+	ev := s.auditEvent(ctx, audit.ActionArticleRestore, userID)
+	return s.withAudit(ctx, ev, func(ctx context.Context) error {
+		ok, err := s.repo.RestoreOwned(ctx, id, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotFound
+		}
+
+		after, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		ev.Target, ev.After = articleTarget(id), snapshot(after)
+		return nil
+	})
+}
+
+// Purge permanently deletes an article of the user from the trash.
+func (s *Service) Purge(ctx context.Context, userID uuid.UUID, id int64) error {
+	ev := s.auditEvent(ctx, audit.ActionArticlePurge, userID)
+	return s.withAudit(ctx, ev, func(ctx context.Context) error {
+		before, err := s.repo.GetTrashed(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil || before.AuthorID != userID {
+			return ErrNotFound
+		}
+
+		ok, err := s.repo.PurgeOwned(ctx, id, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotFound
+		}
+
+		ev.Target, ev.Before = articleTarget(id), snapshot(before)
+		return nil
+	})
+}
+
+// RunTrashPurge permanently deletes articles that have been in the trash
+// longer than TrashRetention, every TrashPurgeInterval until ctx is done.
+// It returns at once when retention is off.
+func (s *Service) RunTrashPurge(ctx context.Context) {
+	if s.cfg.TrashRetention <= 0 {
+		return
+	}
+	periodic.Run(ctx, "trash purge", "articles", s.cfg.TrashPurgeInterval, s.purgeExpiredTrash)
+}
+
+func (s *Service) purgeExpiredTrash(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-s.cfg.TrashRetention)
+
+	var n int64
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if n, err = s.repo.PurgeTrash(ctx, before); err != nil || n == 0 {
+			return err
+		}
+		return s.audit.Record(ctx, &audit.Event{
+			Action:  audit.ActionTrashExpire,
+			Details: map[string]any{"articles": n, "deleted_before": before},
+		})
+	})
+	return n, err
+}
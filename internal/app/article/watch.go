@@ -0,0 +1,107 @@
+package article
+
+import (
+	"context"
+	"time"
+
+	"gopress/internal/app/periodic"
+	"gopress/internal/domain/article"
+)
+
+const watchBatch = 100
+
+// Watch calls fn for every article event after lastEventID, first replaying
+// stored events and then following live ones, until ctx is done or fn
+// returns an error. lastEventID <= 0 means only new events. Event IDs are
+// taken at commit, in commit order, so a later commit never brings a
+// smaller ID. Events older than EventRetention cannot be replayed.
+func (s *Service) Watch(ctx context.Context, lastEventID int64, fn func(*article.Event) error) error {
+	if lastEventID <= 0 {
+		head, err := s.events.LastID(ctx)
+		if err != nil {
+			return err
+		}
+		lastEventID = head
+	}
+
+	for {
+		last, err := s.watchOnce(ctx, lastEventID, fn)
+		if err != nil {
+			return err
+		}
+		lastEventID = last
+	}
+}
+
+// watchOnce subscribes, catches up from the event log and follows the
+// subscription until the bus closes it.
+func (s *Service) watchOnce(ctx context.Context, lastEventID int64, fn func(*article.Event) error) (int64, error) {
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// subscribe before reading the log so nothing falls in between
+	live, err := s.bus.Subscribe(subCtx)
+	if err != nil {
+		return lastEventID, err
+	}
+
+	for {
+		events, err := s.events.ListSince(ctx, lastEventID, watchBatch)
+		if err != nil {
+			return lastEventID, err
+		}
+		for _, ev := range events {
+			if err := s.emit(ctx, ev, fn); err != nil {
+				return lastEventID, err
+			}
+			lastEventID = ev.ID
+		}
+		if len(events) < watchBatch {
+			break
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return lastEventID, ctx.Err()
+		case ev, ok := <-live:
+			if !ok {
+				return lastEventID, nil
+			}
+			if ev.ID <= lastEventID {
+				continue
+			}
+			if err := s.emit(ctx, ev, fn); err != nil {
+				return lastEventID, err
+			}
+			lastEventID = ev.ID
+		}
+	}
+}
+
+func (s *Service) emit(ctx context.Context, ev *article.Event, fn func(*article.Event) error) error {
+	if ev.Type != article.EventDeleted {
+		a, err := s.repo.GetByID(ctx, ev.ArticleID)
+		if err != nil {
+			return err
+		}
+		if err := s.renderHTML(a); err != nil {
+			return err
+		}
+		ev.Article = a
+	}
+	return fn(ev)
+}
+
+// RunEventPurge deletes article events older than EventRetention, every
+// EventPurgeInterval until ctx is done. It returns at once when retention
+// is off.
+func (s *Service) RunEventPurge(ctx context.Context) {
+	if s.cfg.EventRetention <= 0 {
+		return
+	}
+	periodic.Run(ctx, "article event purge", "events", s.cfg.EventPurgeInterval, func(ctx context.Context) (int64, error) {
+		return s.events.DeleteBefore(ctx, time.Now().Add(-s.cfg.EventRetention))
+	})
+}
@@ -0,0 +1,39 @@
+// Package audited stores changes together with the audit events that
+// describe them.
+package audited
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/audit"
+)
+
+// ErrNotRecorded means the change was rolled back because its event could
+// not be stored or the transaction not committed.
+var ErrNotRecorded = errors.New("audit event not recorded")
+
+// Run runs fn and records e in one transaction, so there is no change
+// without its event. fn gets the transaction's ctx and may fill in e; its
+// errors are returned as they are. Any other failure is logged and wraps
+// both ErrNotRecorded and the cause.
+func Run(ctx context.Context, tx ports.Transactor, auditLog ports.AuditLog, e *audit.Event, fn func(ctx context.Context) error) error {
+	var fnErr error
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		if fnErr = fn(ctx); fnErr != nil {
+			return fnErr
+		}
+		return auditLog.Record(ctx, e)
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		log.Printf("audit %s: %v", e.Action, err)
+		return fmt.Errorf("%w: %w", ErrNotRecorded, err)
+	}
+	return nil
+}
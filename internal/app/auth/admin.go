@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+	"gopress/pkg/token"
+)
+
+const (
+	defaultUserListLimit = 50
+	maxUserListLimit     = 200
+	maxSuspendReasonLen  = 500
+)
+
+var (
+	ErrForbidden   = errors.New("forbidden")
+	ErrSuspended   = errors.New("account suspended")
+	ErrSelfAction  = errors.New("not allowed on your own account")
+	ErrInvalidRole = errors.New("invalid role")
+)
+
+// Admin operations take the caller's ID and check its role on every call,
+// so a demoted admin loses access at once.
+
+func (s *Service) requireAdmin(ctx context.Context, actorID uuid.UUID) error {
+	u, err := s.repo.GetByID(ctx, actorID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if u == nil || !u.IsAdmin() || u.Suspended() {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// adminTarget checks the caller and loads the user it acts on, who must
+// not be the caller.
+func (s *Service) adminTarget(ctx context.Context, actorID, targetID uuid.UUID) (*user.User, error) {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	if actorID == targetID {
+		return nil, ErrSelfAction
+	}
+	return s.GetMe(ctx, targetID)
+}
+
+func (s *Service) ListUsers(ctx context.Context, actorID uuid.UUID, f user.ListFilter) ([]*user.User, error) {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	if f.Role != "" && !user.ValidRole(f.Role) {
+		return nil, ErrInvalidRole
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultUserListLimit
+	}
+	f.Limit = min(f.Limit, maxUserListLimit)
+	f.Offset = max(f.Offset, 0)
+	f.Query = strings.TrimSpace(f.Query)
+
+	users, err := s.repo.List(ctx, f)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return users, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, actorID, id uuid.UUID) (*user.User, error) {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	return s.GetMe(ctx, id)
+}
+
+// SuspendUser blocks the account: its sessions are revoked and its API
+// keys and OAuth tokens are refused until UnsuspendUser.
+func (s *Service) SuspendUser(ctx context.Context, actorID, id uuid.UUID, reason string) (*user.User, error) {
+	reason = strings.TrimSpace(reason)
+	if utf8.RuneCountInString(reason) > maxSuspendReasonLen {
+		return nil, ErrInvalidData
+	}
+
+	target, err := s.adminTarget(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := adminEvent(ctx, audit.ActionUserSuspend, actorID, target)
+	ev.Details = map[string]any{"reason": reason}
+
+	var u *user.User
+	err = s.withAudit(ctx, ev, func(ctx context.Context) error {
+		now := time.Now()
+		if u, err = s.setSuspended(ctx, target.ID, &now, reason); err != nil {
+			return err
+		}
+		ev.After = userSnapshot(u)
+		return s.RevokeAllSessions(ctx, u.ID, uuid.Nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *Service) UnsuspendUser(ctx context.Context, actorID, id uuid.UUID) (*user.User, error) {
+	target, err := s.adminTarget(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := adminEvent(ctx, audit.ActionUserUnsuspend, actorID, target)
+
+	var u *user.User
+	err = s.withAudit(ctx, ev, func(ctx context.Context) error {
+		if u, err = s.setSuspended(ctx, target.ID, nil, ""); err != nil {
+			return err
+		}
+		ev.After = userSnapshot(u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *Service) setSuspended(ctx context.Context, id uuid.UUID, at *time.Time, reason string) (*user.User, error) {
+	u, err := s.repo.SetSuspended(ctx, id, at, reason)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// ForcePasswordReset makes the current password unusable, logs the user
+// out everywhere and emails a reset link.
+func (s *Service) ForcePasswordReset(ctx context.Context, actorID, id uuid.UUID) error {
+	u, err := s.adminTarget(ctx, actorID, id)
+	if err != nil {
+		return err
+	}
+
+	plain, _, err := token.Generate()
+	if err != nil {
+		return ErrInternalError
+	}
+	hashed, err := s.hasher.Hash(plain)
+	if err != nil {
+		return ErrHashPassword
+	}
+
+	err = s.withAudit(ctx, adminEvent(ctx, audit.ActionUserPasswordReset, actorID, u), func(ctx context.Context) error {
+		// bumps token_version: all issued tokens stop working
+		if _, err := s.repo.UpdatePassword(ctx, u.ID, hashed); err != nil {
+			return ErrInternalError
+		}
+		return s.RevokeAllSessions(ctx, u.ID, uuid.Nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sendResetLink(ctx, u,
+		"an administrator has reset the password of your account, you have to choose a new one.",
+		"Contact the site administrators if you have questions.")
+}
+
+func (s *Service) SetUserRole(ctx context.Context, actorID, id uuid.UUID, role string) (*user.User, error) {
+	if !user.ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+
+	target, err := s.adminTarget(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+	if target.Role == role {
+		return target, nil
+	}
+
+	ev := adminEvent(ctx, audit.ActionUserRoleChange, actorID, target)
+
+	var u *user.User
+	err = s.withAudit(ctx, ev, func(ctx context.Context) error {
+		var err error
+		u, err = s.repo.SetRole(ctx, target.ID, role)
+		if err != nil {
+			return ErrInternalError
+		}
+		if u == nil {
+			return ErrUserNotFound
+		}
+		ev.After = userSnapshot(u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// DeleteUser deletes the account; like DeleteAccount it trashes its articles.
+func (s *Service) DeleteUser(ctx context.Context, actorID, id uuid.UUID) error {
+	u, err := s.adminTarget(ctx, actorID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.withAudit(ctx, adminEvent(ctx, audit.ActionUserDelete, actorID, u), func(ctx context.Context) error {
+		if err := s.RevokeAllSessions(ctx, u.ID, uuid.Nil); err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, u.ID); err != nil {
+			return ErrInternalError
+		}
+		return nil
+	})
+}
+
+// adminEvent starts the audit event of an admin action with the target's
+// state before it.
+func adminEvent(ctx context.Context, action string, actorID uuid.UUID, target *user.User) *audit.Event {
+	ev := auditEvent(ctx, action, &actorID, target.ID.String())
+	ev.Before = userSnapshot(target)
+	return ev
+}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+)
+
+func (f *fixture) addAdmin(t *testing.T, username string) *user.User {
+	t.Helper()
+	u := f.addUser(t, username)
+	f.users.byID[u.ID].Role = user.RoleAdmin
+	u.Role = user.RoleAdmin
+	return u
+}
+
+// adminOps call every admin operation by actor on target.
+var adminOps = []struct {
+	name string
+	call func(s *Service, actor, target uuid.UUID) error
+}{
+	{"ListUsers", func(s *Service, actor, _ uuid.UUID) error {
+		_, err := s.ListUsers(context.Background(), actor, user.ListFilter{})
+		return err
+	}},
+	{"GetUser", func(s *Service, actor, target uuid.UUID) error {
+		_, err := s.GetUser(context.Background(), actor, target)
+		return err
+	}},
+	{"SuspendUser", func(s *Service, actor, target uuid.UUID) error {
+		_, err := s.SuspendUser(context.Background(), actor, target, "spam")
+		return err
+	}},
+	{"UnsuspendUser", func(s *Service, actor, target uuid.UUID) error {
+		_, err := s.UnsuspendUser(context.Background(), actor, target)
+		return err
+	}},
+	{"ForcePasswordReset", func(s *Service, actor, target uuid.UUID) error {
+		return s.ForcePasswordReset(context.Background(), actor, target)
+	}},
+	{"SetUserRole", func(s *Service, actor, target uuid.UUID) error {
+		_, err := s.SetUserRole(context.Background(), actor, target, user.RoleAdmin)
+		return err
+	}},
+	{"DeleteUser", func(s *Service, actor, target uuid.UUID) error {
+		return s.DeleteUser(context.Background(), actor, target)
+	}},
+	{"ListAuditEvents", func(s *Service, actor, _ uuid.UUID) error {
+		_, err := s.ListAuditEvents(context.Background(), actor, audit.Filter{})
+		return err
+	}},
+}
+
+func TestAdminOperationsRequireAdmin(t *testing.T) {
+	actors := []struct {
+		name  string
+		actor func(t *testing.T, f *fixture) uuid.UUID
+	}{
+		{"user", func(t *testing.T, f *fixture) uuid.UUID { return f.addUser(t, "mallory").ID }},
+		{"suspended admin", func(t *testing.T, f *fixture) uuid.UUID {
+			u := f.addAdmin(t, "mallory")
+			now := time.Now()
+			f.users.byID[u.ID].SuspendedAt = &now
+			return u.ID
+		}},
+		{"deleted admin", func(t *testing.T, f *fixture) uuid.UUID {
+			u := f.addAdmin(t, "mallory")
+			delete(f.users.byID, u.ID)
+			return u.ID
+		}},
+	}
+	for _, a := range actors {
+		for _, op := range adminOps {
+			t.Run(a.name+"/"+op.name, func(t *testing.T) {
+				f := newFixture(t, Config{})
+				target := f.addUser(t, "alice")
+				before := *f.users.byID[target.ID]
+
+				if err := op.call(f.svc, a.actor(t, f), target.ID); !errors.Is(err, ErrForbidden) {
+					t.Errorf("%s = %v, want ErrForbidden", op.name, err)
+				}
+				if after := f.users.byID[target.ID]; after == nil || after.Role != before.Role ||
+					after.SuspendedAt != nil || after.Password != before.Password {
+					t.Errorf("target changed: %+v", after)
+				}
+				if len(f.audit.events) != 0 {
+					t.Errorf("audit = %q", f.audit.actions())
+				}
+			})
+		}
+	}
+}
+
+func TestAdminOperationsOnSelf(t *testing.T) {
+	for _, op := range adminOps {
+		switch op.name {
+		case "ListUsers", "GetUser", "ListAuditEvents":
+			continue
+		}
+		t.Run(op.name, func(t *testing.T) {
+			f := newFixture(t, Config{})
+			admin := f.addAdmin(t, "root")
+			if err := op.call(f.svc, admin.ID, admin.ID); !errors.Is(err, ErrSelfAction) {
+				t.Errorf("%s = %v, want ErrSelfAction", op.name, err)
+			}
+		})
+	}
+}
+
+func TestAdminOperationsOnUnknownUser(t *testing.T) {
+	for _, op := range adminOps {
+		switch op.name {
+		case "ListUsers", "ListAuditEvents":
+			continue
+		}
+		t.Run(op.name, func(t *testing.T) {
+			f := newFixture(t, Config{})
+			admin := f.addAdmin(t, "root")
+			if err := op.call(f.svc, admin.ID, uuid.New()); !errors.Is(err, ErrUserNotFound) {
+				t.Errorf("%s = %v, want ErrUserNotFound", op.name, err)
+			}
+		})
+	}
+}
+
+func TestDemotedAdminLosesAccess(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	root, alice := f.addAdmin(t, "root"), f.addUser(t, "alice")
+
+	if _, err := f.svc.SetUserRole(ctx, root.ID, alice.ID, user.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.ListUsers(ctx, alice.ID, user.ListFilter{}); err != nil {
+		t.Fatalf("promoted admin: %v", err)
+	}
+
+	if _, err := f.svc.SetUserRole(ctx, root.ID, alice.ID, user.RoleUser); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.ListUsers(ctx, alice.ID, user.ListFilter{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("demoted admin = %v, want ErrForbidden", err)
+	}
+
+	if _, err := f.svc.SetUserRole(ctx, root.ID, alice.ID, "owner"); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("unknown role = %v, want ErrInvalidRole", err)
+	}
+	if _, err := f.svc.ListUsers(ctx, root.ID, user.ListFilter{Role: "owner"}); !errors.Is(err, ErrInvalidRole) {
+		t.Errorf("listing an unknown role = %v, want ErrInvalidRole", err)
+	}
+
+	want := []string{audit.ActionUserRoleChange, audit.ActionUserRoleChange}
+	if got := f.audit.actions(); !slices.Equal(got, want) {
+		t.Errorf("audit = %q, want %q", got, want)
+	}
+	for _, e := range f.audit.events {
+		if e.ActorID == nil || *e.ActorID != root.ID || e.Target != alice.ID.String() || e.Before == nil || e.After == nil {
+			t.Errorf("event = %+v", e)
+		}
+	}
+}
+
+func TestSuspendUser(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	root, alice := f.addAdmin(t, "root"), f.addUser(t, "alice")
+	token := f.login(t, alice)
+
+	if _, err := f.svc.SuspendUser(ctx, root.ID, alice.ID, strings.Repeat("x", maxSuspendReasonLen+1)); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("long reason = %v, want ErrInvalidData", err)
+	}
+	u, err := f.svc.SuspendUser(ctx, root.ID, alice.ID, " spam ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.Suspended() || u.SuspendReason != "spam" {
+		t.Errorf("suspended user = %+v", u)
+	}
+
+	if _, err := f.svc.Authenticate(ctx, token); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("session of a suspended user = %v, want ErrUnauthorized", err)
+	}
+	if _, err := f.svc.Login(ctx, "alice", testPassword); !errors.Is(err, ErrSuspended) {
+		t.Errorf("login of a suspended user = %v, want ErrSuspended", err)
+	}
+
+	if _, err := f.svc.UnsuspendUser(ctx, root.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	f.login(t, alice)
+
+	want := []string{audit.ActionLogin, audit.ActionUserSuspend, audit.ActionUserUnsuspend, audit.ActionLogin}
+	if got := f.audit.actions(); !slices.Equal(got, want) {
+		t.Errorf("audit = %q, want %q", got, want)
+	}
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{ResetURL: "https://gopress.example/reset?token=", ResetTokenTTL: time.Hour})
+	root, alice := f.addAdmin(t, "root"), f.addUser(t, "alice")
+	token := f.login(t, alice)
+
+	if err := f.svc.ForcePasswordReset(ctx, root.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.Authenticate(ctx, token); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("session after a forced reset = %v, want ErrUnauthorized", err)
+	}
+	if _, err := f.svc.Login(ctx, "alice", testPassword); !errors.Is(err, ErrInvalidData) {
+		t.Errorf("old password = %v, want ErrInvalidData", err)
+	}
+
+	mail := <-f.mailer.sent
+	_, resetToken, ok := strings.Cut(mail.Body, f.svc.cfg.ResetURL)
+	resetToken, _, _ = strings.Cut(resetToken, "\n")
+	if mail.To != alice.Email || !ok || resetToken == "" {
+		t.Fatalf("mail = %+v", mail)
+	}
+	if err := f.svc.ResetPassword(ctx, resetToken, "a new passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.Login(ctx, "alice", "a new passphrase"); err != nil {
+		t.Errorf("login with the new password = %v", err)
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	root, alice := f.addAdmin(t, "root"), f.addUser(t, "alice")
+	token := f.login(t, alice)
+
+	if err := f.svc.DeleteUser(ctx, root.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.users.byID[alice.ID]; ok {
+		t.Error("user not deleted")
+	}
+	if _, err := f.svc.Authenticate(ctx, token); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("session of a deleted user = %v, want ErrUnauthorized", err)
+	}
+	last := f.audit.events[len(f.audit.events)-1]
+	if last.Action != audit.ActionUserDelete || *last.ActorID != root.ID || last.Before["username"] != "alice" {
+		t.Errorf("event = %+v", last)
+	}
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+	"gopress/pkg/token"
+)
+
+// APIKeyPrefix starts every API key so secret scanners can spot leaked keys.
+const APIKeyPrefix = "gpat_"
+
+const (
+	apiKeyRandomBytes = 20
+	apiKeyDisplayLen  = len(APIKeyPrefix) + 8
+	maxAPIKeyNameLen  = 100
+	apiKeyTouchPeriod = time.Minute
+	maxAPIKeysPerUser = 50
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrTooManyAPIKeys = errors.New("too many api keys")
+)
+
+// NewAPIKey is returned once on creation, the plain key is not stored.
+type NewAPIKey struct {
+	Key    string
+	APIKey *user.APIKey
+}
+
+// CreateAPIKey issues a personal API key with the given scopes. expiresAt
+// nil means the key doesn't expire.
+func (s *Service) CreateAPIKey(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*NewAPIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLen || len(scopes) == 0 {
+		return nil, ErrInvalidData
+	}
+	for _, sc := range scopes {
+		if !user.ValidScope(sc) {
+			return nil, ErrInvalidData
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidData
+	}
+
+	existing, err := s.apiKeys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return nil, ErrTooManyAPIKeys
+	}
+
+	b := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, ErrInternalError
+	}
+	plain := APIKeyPrefix + hex.EncodeToString(b)
+
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	k := &user.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    plain[:apiKeyDisplayLen],
+		KeyHash:   token.Hash(plain),
+		Scopes:    slices.Compact(scopes),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.apiKeys.Create(ctx, k); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return &NewAPIKey{Key: plain, APIKey: k}, nil
+}
+
+func (s *Service) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]user.APIKey, error) {
+	keys, err := s.apiKeys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return keys, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, userID, id uuid.UUID) error {
+	ok, err := s.apiKeys.Revoke(ctx, userID, id)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *Service) authenticateAPIKey(ctx context.Context, plain string) (*Identity, error) {
+	k, err := s.apiKeys.GetByHash(ctx, token.Hash(plain))
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	now := time.Now()
+	if !k.Active(now) {
+		return nil, ErrUnauthorized
+	}
+
+	u, err := s.repo.GetByID(ctx, k.UserID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if u == nil || u.Suspended() {
+		return nil, ErrUnauthorized
+	}
+
+	if err := s.apiKeys.Touch(ctx, k.ID, now, apiKeyTouchPeriod); err != nil {
+		log.Printf("touch api key %s: %v", k.ID, err)
+	}
+
+	return &Identity{
+		UserID:   u.ID,
+		Username: u.Username,
+		APIKeyID: k.ID,
+		Scopes:   k.Scopes,
+	}, nil
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+)
+
+func TestCreateAPIKeyValidates(t *testing.T) {
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	tests := []struct {
+		name      string
+		keyName   string
+		scopes    []string
+		expiresAt *time.Time
+	}{
+		{"no name", "  ", []string{user.ScopeArticlesRead}, nil},
+		{"long name", strings.Repeat("x", maxAPIKeyNameLen+1), []string{user.ScopeArticlesRead}, nil},
+		{"no scopes", "ci", nil, nil},
+		{"unknown scope", "ci", []string{user.ScopeArticlesRead, "admin"}, nil},
+		{"expired", "ci", []string{user.ScopeArticlesRead}, &past},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, Config{})
+			u := f.addUser(t, "alice")
+			_, err := f.svc.CreateAPIKey(context.Background(), u.ID, tt.keyName, tt.scopes, tt.expiresAt)
+			if !errors.Is(err, ErrInvalidData) {
+				t.Errorf("CreateAPIKey = %v, want ErrInvalidData", err)
+			}
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		f := newFixture(t, Config{})
+		u := f.addUser(t, "alice")
+		k, err := f.svc.CreateAPIKey(context.Background(), u.ID, " ci ", []string{user.ScopeProfileRead, user.ScopeArticlesRead, user.ScopeProfileRead}, &future)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(k.Key, APIKeyPrefix) || !strings.HasPrefix(k.Key, k.APIKey.Prefix) {
+			t.Errorf("key %q with prefix %q", k.Key, k.APIKey.Prefix)
+		}
+		if k.APIKey.Name != "ci" || !slices.Equal(k.APIKey.Scopes, []string{user.ScopeArticlesRead, user.ScopeProfileRead}) {
+			t.Errorf("key = %+v", k.APIKey)
+		}
+		if strings.Contains(k.APIKey.KeyHash, k.Key) {
+			t.Error("plain key stored")
+		}
+	})
+}
+
+func TestCreateAPIKeyLimit(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	u := f.addUser(t, "alice")
+
+	for range maxAPIKeysPerUser {
+		if _, err := f.svc.CreateAPIKey(ctx, u.ID, "ci", []string{user.ScopeArticlesRead}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := f.svc.CreateAPIKey(ctx, u.ID, "ci", []string{user.ScopeArticlesRead}, nil); !errors.Is(err, ErrTooManyAPIKeys) {
+		t.Fatalf("key over the limit = %v, want ErrTooManyAPIKeys", err)
+	}
+
+	// revoked keys don't count
+	keys, _ := f.svc.ListAPIKeys(ctx, u.ID)
+	if err := f.svc.RevokeAPIKey(ctx, u.ID, keys[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.CreateAPIKey(ctx, u.ID, "ci", []string{user.ScopeArticlesRead}, nil); err != nil {
+		t.Errorf("key after a revocation = %v", err)
+	}
+}
+
+func TestAuthenticateAPIKeyScopes(t *testing.T) {
+	tests := []struct {
+		scopes  []string
+		allowed []string
+		denied  []string
+	}{
+		{
+			[]string{user.ScopeArticlesRead},
+			[]string{user.ScopeArticlesRead},
+			[]string{user.ScopeArticlesWrite, user.ScopeProfileRead},
+		},
+		{
+			[]string{user.ScopeArticlesWrite},
+			[]string{user.ScopeArticlesRead, user.ScopeArticlesWrite},
+			[]string{user.ScopeProfileRead},
+		},
+		{
+			[]string{user.ScopeProfileRead},
+			[]string{user.ScopeProfileRead},
+			[]string{user.ScopeArticlesRead, user.ScopeArticlesWrite},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.scopes, ","), func(t *testing.T) {
+			ctx := context.Background()
+			f := newFixture(t, Config{})
+			u := f.addUser(t, "alice")
+			k, err := f.svc.CreateAPIKey(ctx, u.ID, "ci", tt.scopes, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			id, err := f.svc.Authenticate(ctx, k.Key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id.UserID != u.ID || id.APIKeyID != k.APIKey.ID || !id.Scoped() || id.SessionID != uuid.Nil {
+				t.Errorf("identity = %+v", id)
+			}
+			for _, scope := range tt.allowed {
+				if !id.Can(scope) {
+					t.Errorf("key can't %s", scope)
+				}
+			}
+			for _, scope := range tt.denied {
+				if id.Can(scope) {
+					t.Errorf("key can %s", scope)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticateAPIKeyRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(f *fixture, u *user.User, k *user.APIKey)
+	}{
+		{"expired", func(f *fixture, _ *user.User, k *user.APIKey) {
+			past := time.Now().Add(-time.Second)
+			f.apiKeys.byID[k.ID].ExpiresAt = &past
+		}},
+		{"revoked", func(f *fixture, u *user.User, k *user.APIKey) {
+			_ = f.svc.RevokeAPIKey(context.Background(), u.ID, k.ID)
+		}},
+		{"user suspended", func(f *fixture, u *user.User, _ *user.APIKey) {
+			now := time.Now()
+			f.users.byID[u.ID].SuspendedAt = &now
+		}},
+		{"user deleted", func(f *fixture, u *user.User, _ *user.APIKey) {
+			delete(f.users.byID, u.ID)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newFixture(t, Config{})
+			u := f.addUser(t, "alice")
+			expiresAt := time.Now().Add(time.Hour)
+			k, err := f.svc.CreateAPIKey(ctx, u.ID, "ci", []string{user.ScopeArticlesRead}, &expiresAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := f.svc.Authenticate(ctx, k.Key); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(f, u, k.APIKey)
+			if _, err := f.svc.Authenticate(ctx, k.Key); !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("Authenticate = %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		f := newFixture(t, Config{})
+		if _, err := f.svc.Authenticate(context.Background(), APIKeyPrefix+"0123"); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Authenticate = %v, want ErrUnauthorized", err)
+		}
+	})
+}
+
+func TestRevokeAPIKeyOfAnotherUser(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	alice, bob := f.addUser(t, "alice"), f.addUser(t, "bob")
+	k, err := f.svc.CreateAPIKey(ctx, alice.ID, "ci", []string{user.ScopeArticlesRead}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.svc.RevokeAPIKey(ctx, bob.ID, k.APIKey.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RevokeAPIKey = %v, want ErrAPIKeyNotFound", err)
+	}
+	if _, err := f.svc.Authenticate(ctx, k.Key); err != nil {
+		t.Errorf("key revoked by another user: %v", err)
+	}
+	if keys, _ := f.svc.ListAPIKeys(ctx, bob.ID); len(keys) != 0 {
+		t.Errorf("bob lists %d keys", len(keys))
+	}
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/audited"
+	"gopress/internal/app/periodic"
+	"gopress/internal/app/requestinfo"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+)
+
+const (
+	defaultAuditListLimit = 50
+	maxAuditListLimit     = 200
+)
+
+// auditEvent starts an event carrying the client IP and request ID.
+func auditEvent(ctx context.Context, action string, actorID *uuid.UUID, target string) *audit.Event {
+	info := requestinfo.From(ctx)
+	return &audit.Event{
+		Action:    action,
+		ActorID:   actorID,
+		Target:    target,
+		IP:        info.IP,
+		RequestID: info.RequestID,
+	}
+}
+
+// recordAudit stores an event that goes with no change, such as a failed
+// login. Failures are only logged.
+func (s *Service) recordAudit(ctx context.Context, e *audit.Event) {
+	if err := s.audit.Record(ctx, e); err != nil {
+		log.Printf("audit %s: %v", e.Action, err)
+	}
+}
+
+// withAudit runs fn and records e as audited.Run does.
+func (s *Service) withAudit(ctx context.Context, e *audit.Event, fn func(ctx context.Context) error) error {
+	return audited.Run(ctx, s.tx, s.audit, e, fn)
+}
+
+// userSnapshot is the state of an account kept in audit events.
+func userSnapshot(u *user.User) map[string]any {
+	return map[string]any{
+		"id":             u.ID,
+		"username":       u.Username,
+		"email":          u.Email,
+		"role":           u.Role,
+		"suspended_at":   u.SuspendedAt,
+		"suspend_reason": u.SuspendReason,
+	}
+}
+
+// ListAuditEvents returns audit events, newest first, to an admin.
+func (s *Service) ListAuditEvents(ctx context.Context, actorID uuid.UUID, f audit.Filter) ([]*audit.Event, error) {
+	if err := s.requireAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultAuditListLimit
+	}
+	f.Limit = min(f.Limit, maxAuditListLimit)
+
+	events, err := s.audit.List(ctx, f)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return events, nil
+}
+
+// RunAuditRetention purges events older than AuditRetention every
+// AuditPurgeInterval until ctx is done. It returns at once when retention
+// is off.
+func (s *Service) RunAuditRetention(ctx context.Context) {
+	if s.cfg.AuditRetention <= 0 {
+		return
+	}
+	periodic.Run(ctx, "audit retention", "events", s.cfg.AuditPurgeInterval, func(ctx context.Context) (int64, error) {
+		return s.audit.DeleteBefore(ctx, time.Now().Add(-s.cfg.AuditRetention))
+	})
+}
@@ -0,0 +1,633 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+	"gopress/pkg/jwt"
+	"gopress/pkg/password"
+)
+
+const testPassword = "correct horse battery"
+
+type fakeUsers struct {
+	byID map[uuid.UUID]*user.User
+}
+
+func (r *fakeUsers) Create(_ context.Context, u *user.User) error {
+	u.ID = uuid.New()
+	if u.Role == "" {
+		u.Role = user.RoleUser
+	}
+	r.byID[u.ID] = u
+	return nil
+}
+
+func (r *fakeUsers) find(match func(*user.User) bool) *user.User {
+	for _, u := range r.byID {
+		if match(u) {
+			c := *u
+			return &c
+		}
+	}
+	return nil
+}
+
+func (r *fakeUsers) GetByUsername(_ context.Context, username string) (*user.User, error) {
+	return r.find(func(u *user.User) bool { return strings.EqualFold(u.Username, username) }), nil
+}
+
+func (r *fakeUsers) GetByEmail(_ context.Context, email string) (*user.User, error) {
+	return r.find(func(u *user.User) bool { return strings.EqualFold(u.Email, email) }), nil
+}
+
+func (r *fakeUsers) GetByID(_ context.Context, id uuid.UUID) (*user.User, error) {
+	return r.find(func(u *user.User) bool { return u.ID == id }), nil
+}
+
+func (r *fakeUsers) UpdateProfile(_ context.Context, id uuid.UUID, p user.Profile) (*user.User, error) {
+	u := r.byID[id]
+	if u == nil {
+		return nil, nil
+	}
+	if p.DisplayName != nil {
+		u.DisplayName = *p.DisplayName
+	}
+	if p.Bio != nil {
+		u.Bio = *p.Bio
+	}
+	if p.AvatarURL != nil {
+		u.AvatarURL = *p.AvatarURL
+	}
+	c := *u
+	return &c, nil
+}
+
+func (r *fakeUsers) UpdatePassword(_ context.Context, id uuid.UUID, passwordHash string) (int, error) {
+	u := r.byID[id]
+	u.Password = passwordHash
+	u.TokenVersion++
+	return u.TokenVersion, nil
+}
+
+func (r *fakeUsers) UpdatePasswordHash(_ context.Context, id uuid.UUID, passwordHash string) error {
+	r.byID[id].Password = passwordHash
+	return nil
+}
+
+func (r *fakeUsers) MarkEmailVerified(_ context.Context, id uuid.UUID) error {
+	now := time.Now()
+	r.byID[id].EmailVerifiedAt = &now
+	return nil
+}
+
+func (r *fakeUsers) List(_ context.Context, f user.ListFilter) ([]*user.User, error) {
+	var res []*user.User
+	for _, u := range r.byID {
+		if f.Role == "" || u.Role == f.Role {
+			res = append(res, u)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeUsers) SetRole(_ context.Context, id uuid.UUID, role string) (*user.User, error) {
+	u := r.byID[id]
+	if u == nil {
+		return nil, nil
+	}
+	u.Role = role
+	c := *u
+	return &c, nil
+}
+
+func (r *fakeUsers) SetSuspended(_ context.Context, id uuid.UUID, suspendedAt *time.Time, reason string) (*user.User, error) {
+	u := r.byID[id]
+	if u == nil {
+		return nil, nil
+	}
+	u.SuspendedAt, u.SuspendReason = suspendedAt, reason
+	c := *u
+	return &c, nil
+}
+
+func (r *fakeUsers) Delete(_ context.Context, id uuid.UUID) error {
+	delete(r.byID, id)
+	return nil
+}
+
+type fakeSessions struct {
+	byID    map[uuid.UUID]*user.Session
+	touches int
+}
+
+func (r *fakeSessions) Create(_ context.Context, s *user.Session) error {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	s.LastUsedAt = s.CreatedAt
+	c := *s
+	r.byID[s.ID] = &c
+	return nil
+}
+
+func (r *fakeSessions) Get(_ context.Context, id uuid.UUID) (*user.Session, error) {
+	s, ok := r.byID[id]
+	if !ok {
+		return nil, nil
+	}
+	c := *s
+	return &c, nil
+}
+
+func (r *fakeSessions) ListActive(_ context.Context, userID uuid.UUID) ([]user.Session, error) {
+	var res []user.Session
+	for _, s := range r.byID {
+		if s.UserID == userID && s.Active(time.Now()) {
+			res = append(res, *s)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeSessions) Touch(_ context.Context, id uuid.UUID, ip string, at time.Time) error {
+	r.touches++
+	r.byID[id].IP, r.byID[id].LastUsedAt = ip, at
+	return nil
+}
+
+func (r *fakeSessions) Revoke(_ context.Context, userID, id uuid.UUID) (bool, error) {
+	s, ok := r.byID[id]
+	if !ok || s.UserID != userID || !s.Active(time.Now()) {
+		return false, nil
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	return true, nil
+}
+
+func (r *fakeSessions) RevokeAll(_ context.Context, userID, keep uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	now := time.Now()
+	for id, s := range r.byID {
+		if s.UserID == userID && id != keep && s.Active(now) {
+			s.RevokedAt = &now
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+type fakeAPIKeys struct {
+	byID map[uuid.UUID]*user.APIKey
+}
+
+func (r *fakeAPIKeys) Create(_ context.Context, k *user.APIKey) error {
+	k.ID = uuid.New()
+	k.CreatedAt = time.Now()
+	c := *k
+	r.byID[k.ID] = &c
+	return nil
+}
+
+func (r *fakeAPIKeys) GetByHash(_ context.Context, keyHash string) (*user.APIKey, error) {
+	for _, k := range r.byID {
+		if k.KeyHash == keyHash {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeAPIKeys) ListByUser(_ context.Context, userID uuid.UUID) ([]user.APIKey, error) {
+	var res []user.APIKey
+	for _, k := range r.byID {
+		if k.UserID == userID && k.RevokedAt == nil {
+			res = append(res, *k)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeAPIKeys) Revoke(_ context.Context, userID, id uuid.UUID) (bool, error) {
+	k, ok := r.byID[id]
+	if !ok || k.UserID != userID || k.RevokedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	k.RevokedAt = &now
+	return true, nil
+}
+
+func (r *fakeAPIKeys) Touch(_ context.Context, id uuid.UUID, at time.Time, _ time.Duration) error {
+	r.byID[id].LastUsedAt = &at
+	return nil
+}
+
+type fakeIdentities struct {
+	byID map[uuid.UUID]*user.ExternalIdentity
+}
+
+func (r *fakeIdentities) Get(_ context.Context, provider, subject string) (*user.ExternalIdentity, error) {
+	for _, id := range r.byID {
+		if id.Provider == provider && id.Subject == subject {
+			c := *id
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeIdentities) ListByUser(_ context.Context, userID uuid.UUID) ([]user.ExternalIdentity, error) {
+	var res []user.ExternalIdentity
+	for _, id := range r.byID {
+		if id.UserID == userID {
+			res = append(res, *id)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeIdentities) Create(_ context.Context, id *user.ExternalIdentity) error {
+	id.ID = uuid.New()
+	id.CreatedAt = time.Now()
+	id.LastLoginAt = id.CreatedAt
+	c := *id
+	r.byID[id.ID] = &c
+	return nil
+}
+
+func (r *fakeIdentities) Touch(_ context.Context, id uuid.UUID, email string) error {
+	r.byID[id].Email, r.byID[id].LastLoginAt = email, time.Now()
+	return nil
+}
+
+type fakeOIDCStates struct {
+	byHash map[string]user.OIDCLoginState
+}
+
+func (r *fakeOIDCStates) Create(_ context.Context, stateHash string, s user.OIDCLoginState) error {
+	r.byHash[stateHash] = s
+	return nil
+}
+
+func (r *fakeOIDCStates) Consume(_ context.Context, stateHash string) (*user.OIDCLoginState, error) {
+	s, ok := r.byHash[stateHash]
+	if !ok {
+		return nil, nil
+	}
+	delete(r.byHash, stateHash)
+	return &s, nil
+}
+
+type fakeOAuthClients struct {
+	byID map[uuid.UUID]*user.OAuthClient
+}
+
+func (r *fakeOAuthClients) Create(_ context.Context, c *user.OAuthClient) error {
+	c.ID = uuid.New()
+	c.CreatedAt = time.Now()
+	cc := *c
+	r.byID[c.ID] = &cc
+	return nil
+}
+
+func (r *fakeOAuthClients) Get(_ context.Context, id uuid.UUID) (*user.OAuthClient, error) {
+	c, ok := r.byID[id]
+	if !ok {
+		return nil, nil
+	}
+	cc := *c
+	return &cc, nil
+}
+
+func (r *fakeOAuthClients) ListByOwner(_ context.Context, ownerID uuid.UUID) ([]user.OAuthClient, error) {
+	var res []user.OAuthClient
+	for _, c := range r.byID {
+		if c.OwnerID == ownerID {
+			res = append(res, *c)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeOAuthClients) Delete(_ context.Context, ownerID, id uuid.UUID) (bool, error) {
+	c, ok := r.byID[id]
+	if !ok || c.OwnerID != ownerID {
+		return false, nil
+	}
+	delete(r.byID, id)
+	return true, nil
+}
+
+type fakeOAuthCodes struct {
+	byHash map[string]user.OAuthCode
+}
+
+func (r *fakeOAuthCodes) Create(_ context.Context, codeHash string, c user.OAuthCode) error {
+	r.byHash[codeHash] = c
+	return nil
+}
+
+func (r *fakeOAuthCodes) Consume(_ context.Context, codeHash string) (*user.OAuthCode, error) {
+	c, ok := r.byHash[codeHash]
+	if !ok {
+		return nil, nil
+	}
+	delete(r.byHash, codeHash)
+	return &c, nil
+}
+
+type fakeGrant struct {
+	user.OAuthGrant
+	refreshHash string
+}
+
+type fakeOAuthGrants struct {
+	byID map[uuid.UUID]*fakeGrant
+}
+
+func (r *fakeOAuthGrants) Create(_ context.Context, g *user.OAuthGrant, refreshHash string) error {
+	g.ID = uuid.New()
+	g.CreatedAt = time.Now()
+	r.byID[g.ID] = &fakeGrant{OAuthGrant: *g, refreshHash: refreshHash}
+	return nil
+}
+
+func (r *fakeOAuthGrants) Get(_ context.Context, id uuid.UUID) (*user.OAuthGrant, error) {
+	g, ok := r.byID[id]
+	if !ok {
+		return nil, nil
+	}
+	c := g.OAuthGrant
+	return &c, nil
+}
+
+func (r *fakeOAuthGrants) GetByRefreshHash(_ context.Context, refreshHash string) (*user.OAuthGrant, error) {
+	for _, g := range r.byID {
+		if g.refreshHash == refreshHash {
+			c := g.OAuthGrant
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeOAuthGrants) Rotate(_ context.Context, id uuid.UUID, oldHash, newHash string, expiresAt, now time.Time) (bool, error) {
+	g, ok := r.byID[id]
+	if !ok || g.refreshHash != oldHash || !g.Active(now) {
+		return false, nil
+	}
+	g.refreshHash, g.ExpiresAt, g.LastUsedAt = newHash, expiresAt, &now
+	return true, nil
+}
+
+func (r *fakeOAuthGrants) Revoke(_ context.Context, id uuid.UUID) error {
+	now := time.Now()
+	r.byID[id].RevokedAt = &now
+	return nil
+}
+
+func (r *fakeOAuthGrants) RevokeClient(_ context.Context, userID, clientID uuid.UUID) (bool, error) {
+	revoked := false
+	now := time.Now()
+	for _, g := range r.byID {
+		if g.UserID == userID && g.ClientID == clientID && g.Active(now) {
+			g.RevokedAt = &now
+			revoked = true
+		}
+	}
+	return revoked, nil
+}
+
+func (r *fakeOAuthGrants) ListApps(_ context.Context, userID uuid.UUID) ([]user.AuthorizedApp, error) {
+	var res []user.AuthorizedApp
+	for _, g := range r.byID {
+		if g.UserID == userID && g.Active(time.Now()) {
+			res = append(res, user.AuthorizedApp{ClientID: g.ClientID, Scopes: g.Scopes, CreatedAt: g.CreatedAt})
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeOAuthGrants) Covers(_ context.Context, userID, clientID uuid.UUID, scopes []string) (bool, error) {
+	for _, g := range r.byID {
+		if g.UserID == userID && g.ClientID == clientID && g.Active(time.Now()) && !slices.ContainsFunc(scopes, func(sc string) bool {
+			return !slices.Contains(g.Scopes, sc)
+		}) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type resetToken struct {
+	userID    uuid.UUID
+	expiresAt time.Time
+	used      bool
+}
+
+type fakeResets struct {
+	byHash map[string]*resetToken
+}
+
+func (r *fakeResets) Create(_ context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	for hash, t := range r.byHash {
+		if t.userID == userID {
+			delete(r.byHash, hash)
+		}
+	}
+	r.byHash[tokenHash] = &resetToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+func (r *fakeResets) Peek(_ context.Context, tokenHash string) (uuid.UUID, error) {
+	t, ok := r.byHash[tokenHash]
+	if !ok || t.used || !time.Now().Before(t.expiresAt) {
+		return uuid.Nil, nil
+	}
+	return t.userID, nil
+}
+
+func (r *fakeResets) Consume(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	userID, err := r.Peek(ctx, tokenHash)
+	if userID != uuid.Nil {
+		r.byHash[tokenHash].used = true
+	}
+	return userID, err
+}
+
+// fakeMailer hands mails sent in the background to the test.
+type fakeMailer struct {
+	sent chan ports.Mail
+}
+
+func (m *fakeMailer) Send(_ context.Context, mail ports.Mail) error {
+	m.sent <- mail
+	return nil
+}
+
+type fakeBreached map[string]bool
+
+func (b fakeBreached) IsBreached(_ context.Context, plain string) (bool, error) {
+	return b[plain], nil
+}
+
+// fakeThrottle counts failures without a window: the tests are quicker
+// than any of them.
+type fakeThrottle struct {
+	entries map[string]*user.LoginThrottle
+}
+
+func (r *fakeThrottle) Get(_ context.Context, keys []string) ([]*user.LoginThrottle, error) {
+	var res []*user.LoginThrottle
+	for _, k := range keys {
+		if e, ok := r.entries[k]; ok {
+			c := *e
+			res = append(res, &c)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeThrottle) RecordFailure(_ context.Context, key string, _ time.Duration) (int, error) {
+	e, ok := r.entries[key]
+	if !ok {
+		e = &user.LoginThrottle{Key: key}
+		r.entries[key] = e
+	}
+	e.Failures++
+	e.LastFailureAt = time.Now()
+	return e.Failures, nil
+}
+
+func (r *fakeThrottle) Block(_ context.Context, key string, until time.Time) error {
+	r.entries[key].BlockedUntil = &until
+	return nil
+}
+
+func (r *fakeThrottle) Reset(_ context.Context, key string) error {
+	delete(r.entries, key)
+	return nil
+}
+
+func (r *fakeThrottle) DeleteStale(context.Context, time.Duration) (int64, error) { return 0, nil }
+
+// fakeMFA has no second factors.
+type fakeMFA struct{}
+
+func (fakeMFA) GetTOTP(context.Context, uuid.UUID) (*user.TOTP, error)       { return nil, nil }
+func (fakeMFA) SaveTOTPSecret(context.Context, uuid.UUID, string) error      { return nil }
+func (fakeMFA) EnableTOTP(context.Context, uuid.UUID, int64, []string) error { return nil }
+func (fakeMFA) DisableTOTP(context.Context, uuid.UUID) error                 { return nil }
+func (fakeMFA) UseTOTPStep(context.Context, uuid.UUID, int64) (bool, error)  { return false, nil }
+func (fakeMFA) UseRecoveryCode(context.Context, uuid.UUID, string) (bool, error) {
+	return false, nil
+}
+
+type fakeAudit struct {
+	events []*audit.Event
+}
+
+func (a *fakeAudit) Record(_ context.Context, e *audit.Event) error {
+	a.events = append(a.events, e)
+	return nil
+}
+
+func (a *fakeAudit) List(context.Context, audit.Filter) ([]*audit.Event, error) {
+	return a.events, nil
+}
+
+func (a *fakeAudit) DeleteBefore(context.Context, time.Time) (int64, error) { return 0, nil }
+
+// actions lists the recorded actions in order.
+func (a *fakeAudit) actions() []string {
+	var res []string
+	for _, e := range a.events {
+		res = append(res, e.Action)
+	}
+	return res
+}
+
+// fakeTx has no rollback; the tests look at what a failed call leaves.
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fixture struct {
+	svc        *Service
+	users      *fakeUsers
+	sessions   *fakeSessions
+	apiKeys    *fakeAPIKeys
+	identities *fakeIdentities
+	oidcStates *fakeOIDCStates
+	clients    *fakeOAuthClients
+	codes      *fakeOAuthCodes
+	grants     *fakeOAuthGrants
+	resets     *fakeResets
+	mailer     *fakeMailer
+	breached   fakeBreached
+	throttle   *fakeThrottle
+	audit      *fakeAudit
+}
+
+func newFixture(t *testing.T, cfg Config) *fixture {
+	t.Helper()
+	f := &fixture{
+		users:      &fakeUsers{byID: map[uuid.UUID]*user.User{}},
+		sessions:   &fakeSessions{byID: map[uuid.UUID]*user.Session{}},
+		apiKeys:    &fakeAPIKeys{byID: map[uuid.UUID]*user.APIKey{}},
+		identities: &fakeIdentities{byID: map[uuid.UUID]*user.ExternalIdentity{}},
+		oidcStates: &fakeOIDCStates{byHash: map[string]user.OIDCLoginState{}},
+		clients:    &fakeOAuthClients{byID: map[uuid.UUID]*user.OAuthClient{}},
+		codes:      &fakeOAuthCodes{byHash: map[string]user.OAuthCode{}},
+		grants:     &fakeOAuthGrants{byID: map[uuid.UUID]*fakeGrant{}},
+		resets:     &fakeResets{byHash: map[string]*resetToken{}},
+		mailer:     &fakeMailer{sent: make(chan ports.Mail, 10)},
+		breached:   fakeBreached{},
+		throttle:   &fakeThrottle{entries: map[string]*user.LoginThrottle{}},
+		audit:      &fakeAudit{},
+	}
+	cfg.Hasher = password.NewHasher(password.NewBcrypt(4))
+	if cfg.SessionCacheTTL == 0 {
+		cfg.SessionCacheTTL = time.Minute
+	}
+	f.svc = NewService(f.users, f.sessions, f.apiKeys, f.identities, f.oidcStates,
+		f.clients, f.codes, f.grants, f.resets, nil,
+		fakeMFA{}, f.throttle, f.breached, f.audit, fakeTx{}, f.mailer,
+		jwt.NewManager("test-secret", time.Hour), cfg)
+	return f
+}
+
+// addUser stores a user with testPassword.
+func (f *fixture) addUser(t *testing.T, username string) *user.User {
+	t.Helper()
+	hash, err := f.svc.hasher.Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &user.User{Username: username, Email: username + "@example.com", Password: hash}
+	if err := f.users.Create(context.Background(), u); err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+// login signs u in and returns the access token.
+func (f *fixture) login(t *testing.T, u *user.User) string {
+	t.Helper()
+	res, err := f.svc.Login(context.Background(), u.Username, testPassword)
+	if err != nil {
+		t.Fatalf("login %s: %v", u.Username, err)
+	}
+	return res.Token
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+	"gopress/pkg/token"
+	"gopress/pkg/totp"
+)
+
+const (
+	mfaPurpose = "mfa"
+	// accept codes from the neighbouring periods for clock drift
+	totpSkew          = 1
+	recoveryCodeCount = 10
+	recoveryCodeLen   = 10
+	recoveryAlphabet  = "abcdefghijkmnpqrstuvwxyz23456789"
+)
+
+type TOTPEnrollment struct {
+	Secret string
+	// URI is the otpauth:// provisioning URI to render as a QR code.
+	URI string
+}
+
+// LoginMFA completes a login started by Login with the MFA challenge token
+// and a TOTP or recovery code.
+func (s *Service) LoginMFA(ctx context.Context, mfaToken, code string) (string, error) {
+	if mfaToken == "" || code == "" {
+		return "", ErrInvalidData
+	}
+
+	claims, err := s.jwtManager.ParsePurposeToken(mfaToken, mfaPurpose)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	keys := s.mfaKeys(ctx, userID)
+	if err := s.checkThrottle(ctx, keys); err != nil {
+		return "", err
+	}
+
+	u, err := s.GetMe(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	t, err := s.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		return "", ErrInternalError
+	}
+	if !t.Enabled() {
+		return "", ErrInvalidToken
+	}
+
+	if err := s.checkSecondFactor(ctx, t, code); err != nil {
+		if errors.Is(err, ErrInvalidCode) {
+			s.recordFailure(ctx, keys, &userID)
+		}
+		return "", err
+	}
+	s.resetThrottle(ctx, keys[0].key)
+
+	return s.startSession(ctx, u)
+}
+
+// StartTOTP generates a new secret. TOTP is not enforced until ConfirmTOTP.
+func (s *Service) StartTOTP(ctx context.Context, userID uuid.UUID) (*TOTPEnrollment, error) {
+	u, err := s.GetMe(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if t.Enabled() {
+		return nil, ErrMFAEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if err := s.mfa.SaveTOTPSecret(ctx, userID, secret); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    totp.URI(s.cfg.MFAIssuer, u.Username, secret),
+	}, nil
+}
+
+// ConfirmTOTP enables TOTP after the user proves the authenticator works and
+// returns recovery codes. They are only stored hashed and can't be shown again.
+func (s *Service) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if code == "" {
+		return nil, ErrInvalidData
+	}
+
+	t, err := s.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if t == nil {
+		return nil, ErrMFANotEnabled
+	}
+	if t.Enabled() {
+		return nil, ErrMFAEnabled
+	}
+
+	step, ok := totp.Validate(t.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		c, err := newRecoveryCode()
+		if err != nil {
+			return nil, ErrInternalError
+		}
+		codes[i] = c
+		hashes[i] = token.Hash(normalizeRecoveryCode(c))
+	}
+
+	if err := s.mfa.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		return nil, ErrInternalError
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns the second factor off, it takes a TOTP or recovery code.
+func (s *Service) DisableTOTP(ctx context.Context, userID uuid.UUID, code string) error {
+	if code == "" {
+		return ErrInvalidData
+	}
+
+	t, err := s.mfa.GetTOTP(ctx, userID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !t.Enabled() {
+		return ErrMFANotEnabled
+	}
+
+	if err := s.checkSecondFactor(ctx, t, code); err != nil {
+		return err
+	}
+
+	if err := s.mfa.DisableTOTP(ctx, userID); err != nil {
+		return ErrInternalError
+	}
+	return nil
+}
+
+// checkSecondFactor accepts a current TOTP code (once) or an unused
+// recovery code.
+func (s *Service) checkSecondFactor(ctx context.Context, t *user.TOTP, code string) error {
+	if step, ok := totp.Validate(t.Secret, code, time.Now(), totpSkew); ok {
+		fresh, err := s.mfa.UseTOTPStep(ctx, t.UserID, step)
+		if err != nil {
+			return ErrInternalError
+		}
+		if !fresh {
+			return ErrInvalidCode
+		}
+		return nil
+	}
+
+	used, err := s.mfa.UseRecoveryCode(ctx, t.UserID, token.Hash(normalizeRecoveryCode(code)))
+	if err != nil {
+		return ErrInternalError
+	}
+	if !used {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// newRecoveryCode returns a code like "k3fpq-7xw2m".
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = recoveryAlphabet[int(b[i])%len(recoveryAlphabet)]
+	}
+	return string(b[:recoveryCodeLen/2]) + "-" + string(b[recoveryCodeLen/2:]), nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
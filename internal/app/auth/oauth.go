@@ -0,0 +1,666 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+	jwtpkg "gopress/pkg/jwt"
+	"gopress/pkg/token"
+)
+
+// OAuthRefreshPrefix starts every refresh token, OAuthSecretPrefix every
+// client secret, so secret scanners can spot leaks.
+const (
+	OAuthRefreshPrefix = "gprt_"
+	OAuthSecretPrefix  = "gpcs_"
+)
+
+const (
+	maxOAuthClientNameLen  = 100
+	maxOAuthClientsPerUser = 20
+	maxRedirectURIs        = 10
+	pkceMethodS256         = "S256"
+)
+
+var (
+	ErrOAuthClientNotFound = errors.New("oauth client not found")
+	ErrTooManyOAuthClients = errors.New("too many oauth clients")
+)
+
+type oauthRepos struct {
+	clients ports.OAuthClientRepo
+	codes   ports.OAuthCodeRepo
+	grants  ports.OAuthGrantRepo
+}
+
+// OAuthError is an error response defined by RFC 6749, e.g.
+// "invalid_grant". RedirectTo is set when the error may be reported to the
+// client by redirecting the user back to it.
+type OAuthError struct {
+	Code        string
+	Description string
+	RedirectTo  string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func oauthError(code, description string) error {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// NewOAuthClient is a freshly registered client. Secret is only available
+// here, and empty for public clients.
+type NewOAuthClient struct {
+	*user.OAuthClient
+	Secret string
+}
+
+// ClientCredentials authenticate a client at the token, introspection
+// and revocation endpoints. Public clients have no secret.
+type ClientCredentials struct {
+	ID     string
+	Secret string
+}
+
+// AuthorizeRequest holds the parameters of an authorization request.
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// ConsentRequest is what the consent screen shows: who asks for what.
+type ConsentRequest struct {
+	Client      *user.OAuthClient
+	Scopes      []string
+	RedirectURI string
+	// Granted is set when the user already authorized these scopes, the
+	// screen may then be skipped.
+	Granted bool
+}
+
+type OAuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+	Scopes       []string
+}
+
+// TokenInfo describes a token for introspection (RFC 7662).
+type TokenInfo struct {
+	Active    bool
+	TokenType string
+	ClientID  uuid.UUID
+	UserID    uuid.UUID
+	Username  string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// RegisterOAuthClient registers a third-party application owned by the
+// user. Confidential clients get a secret, public ones (SPAs, native
+// apps) rely on PKCE alone.
+func (s *Service) RegisterOAuthClient(ctx context.Context, ownerID uuid.UUID, name string, redirectURIs, scopes []string, confidential bool) (*NewOAuthClient, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxOAuthClientNameLen {
+		return nil, ErrInvalidData
+	}
+	if len(redirectURIs) == 0 || len(redirectURIs) > maxRedirectURIs || len(scopes) == 0 {
+		return nil, ErrInvalidData
+	}
+	for _, uri := range redirectURIs {
+		if !validRedirectURI(uri) {
+			return nil, ErrInvalidData
+		}
+	}
+	for _, sc := range scopes {
+		if !user.ValidScope(sc) {
+			return nil, ErrInvalidData
+		}
+	}
+
+	existing, err := s.oauth.clients.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if len(existing) >= maxOAuthClientsPerUser {
+		return nil, ErrTooManyOAuthClients
+	}
+
+	c := &user.OAuthClient{
+		OwnerID:      ownerID,
+		Name:         name,
+		RedirectURIs: slices.Compact(slices.Clone(redirectURIs)),
+		Scopes:       normalizeScopes(scopes),
+	}
+
+	var secret string
+	if confidential {
+		plain, _, err := token.Generate()
+		if err != nil {
+			return nil, ErrInternalError
+		}
+		secret = OAuthSecretPrefix + plain
+		c.SecretHash = token.Hash(secret)
+	}
+
+	if err := s.oauth.clients.Create(ctx, c); err != nil {
+		return nil, ErrInternalError
+	}
+	return &NewOAuthClient{OAuthClient: c, Secret: secret}, nil
+}
+
+func (s *Service) ListOAuthClients(ctx context.Context, ownerID uuid.UUID) ([]user.OAuthClient, error) {
+	clients, err := s.oauth.clients.ListByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return clients, nil
+}
+
+// DeleteOAuthClient removes the client, its grants and with them all the
+// tokens it holds.
+func (s *Service) DeleteOAuthClient(ctx context.Context, ownerID, id uuid.UUID) error {
+	ok, err := s.oauth.clients.Delete(ctx, ownerID, id)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !ok {
+		return ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+// CheckAuthorization validates an authorization request for the consent
+// screen. Errors are *OAuthError.
+func (s *Service) CheckAuthorization(ctx context.Context, userID uuid.UUID, req AuthorizeRequest) (*ConsentRequest, error) {
+	c, redirectURI, scopes, err := s.validateAuthorize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	granted, err := s.oauth.grants.Covers(ctx, userID, c.ID, scopes)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	return &ConsentRequest{Client: c, Scopes: scopes, RedirectURI: redirectURI, Granted: granted}, nil
+}
+
+// Authorize records the user's decision and returns where to send the
+// user: back to the client with a code, or with access_denied.
+func (s *Service) Authorize(ctx context.Context, userID uuid.UUID, req AuthorizeRequest, approved bool) (string, error) {
+	c, redirectURI, scopes, err := s.validateAuthorize(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if !approved {
+		return redirectWith(redirectURI, url.Values{
+			"error":             {"access_denied"},
+			"error_description": {"the user denied the request"},
+		}, req.State), nil
+	}
+
+	code, codeHash, err := token.Generate()
+	if err != nil {
+		return "", ErrInternalError
+	}
+	err = s.oauth.codes.Create(ctx, codeHash, user.OAuthCode{
+		ClientID:      c.ID,
+		UserID:        userID,
+		RedirectURI:   redirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(s.cfg.OAuthCodeTTL),
+	})
+	if err != nil {
+		return "", ErrInternalError
+	}
+
+	return redirectWith(redirectURI, url.Values{"code": {code}}, req.State), nil
+}
+
+// validateAuthorize checks the client and redirect URI first: until both
+// are known good, errors must not redirect anywhere.
+func (s *Service) validateAuthorize(ctx context.Context, req AuthorizeRequest) (*user.OAuthClient, string, []string, error) {
+	c, err := s.oauthClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	redirectURI := req.RedirectURI
+	if redirectURI == "" && len(c.RedirectURIs) == 1 {
+		redirectURI = c.RedirectURIs[0]
+	}
+	if !c.AllowsRedirect(redirectURI) {
+		return nil, "", nil, oauthError("invalid_request", "redirect_uri is not registered for this client")
+	}
+
+	fail := func(code, description string) error {
+		return &OAuthError{
+			Code:        code,
+			Description: description,
+			RedirectTo: redirectWith(redirectURI, url.Values{
+				"error":             {code},
+				"error_description": {description},
+			}, req.State),
+		}
+	}
+
+	if req.ResponseType != "code" {
+		return nil, "", nil, fail("unsupported_response_type", "only response_type=code is supported")
+	}
+	if req.CodeChallengeMethod != pkceMethodS256 || !validPKCEValue(req.CodeChallenge) {
+		return nil, "", nil, fail("invalid_request", "PKCE with code_challenge_method=S256 is required")
+	}
+
+	scopes, err := parseScopes(req.Scope, c.Scopes)
+	if err != nil {
+		return nil, "", nil, fail("invalid_scope", err.Error())
+	}
+
+	return c, redirectURI, scopes, nil
+}
+
+// ExchangeOAuthCode redeems an authorization code (grant_type
+// authorization_code). Errors are *OAuthError.
+func (s *Service) ExchangeOAuthCode(ctx context.Context, cc ClientCredentials, code, redirectURI, verifier string) (*OAuthTokens, error) {
+	c, err := s.authenticateClient(ctx, cc)
+	if err != nil {
+		return nil, err
+	}
+	if code == "" || !validPKCEValue(verifier) {
+		return nil, oauthError("invalid_request", "code and code_verifier are required")
+	}
+
+	ac, err := s.oauth.codes.Consume(ctx, token.Hash(code))
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if ac == nil || ac.ClientID != c.ID || time.Now().After(ac.ExpiresAt) {
+		return nil, oauthError("invalid_grant", "invalid or expired code")
+	}
+	if ac.RedirectURI != redirectURI {
+		return nil, oauthError("invalid_grant", "redirect_uri does not match")
+	}
+	if subtle.ConstantTimeCompare([]byte(pkceChallenge(verifier)), []byte(ac.CodeChallenge)) != 1 {
+		return nil, oauthError("invalid_grant", "code_verifier does not match")
+	}
+
+	u, err := s.repo.GetByID(ctx, ac.UserID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if u == nil || u.Suspended() {
+		return nil, oauthError("invalid_grant", "invalid or expired code")
+	}
+
+	refresh, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	g := &user.OAuthGrant{
+		ClientID:  c.ID,
+		UserID:    u.ID,
+		Scopes:    ac.Scopes,
+		ExpiresAt: time.Now().Add(s.cfg.OAuthRefreshTokenTTL),
+	}
+	if err := s.oauth.grants.Create(ctx, g, refreshHash); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return s.issueOAuthTokens(u, g, ac.Scopes, refresh)
+}
+
+// RefreshOAuthToken rotates a refresh token (grant_type refresh_token).
+// scope may narrow the access token down. Errors are *OAuthError.
+func (s *Service) RefreshOAuthToken(ctx context.Context, cc ClientCredentials, refresh, scope string) (*OAuthTokens, error) {
+	c, err := s.authenticateClient(ctx, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	oldHash := token.Hash(refresh)
+	g, err := s.oauth.grants.GetByRefreshHash(ctx, oldHash)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	now := time.Now()
+	if !g.Active(now) || g.ClientID != c.ID {
+		return nil, oauthError("invalid_grant", "invalid or expired refresh token")
+	}
+
+	scopes := g.Scopes
+	if scope != "" {
+		if scopes, err = parseScopes(scope, g.Scopes); err != nil {
+			return nil, oauthError("invalid_scope", err.Error())
+		}
+	}
+
+	u, err := s.repo.GetByID(ctx, g.UserID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if u == nil || u.Suspended() {
+		return nil, oauthError("invalid_grant", "invalid or expired refresh token")
+	}
+
+	next, nextHash, err := newRefreshToken()
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	ok, err := s.oauth.grants.Rotate(ctx, g.ID, oldHash, nextHash, now.Add(s.cfg.OAuthRefreshTokenTTL), now)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if !ok {
+		return nil, oauthError("invalid_grant", "invalid or expired refresh token")
+	}
+
+	return s.issueOAuthTokens(u, g, scopes, next)
+}
+
+func (s *Service) issueOAuthTokens(u *user.User, g *user.OAuthGrant, scopes []string, refresh string) (*OAuthTokens, error) {
+	ttl := s.cfg.OAuthAccessTokenTTL
+	access, err := s.jwtManager.GenerateClientToken(u.ID, u.Username, u.TokenVersion, g.ClientID, g.ID, strings.Join(scopes, " "), ttl)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	return &OAuthTokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    ttl,
+		Scopes:       scopes,
+	}, nil
+}
+
+// IntrospectOAuthToken describes an access or refresh token issued to the
+// calling client. Tokens of other clients are reported inactive.
+func (s *Service) IntrospectOAuthToken(ctx context.Context, cc ClientCredentials, tok string) (*TokenInfo, error) {
+	c, err := s.authenticateClient(ctx, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(tok, OAuthRefreshPrefix) {
+		g, err := s.oauth.grants.GetByRefreshHash(ctx, token.Hash(tok))
+		if err != nil {
+			return nil, ErrInternalError
+		}
+		if !g.Active(time.Now()) || g.ClientID != c.ID {
+			return &TokenInfo{}, nil
+		}
+		u, err := s.repo.GetByID(ctx, g.UserID)
+		if err != nil {
+			return nil, ErrInternalError
+		}
+		if u == nil || u.Suspended() {
+			return &TokenInfo{}, nil
+		}
+		return &TokenInfo{
+			Active:    true,
+			TokenType: "refresh_token",
+			ClientID:  c.ID,
+			UserID:    u.ID,
+			Username:  u.Username,
+			Scopes:    g.Scopes,
+			ExpiresAt: g.ExpiresAt,
+		}, nil
+	}
+
+	claims, err := s.jwtManager.ParseToken(tok)
+	if err != nil || claims.ClientID != c.ID.String() {
+		return &TokenInfo{}, nil
+	}
+	id, err := s.authenticateOAuth(ctx, claims)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return &TokenInfo{}, nil
+		}
+		return nil, err
+	}
+
+	return &TokenInfo{
+		Active:    true,
+		TokenType: "access_token",
+		ClientID:  c.ID,
+		UserID:    id.UserID,
+		Username:  id.Username,
+		Scopes:    id.Scopes,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// RevokeOAuthToken revokes the grant behind an access or refresh token of
+// the calling client, which ends both. Unknown tokens are ignored, as
+// RFC 7009 requires.
+func (s *Service) RevokeOAuthToken(ctx context.Context, cc ClientCredentials, tok string) error {
+	c, err := s.authenticateClient(ctx, cc)
+	if err != nil {
+		return err
+	}
+
+	var g *user.OAuthGrant
+	if strings.HasPrefix(tok, OAuthRefreshPrefix) {
+		if g, err = s.oauth.grants.GetByRefreshHash(ctx, token.Hash(tok)); err != nil {
+			return ErrInternalError
+		}
+	} else if claims, err := s.jwtManager.ParseToken(tok); err == nil && claims.GrantID != "" {
+		grantID, err := uuid.Parse(claims.GrantID)
+		if err != nil {
+			return nil
+		}
+		if g, err = s.oauth.grants.Get(ctx, grantID); err != nil {
+			return ErrInternalError
+		}
+	}
+
+	if g == nil || g.ClientID != c.ID {
+		return nil
+	}
+	if err := s.oauth.grants.Revoke(ctx, g.ID); err != nil {
+		return ErrInternalError
+	}
+	return nil
+}
+
+// ListAuthorizedApps returns the clients the user has granted access to.
+func (s *Service) ListAuthorizedApps(ctx context.Context, userID uuid.UUID) ([]user.AuthorizedApp, error) {
+	apps, err := s.oauth.grants.ListApps(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return apps, nil
+}
+
+// RevokeAuthorizedApp withdraws the user's consent: all grants, and so all
+// tokens, of the client for this user stop working.
+func (s *Service) RevokeAuthorizedApp(ctx context.Context, userID, clientID uuid.UUID) error {
+	ok, err := s.oauth.grants.RevokeClient(ctx, userID, clientID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !ok {
+		return ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+// authenticateOAuth checks an access token issued to a client: the grant
+// must still be active, so revocation takes effect immediately.
+func (s *Service) authenticateOAuth(ctx context.Context, claims *jwtpkg.Claims) (*Identity, error) {
+	userID, err1 := uuid.Parse(claims.UserID)
+	clientID, err2 := uuid.Parse(claims.ClientID)
+	grantID, err3 := uuid.Parse(claims.GrantID)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	g, err := s.oauth.grants.Get(ctx, grantID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if !g.Active(time.Now()) || g.UserID != userID || g.ClientID != clientID {
+		return nil, ErrUnauthorized
+	}
+
+	u, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if u == nil || u.Suspended() || u.TokenVersion != claims.TokenVersion {
+		return nil, ErrUnauthorized
+	}
+
+	return &Identity{
+		UserID:   u.ID,
+		Username: u.Username,
+		ClientID: clientID,
+		Scopes:   strings.Fields(claims.Scope),
+	}, nil
+}
+
+func (s *Service) oauthClient(ctx context.Context, clientID string) (*user.OAuthClient, error) {
+	id, err := uuid.Parse(clientID)
+	if err != nil {
+		return nil, oauthError("invalid_client", "unknown client")
+	}
+	c, err := s.oauth.clients.Get(ctx, id)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if c == nil {
+		return nil, oauthError("invalid_client", "unknown client")
+	}
+	return c, nil
+}
+
+// authenticateClient checks the secret of confidential clients. Public
+// clients must not send one.
+func (s *Service) authenticateClient(ctx context.Context, cc ClientCredentials) (*user.OAuthClient, error) {
+	c, err := s.oauthClient(ctx, cc.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.Confidential() {
+		if cc.Secret != "" {
+			return nil, oauthError("invalid_client", "client authentication failed")
+		}
+		return c, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(token.Hash(cc.Secret)), []byte(c.SecretHash)) != 1 {
+		return nil, oauthError("invalid_client", "client authentication failed")
+	}
+	return c, nil
+}
+
+func newRefreshToken() (plain, hash string, err error) {
+	t, _, err := token.Generate()
+	if err != nil {
+		return "", "", err
+	}
+	plain = OAuthRefreshPrefix + t
+	return plain, token.Hash(plain), nil
+}
+
+// parseScopes parses a space separated scope parameter, which must stay
+// within allowed. An empty parameter asks for all of allowed.
+func parseScopes(scope string, allowed []string) ([]string, error) {
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		return slices.Clone(allowed), nil
+	}
+	for _, sc := range requested {
+		if !user.ValidScope(sc) {
+			return nil, errors.New("unknown scope " + sc)
+		}
+		if !user.GrantsScope(allowed, sc) {
+			return nil, errors.New("scope " + sc + " is not allowed for this client")
+		}
+	}
+	return normalizeScopes(requested), nil
+}
+
+func normalizeScopes(scopes []string) []string {
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
+
+// validRedirectURI accepts https URLs, http on loopback for local tools
+// and private-use schemes of native apps (com.example.app:/callback,
+// RFC 8252). Fragments are not allowed.
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Fragment != "" {
+		return false
+	}
+
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	default:
+		return strings.Contains(u.Scheme, ".")
+	}
+}
+
+// validPKCEValue checks the RFC 7636 shape of a code verifier or an S256
+// challenge: 43 to 128 unreserved characters.
+func validPKCEValue(v string) bool {
+	if len(v) < 43 || len(v) > 128 {
+		return false
+	}
+	for _, c := range v {
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~') {
+			return false
+		}
+	}
+	return true
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// redirectWith adds params and the client's state to a redirect URI,
+// keeping its own query.
+func redirectWith(redirectURI string, params url.Values, state string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		log.Printf("invalid redirect uri %q: %v", redirectURI, err)
+		return redirectURI
+	}
+	q := u.Query()
+	for k, vs := range params {
+		q[k] = vs
+	}
+	if state != "" {
+		q.Set("state", state)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,457 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+)
+
+const (
+	// the example of RFC 7636, appendix B
+	testVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	testRedirect = "https://app.example/callback"
+)
+
+func newOAuthFixture(t *testing.T) *fixture {
+	t.Helper()
+	return newFixture(t, Config{
+		OAuthCodeTTL:         time.Minute,
+		OAuthAccessTokenTTL:  time.Hour,
+		OAuthRefreshTokenTTL: 24 * time.Hour,
+	})
+}
+
+func (f *fixture) registerClient(t *testing.T, owner *user.User, confidential bool) *NewOAuthClient {
+	t.Helper()
+	c, err := f.svc.RegisterOAuthClient(context.Background(), owner.ID, "app", []string{testRedirect},
+		[]string{user.ScopeArticlesWrite, user.ScopeProfileRead}, confidential)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func credentials(c *NewOAuthClient) ClientCredentials {
+	return ClientCredentials{ID: c.ID.String(), Secret: c.Secret}
+}
+
+func authorizeRequest(c *NewOAuthClient, scope string) AuthorizeRequest {
+	return AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            c.ID.String(),
+		RedirectURI:         testRedirect,
+		Scope:               scope,
+		State:               "xyz",
+		CodeChallenge:       testChallenge,
+		CodeChallengeMethod: pkceMethodS256,
+	}
+}
+
+// authorizeCode has u approve c and returns the code it redirects with.
+func (f *fixture) authorizeCode(t *testing.T, u *user.User, c *NewOAuthClient, scope string) string {
+	t.Helper()
+	to, err := f.svc.Authorize(context.Background(), u.ID, authorizeRequest(c, scope), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redirect, err := url.Parse(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := redirect.Query()
+	if !strings.HasPrefix(to, testRedirect+"?") || q.Get("state") != "xyz" || q.Get("code") == "" {
+		t.Fatalf("redirect to %q", to)
+	}
+	return q.Get("code")
+}
+
+// oauthTokens runs the code flow for u and c.
+func (f *fixture) oauthTokens(t *testing.T, u *user.User, c *NewOAuthClient, scope string) *OAuthTokens {
+	t.Helper()
+	code := f.authorizeCode(t, u, c, scope)
+	tokens, err := f.svc.ExchangeOAuthCode(context.Background(), credentials(c), code, testRedirect, testVerifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func oauthErrorCode(err error) string {
+	var oe *OAuthError
+	if errors.As(err, &oe) {
+		return oe.Code
+	}
+	return ""
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	if got := pkceChallenge(testVerifier); got != testChallenge {
+		t.Errorf("pkceChallenge = %q, want %q", got, testChallenge)
+	}
+	for _, v := range []string{"", strings.Repeat("a", 42), strings.Repeat("a", 129), strings.Repeat("a", 42) + "="} {
+		if validPKCEValue(v) {
+			t.Errorf("validPKCEValue(%q) = true", v)
+		}
+	}
+}
+
+func TestOAuthCodeFlow(t *testing.T) {
+	for _, confidential := range []bool{true, false} {
+		ctx := context.Background()
+		f := newOAuthFixture(t)
+		owner, alice := f.addUser(t, "owner"), f.addUser(t, "alice")
+		c := f.registerClient(t, owner, confidential)
+		if (c.Secret != "") != confidential {
+			t.Fatalf("confidential %v client with secret %q", confidential, c.Secret)
+		}
+
+		tokens := f.oauthTokens(t, alice, c, user.ScopeArticlesRead)
+		if !strings.HasPrefix(tokens.RefreshToken, OAuthRefreshPrefix) || !slices.Equal(tokens.Scopes, []string{user.ScopeArticlesRead}) {
+			t.Errorf("tokens = %+v", tokens)
+		}
+
+		id, err := f.svc.Authenticate(ctx, tokens.AccessToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id.UserID != alice.ID || id.ClientID != c.ID || !id.Scoped() {
+			t.Errorf("identity = %+v", id)
+		}
+		if !id.Can(user.ScopeArticlesRead) || id.Can(user.ScopeArticlesWrite) || id.Can(user.ScopeProfileRead) {
+			t.Errorf("access token scopes = %q", id.Scopes)
+		}
+	}
+}
+
+func TestAuthorizeValidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(r *AuthorizeRequest)
+		code     string
+		redirect bool
+	}{
+		{"unknown client", func(r *AuthorizeRequest) { r.ClientID = uuid.NewString() }, "invalid_client", false},
+		{"unregistered redirect", func(r *AuthorizeRequest) { r.RedirectURI = "https://evil.example/cb" }, "invalid_request", false},
+		{"no challenge", func(r *AuthorizeRequest) { r.CodeChallenge = "" }, "invalid_request", true},
+		{"plain challenge", func(r *AuthorizeRequest) { r.CodeChallengeMethod = "plain" }, "invalid_request", true},
+		{"token response", func(r *AuthorizeRequest) { r.ResponseType = "token" }, "unsupported_response_type", true},
+		{"scope beyond the client", func(r *AuthorizeRequest) { r.Scope = user.ScopeArticlesRead + " admin" }, "invalid_scope", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOAuthFixture(t)
+			alice := f.addUser(t, "alice")
+			c := f.registerClient(t, alice, true)
+
+			req := authorizeRequest(c, "")
+			tt.change(&req)
+			_, err := f.svc.Authorize(context.Background(), alice.ID, req, true)
+
+			var oe *OAuthError
+			if !errors.As(err, &oe) || oe.Code != tt.code {
+				t.Fatalf("Authorize = %v, want %s", err, tt.code)
+			}
+			if got := strings.HasPrefix(oe.RedirectTo, testRedirect+"?"); got != tt.redirect {
+				t.Errorf("redirect to %q", oe.RedirectTo)
+			}
+			if len(f.codes.byHash) != 0 {
+				t.Error("code issued")
+			}
+		})
+	}
+}
+
+func TestAuthorizeDenied(t *testing.T) {
+	f := newOAuthFixture(t)
+	alice := f.addUser(t, "alice")
+	c := f.registerClient(t, alice, true)
+
+	to, err := f.svc.Authorize(context.Background(), alice.ID, authorizeRequest(c, ""), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(to)
+	if u.Query().Get("error") != "access_denied" || u.Query().Get("code") != "" || len(f.codes.byHash) != 0 {
+		t.Errorf("denied request redirects to %q", to)
+	}
+}
+
+func TestExchangeOAuthCodeRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		cc       func(c, other *NewOAuthClient) ClientCredentials
+		redirect string
+		verifier string
+		codeTTL  time.Duration
+		want     string
+	}{
+		{"wrong verifier", nil, testRedirect, strings.Repeat("a", 43), 0, "invalid_grant"},
+		{"no verifier", nil, testRedirect, "", 0, "invalid_request"},
+		{"other redirect", nil, testRedirect + "/x", testVerifier, 0, "invalid_grant"},
+		{"expired code", nil, testRedirect, testVerifier, -time.Second, "invalid_grant"},
+		{"code of another client", func(_, other *NewOAuthClient) ClientCredentials {
+			return credentials(other)
+		}, testRedirect, testVerifier, 0, "invalid_grant"},
+		{"wrong secret", func(c, _ *NewOAuthClient) ClientCredentials {
+			return ClientCredentials{ID: c.ID.String(), Secret: OAuthSecretPrefix + "nope"}
+		}, testRedirect, testVerifier, 0, "invalid_client"},
+		{"no secret", func(c, _ *NewOAuthClient) ClientCredentials {
+			return ClientCredentials{ID: c.ID.String()}
+		}, testRedirect, testVerifier, 0, "invalid_client"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newOAuthFixture(t)
+			if tt.codeTTL != 0 {
+				f.svc.cfg.OAuthCodeTTL = tt.codeTTL
+			}
+			alice := f.addUser(t, "alice")
+			c, other := f.registerClient(t, alice, true), f.registerClient(t, alice, true)
+			code := f.authorizeCode(t, alice, c, "")
+
+			cc := credentials(c)
+			if tt.cc != nil {
+				cc = tt.cc(c, other)
+			}
+			tokens, err := f.svc.ExchangeOAuthCode(ctx, cc, code, tt.redirect, tt.verifier)
+			if got := oauthErrorCode(err); got != tt.want {
+				t.Fatalf("ExchangeOAuthCode = %v, want %s", err, tt.want)
+			}
+			if tokens != nil || len(f.grants.byID) != 0 {
+				t.Errorf("tokens issued: %+v", tokens)
+			}
+		})
+	}
+
+	t.Run("public client with a secret", func(t *testing.T) {
+		f := newOAuthFixture(t)
+		alice := f.addUser(t, "alice")
+		c := f.registerClient(t, alice, false)
+		code := f.authorizeCode(t, alice, c, "")
+
+		cc := ClientCredentials{ID: c.ID.String(), Secret: "guess"}
+		if _, err := f.svc.ExchangeOAuthCode(context.Background(), cc, code, testRedirect, testVerifier); oauthErrorCode(err) != "invalid_client" {
+			t.Errorf("ExchangeOAuthCode = %v, want invalid_client", err)
+		}
+	})
+}
+
+func TestOAuthCodeIsUsedOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newOAuthFixture(t)
+	alice := f.addUser(t, "alice")
+	c := f.registerClient(t, alice, true)
+
+	code := f.authorizeCode(t, alice, c, "")
+	if _, err := f.svc.ExchangeOAuthCode(ctx, credentials(c), code, testRedirect, testVerifier); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.ExchangeOAuthCode(ctx, credentials(c), code, testRedirect, testVerifier); oauthErrorCode(err) != "invalid_grant" {
+		t.Errorf("reused code = %v, want invalid_grant", err)
+	}
+
+	// a failed attempt burns the code too, the verifier can't be guessed
+	code = f.authorizeCode(t, alice, c, "")
+	if _, err := f.svc.ExchangeOAuthCode(ctx, credentials(c), code, testRedirect, strings.Repeat("a", 43)); oauthErrorCode(err) != "invalid_grant" {
+		t.Fatalf("wrong verifier = %v, want invalid_grant", err)
+	}
+	if _, err := f.svc.ExchangeOAuthCode(ctx, credentials(c), code, testRedirect, testVerifier); oauthErrorCode(err) != "invalid_grant" {
+		t.Errorf("code after a failed attempt = %v, want invalid_grant", err)
+	}
+	if len(f.grants.byID) != 1 {
+		t.Errorf("%d grants, want 1", len(f.grants.byID))
+	}
+}
+
+func TestRefreshOAuthTokenRotates(t *testing.T) {
+	ctx := context.Background()
+	f := newOAuthFixture(t)
+	alice := f.addUser(t, "alice")
+	c, other := f.registerClient(t, alice, true), f.registerClient(t, alice, true)
+	first := f.oauthTokens(t, alice, c, "")
+
+	if _, err := f.svc.RefreshOAuthToken(ctx, credentials(other), first.RefreshToken, ""); oauthErrorCode(err) != "invalid_grant" {
+		t.Fatalf("refresh by another client = %v, want invalid_grant", err)
+	}
+
+	second, err := f.svc.RefreshOAuthToken(ctx, credentials(c), first.RefreshToken, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken || !slices.Equal(second.Scopes, first.Scopes) {
+		t.Errorf("refreshed tokens = %+v", second)
+	}
+
+	// the old refresh token is gone, the new one works once
+	if _, err := f.svc.RefreshOAuthToken(ctx, credentials(c), first.RefreshToken, ""); oauthErrorCode(err) != "invalid_grant" {
+		t.Errorf("rotated refresh token = %v, want invalid_grant", err)
+	}
+	third, err := f.svc.RefreshOAuthToken(ctx, credentials(c), second.RefreshToken, user.ScopeArticlesRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(third.Scopes, []string{user.ScopeArticlesRead}) {
+		t.Errorf("narrowed scopes = %q", third.Scopes)
+	}
+	if id, err := f.svc.Authenticate(ctx, third.AccessToken); err != nil || id.Can(user.ScopeArticlesWrite) {
+		t.Errorf("narrowed access token = %+v, %v", id, err)
+	}
+
+	// narrowing one token doesn't narrow the grant, widening it is refused
+	if _, err := f.svc.RefreshOAuthToken(ctx, credentials(c), third.RefreshToken, user.ScopeArticlesWrite); err != nil {
+		t.Errorf("refresh with a granted scope = %v", err)
+	}
+	if len(f.grants.byID) != 1 {
+		t.Errorf("%d grants, want 1", len(f.grants.byID))
+	}
+}
+
+func TestRefreshOAuthTokenRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		scope  string
+		change func(f *fixture, u *user.User)
+		want   string
+	}{
+		{"wider scope", user.ScopeArticlesWrite, nil, "invalid_scope"},
+		{"expired grant", "", func(f *fixture, _ *user.User) {
+			for _, g := range f.grants.byID {
+				g.ExpiresAt = time.Now().Add(-time.Second)
+			}
+		}, "invalid_grant"},
+		{"app revoked", "", func(f *fixture, u *user.User) {
+			for _, c := range f.clients.byID {
+				_ = f.svc.RevokeAuthorizedApp(context.Background(), u.ID, c.ID)
+			}
+		}, "invalid_grant"},
+		{"user suspended", "", func(f *fixture, u *user.User) {
+			now := time.Now()
+			f.users.byID[u.ID].SuspendedAt = &now
+		}, "invalid_grant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOAuthFixture(t)
+			alice := f.addUser(t, "alice")
+			c := f.registerClient(t, alice, true)
+			tokens := f.oauthTokens(t, alice, c, user.ScopeArticlesRead)
+			if tt.change != nil {
+				tt.change(f, alice)
+			}
+
+			_, err := f.svc.RefreshOAuthToken(context.Background(), credentials(c), tokens.RefreshToken, tt.scope)
+			if got := oauthErrorCode(err); got != tt.want {
+				t.Errorf("RefreshOAuthToken = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntrospectOAuthToken(t *testing.T) {
+	ctx := context.Background()
+	f := newOAuthFixture(t)
+	alice := f.addUser(t, "alice")
+	c, other := f.registerClient(t, alice, true), f.registerClient(t, alice, true)
+	old := f.oauthTokens(t, alice, c, "")
+	tokens, err := f.svc.RefreshOAuthToken(ctx, credentials(c), old.RefreshToken, user.ScopeProfileRead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	access, err := f.svc.IntrospectOAuthToken(ctx, credentials(c), tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !access.Active || access.TokenType != "access_token" || access.ClientID != c.ID || access.UserID != alice.ID ||
+		!slices.Equal(access.Scopes, []string{user.ScopeProfileRead}) || access.ExpiresAt.IsZero() {
+		t.Errorf("access token info = %+v", access)
+	}
+
+	refresh, err := f.svc.IntrospectOAuthToken(ctx, credentials(c), tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !refresh.Active || refresh.TokenType != "refresh_token" || refresh.Username != "alice" ||
+		!slices.Equal(refresh.Scopes, []string{user.ScopeArticlesWrite, user.ScopeProfileRead}) {
+		t.Errorf("refresh token info = %+v", refresh)
+	}
+
+	inactive := []struct {
+		name  string
+		cc    ClientCredentials
+		token string
+	}{
+		{"rotated refresh token", credentials(c), old.RefreshToken},
+		{"access token of another client", credentials(other), tokens.AccessToken},
+		{"refresh token of another client", credentials(other), tokens.RefreshToken},
+		{"garbage", credentials(c), "garbage"},
+		{"unknown refresh token", credentials(c), OAuthRefreshPrefix + "nope"},
+	}
+	for _, tt := range inactive {
+		info, err := f.svc.IntrospectOAuthToken(ctx, tt.cc, tt.token)
+		if err != nil || info.Active {
+			t.Errorf("%s: %+v, %v, want inactive", tt.name, info, err)
+		}
+	}
+
+	if _, err := f.svc.IntrospectOAuthToken(ctx, ClientCredentials{ID: c.ID.String()}, tokens.AccessToken); oauthErrorCode(err) != "invalid_client" {
+		t.Errorf("introspection without the secret = %v, want invalid_client", err)
+	}
+}
+
+func TestRevokeOAuthToken(t *testing.T) {
+	ctx := context.Background()
+	f := newOAuthFixture(t)
+	alice := f.addUser(t, "alice")
+	c, other := f.registerClient(t, alice, true), f.registerClient(t, alice, true)
+	tokens := f.oauthTokens(t, alice, c, "")
+
+	// another client's request is ignored
+	if err := f.svc.RevokeOAuthToken(ctx, credentials(other), tokens.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.Authenticate(ctx, tokens.AccessToken); err != nil {
+		t.Fatalf("token revoked by another client: %v", err)
+	}
+
+	if err := f.svc.RevokeOAuthToken(ctx, credentials(c), tokens.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	// revoking the access token ends the grant and the refresh token with it
+	if _, err := f.svc.Authenticate(ctx, tokens.AccessToken); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("revoked access token = %v, want ErrUnauthorized", err)
+	}
+	if _, err := f.svc.RefreshOAuthToken(ctx, credentials(c), tokens.RefreshToken, ""); oauthErrorCode(err) != "invalid_grant" {
+		t.Errorf("refresh after revocation = %v, want invalid_grant", err)
+	}
+	if info, _ := f.svc.IntrospectOAuthToken(ctx, credentials(c), tokens.AccessToken); info == nil || info.Active {
+		t.Errorf("revoked token info = %+v", info)
+	}
+
+	if err := f.svc.RevokeOAuthToken(ctx, credentials(c), "garbage"); err != nil {
+		t.Errorf("revoking an unknown token = %v", err)
+	}
+}
+
+func TestAuthenticateOAuthAfterPasswordChange(t *testing.T) {
+	ctx := context.Background()
+	f := newOAuthFixture(t)
+	alice := f.addUser(t, "alice")
+	c := f.registerClient(t, alice, true)
+	tokens := f.oauthTokens(t, alice, c, "")
+
+	f.users.byID[alice.ID].TokenVersion++
+	if _, err := f.svc.Authenticate(ctx, tokens.AccessToken); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("access token after a password change = %v, want ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,294 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+	"gopress/pkg/token"
+)
+
+var (
+	ErrUnknownProvider      = errors.New("unknown identity provider")
+	ErrExternalLogin        = errors.New("external sign-in failed")
+	ErrIdentityConflict     = errors.New("an account with this email already exists")
+	ErrProvisioningDisabled = errors.New("no account is linked to this identity")
+)
+
+// OIDCProviderConfig is a configured OpenID Connect provider and what a
+// sign-in through it may do.
+type OIDCProviderConfig struct {
+	Provider ports.OIDCProvider
+	// AutoProvision creates an account on the first sign-in (just in time).
+	AutoProvision bool
+	// LinkByEmail attaches the identity to an existing account with the
+	// same email, if the provider says the email is verified. Only enable
+	// it for providers that own the email domain.
+	LinkByEmail bool
+}
+
+const (
+	maxUsernameLen      = 30
+	usernameSuffixTries = 5
+)
+
+func (s *Service) OIDCProviders() []string {
+	names := make([]string, 0, len(s.cfg.OIDCProviders))
+	for _, p := range s.cfg.OIDCProviders {
+		names = append(names, p.Provider.Name())
+	}
+	return names
+}
+
+func (s *Service) oidcProvider(name string) (*OIDCProviderConfig, error) {
+	for i := range s.cfg.OIDCProviders {
+		if s.cfg.OIDCProviders[i].Provider.Name() == name {
+			return &s.cfg.OIDCProviders[i], nil
+		}
+	}
+	return nil, ErrUnknownProvider
+}
+
+// StartOIDCLogin begins an authorization-code + PKCE login. It returns the
+// provider URL to send the user to and the state, which the caller has to
+// bind to the browser (a cookie) and compare in the callback.
+func (s *Service) StartOIDCLogin(ctx context.Context, provider string) (authURL, state string, err error) {
+	pc, err := s.oidcProvider(provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, stateHash, err := token.Generate()
+	if err != nil {
+		return "", "", ErrInternalError
+	}
+	nonce, _, err := token.Generate()
+	if err != nil {
+		return "", "", ErrInternalError
+	}
+	// 43 URL-safe characters, a valid RFC 7636 code verifier
+	verifier, _, err := token.Generate()
+	if err != nil {
+		return "", "", ErrInternalError
+	}
+
+	err = s.oidcStates.Create(ctx, stateHash, user.OIDCLoginState{
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.cfg.OIDCStateTTL),
+	})
+	if err != nil {
+		return "", "", ErrInternalError
+	}
+
+	authURL, err = pc.Provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Printf("oidc %s: %v", provider, err)
+		return "", "", ErrExternalLogin
+	}
+	return authURL, state, nil
+}
+
+// FinishOIDCLogin handles the provider callback: it redeems the code,
+// finds or provisions the linked account and logs it in like Login.
+func (s *Service) FinishOIDCLogin(ctx context.Context, provider, state, code string) (*LoginResult, error) {
+	if state == "" || code == "" {
+		return nil, ErrInvalidData
+	}
+
+	pc, err := s.oidcProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := s.oidcStates.Consume(ctx, token.Hash(state))
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if st == nil || st.Provider != provider || time.Now().After(st.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	claims, err := pc.Provider.Exchange(ctx, code, st.CodeVerifier, st.Nonce)
+	if err != nil {
+		log.Printf("oidc %s: %v", provider, err)
+		return nil, ErrExternalLogin
+	}
+	if claims.Subject == "" {
+		return nil, ErrExternalLogin
+	}
+
+	u, err := s.oidcUser(ctx, pc, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.finishLogin(ctx, u)
+}
+
+// oidcUser returns the account linked to the external identity, linking
+// or provisioning one as the provider config allows.
+func (s *Service) oidcUser(ctx context.Context, pc *OIDCProviderConfig, claims *ports.OIDCClaims) (*user.User, error) {
+	provider := pc.Provider.Name()
+
+	ident, err := s.identities.Get(ctx, provider, claims.Subject)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if ident != nil {
+		if err := s.identities.Touch(ctx, ident.ID, claims.Email); err != nil {
+			log.Printf("touch identity %s: %v", ident.ID, err)
+		}
+		return s.GetMe(ctx, ident.UserID)
+	}
+
+	if claims.Email == "" {
+		log.Printf("oidc %s: no email claim for subject %s", provider, claims.Subject)
+		return nil, ErrExternalLogin
+	}
+
+	existing, err := s.repo.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	var u *user.User
+	switch {
+	case existing != nil && pc.LinkByEmail && claims.EmailVerified:
+		u = existing
+	case existing != nil:
+		return nil, ErrIdentityConflict
+	case !pc.AutoProvision:
+		return nil, ErrProvisioningDisabled
+	default:
+		if u, err = s.provisionUser(ctx, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.identities.Create(ctx, &user.ExternalIdentity{
+		UserID:   u.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return u, nil
+}
+
+// provisionUser creates an account for a first-time external sign-in. It
+// gets a random password: the user can set one with the reset flow.
+func (s *Service) provisionUser(ctx context.Context, claims *ports.OIDCClaims) (*user.User, error) {
+	plain, _, err := token.Generate()
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	hashed, err := s.hasher.Hash(plain)
+	if err != nil {
+		return nil, ErrHashPassword
+	}
+
+	username, err := s.freeUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &user.User{
+		Email:    claims.Email,
+		Username: username,
+		Password: hashed,
+	}
+	ev := auditEvent(ctx, audit.ActionRegister, nil, "")
+	ev.Details = map[string]any{"via": "oidc"}
+	err = s.withAudit(ctx, ev, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, u); err != nil {
+			return ErrCreateUser
+		}
+		ev.ActorID, ev.Target, ev.After = &u.ID, u.ID.String(), userSnapshot(u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.EmailVerified {
+		if err := s.repo.MarkEmailVerified(ctx, u.ID); err != nil {
+			log.Printf("mark email verified for user %s: %v", u.ID, err)
+		}
+	}
+	if name := strings.TrimSpace(claims.Name); name != "" {
+		if _, err := s.UpdateProfile(ctx, u.ID, user.Profile{DisplayName: &name}); err != nil {
+			log.Printf("set display name for user %s: %v", u.ID, err)
+		}
+	}
+
+	return u, nil
+}
+
+// freeUsername derives an unused username from the preferred username or
+// the email, adding a numeric suffix on collisions.
+func (s *Service) freeUsername(ctx context.Context, claims *ports.OIDCClaims) (string, error) {
+	local, _, _ := strings.Cut(claims.Email, "@")
+	base := sanitizeUsername(claims.PreferredUsername)
+	if base == "" {
+		base = sanitizeUsername(local)
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+
+	candidate := base
+	for range usernameSuffixTries {
+		existing, err := s.repo.GetByUsername(ctx, candidate)
+		if err != nil {
+			return "", ErrInternalError
+		}
+		if existing == nil {
+			return candidate, nil
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", ErrInternalError
+		}
+		candidate = fmt.Sprintf("%s%04d", base[:min(len(base), maxUsernameLen-4)], n)
+	}
+	return "", ErrCreateUser
+}
+
+func sanitizeUsername(s string) string {
+	s, _, _ = strings.Cut(strings.ToLower(s), "@")
+
+	var b strings.Builder
+	for _, c := range s {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' || c == '.' || c == '-' {
+			b.WriteRune(c)
+		}
+		if b.Len() == maxUsernameLen {
+			break
+		}
+	}
+	return strings.Trim(b.String(), ".-")
+}
+
+// ListIdentities returns the external identities linked to the account.
+func (s *Service) ListIdentities(ctx context.Context, userID uuid.UUID) ([]user.ExternalIdentity, error) {
+	ids, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return ids, nil
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/audit"
+)
+
+// fakeProvider binds every code to the nonce and code verifier of its
+// authorization request, as a real provider and the ID token check do.
+type fakeProvider struct {
+	name     string
+	claims   ports.OIDCClaims
+	requests map[string]oidcRequest // by code
+}
+
+type oidcRequest struct {
+	nonce, verifier string
+}
+
+func newFakeProvider(name string, claims ports.OIDCClaims) *fakeProvider {
+	return &fakeProvider{name: name, claims: claims, requests: map[string]oidcRequest{}}
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) AuthCodeURL(_ context.Context, state, nonce, codeVerifier string) (string, error) {
+	p.requests["code-"+state] = oidcRequest{nonce: nonce, verifier: codeVerifier}
+	return "https://" + p.name + ".example/auth?" + url.Values{"state": {state}}.Encode(), nil
+}
+
+func (p *fakeProvider) Exchange(_ context.Context, code, codeVerifier, nonce string) (*ports.OIDCClaims, error) {
+	req, ok := p.requests[code]
+	if !ok {
+		return nil, errors.New("invalid_grant")
+	}
+	delete(p.requests, code)
+	if codeVerifier != req.verifier {
+		return nil, errors.New("invalid_grant: code verifier")
+	}
+	if nonce != req.nonce {
+		return nil, errors.New("id token: nonce mismatch")
+	}
+	c := p.claims
+	return &c, nil
+}
+
+func newOIDCFixture(t *testing.T, providers ...OIDCProviderConfig) *fixture {
+	t.Helper()
+	return newFixture(t, Config{OIDCProviders: providers, OIDCStateTTL: time.Minute})
+}
+
+// startOIDC begins a login and returns the state and the code the
+// provider sends back with it.
+func startOIDC(t *testing.T, f *fixture, provider string) (state, code string) {
+	t.Helper()
+	authURL, state, err := f.svc.StartOIDCLogin(context.Background(), provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil || u.Query().Get("state") != state {
+		t.Fatalf("auth URL %q doesn't carry state %q", authURL, state)
+	}
+	return state, "code-" + state
+}
+
+var aliceClaims = ports.OIDCClaims{
+	Subject:           "sub-1",
+	Email:             "alice@example.com",
+	EmailVerified:     true,
+	Name:              "Alice",
+	PreferredUsername: "alice",
+}
+
+func TestOIDCLoginProvisions(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t, OIDCProviderConfig{Provider: newFakeProvider("idp", aliceClaims), AutoProvision: true})
+
+	state, code := startOIDC(t, f, "idp")
+	if _, ok := f.oidcStates.byHash[state]; ok || len(f.oidcStates.byHash) != 1 {
+		t.Errorf("states = %v, want one stored by hash", f.oidcStates.byHash)
+	}
+
+	res, err := f.svc.FinishOIDCLogin(ctx, "idp", state, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := f.svc.Authenticate(ctx, res.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := f.users.byID[id.UserID]
+	if u.Username != "alice" || u.Email != aliceClaims.Email || !u.EmailVerified() || u.DisplayName != "Alice" {
+		t.Errorf("provisioned user = %+v", u)
+	}
+	if got := f.audit.actions(); !slices.Equal(got, []string{audit.ActionRegister, audit.ActionLogin}) {
+		t.Errorf("audit = %q", got)
+	}
+
+	// the second sign-in finds the linked account
+	state, code = startOIDC(t, f, "idp")
+	res, err = f.svc.FinishOIDCLogin(ctx, "idp", state, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := f.svc.Authenticate(ctx, res.Token); id == nil || id.UserID != u.ID {
+		t.Errorf("second sign-in as %+v, want %s", id, u.ID)
+	}
+	if len(f.users.byID) != 1 || len(f.identities.byID) != 1 {
+		t.Errorf("%d users, %d identities after two sign-ins", len(f.users.byID), len(f.identities.byID))
+	}
+}
+
+func TestOIDCStateIsUsedOnce(t *testing.T) {
+	ctx := context.Background()
+	f := newOIDCFixture(t, OIDCProviderConfig{Provider: newFakeProvider("idp", aliceClaims), AutoProvision: true})
+
+	state, code := startOIDC(t, f, "idp")
+	if _, err := f.svc.FinishOIDCLogin(ctx, "idp", state, code); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.FinishOIDCLogin(ctx, "idp", state, code); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("replayed state = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestOIDCStateRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		finish func(t *testing.T, f *fixture) (*LoginResult, error)
+		want   error
+	}{
+		{"unknown state", func(t *testing.T, f *fixture) (*LoginResult, error) {
+			state, code := startOIDC(t, f, "idp")
+			return f.svc.FinishOIDCLogin(context.Background(), "idp", state+"x", code)
+		}, ErrInvalidToken},
+		{"state of another provider", func(t *testing.T, f *fixture) (*LoginResult, error) {
+			state, code := startOIDC(t, f, "other")
+			return f.svc.FinishOIDCLogin(context.Background(), "idp", state, code)
+		}, ErrInvalidToken},
+		{"expired state", func(t *testing.T, f *fixture) (*LoginResult, error) {
+			f.svc.cfg.OIDCStateTTL = -time.Second
+			state, code := startOIDC(t, f, "idp")
+			return f.svc.FinishOIDCLogin(context.Background(), "idp", state, code)
+		}, ErrInvalidToken},
+		{"code of another login", func(t *testing.T, f *fixture) (*LoginResult, error) {
+			state, _ := startOIDC(t, f, "idp")
+			_, code := startOIDC(t, f, "idp")
+			return f.svc.FinishOIDCLogin(context.Background(), "idp", state, code)
+		}, ErrExternalLogin},
+		{"unknown provider", func(t *testing.T, f *fixture) (*LoginResult, error) {
+			state, code := startOIDC(t, f, "idp")
+			return f.svc.FinishOIDCLogin(context.Background(), "nope", state, code)
+		}, ErrUnknownProvider},
+		{"no state", func(t *testing.T, f *fixture) (*LoginResult, error) {
+			_, code := startOIDC(t, f, "idp")
+			return f.svc.FinishOIDCLogin(context.Background(), "idp", "", code)
+		}, ErrInvalidData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newOIDCFixture(t,
+				OIDCProviderConfig{Provider: newFakeProvider("idp", aliceClaims), AutoProvision: true},
+				OIDCProviderConfig{Provider: newFakeProvider("other", aliceClaims), AutoProvision: true},
+			)
+			res, err := tt.finish(t, f)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("FinishOIDCLogin = %v, want %v", err, tt.want)
+			}
+			if res != nil || len(f.users.byID) != 0 {
+				t.Errorf("signed in: %+v, %d users", res, len(f.users.byID))
+			}
+		})
+	}
+}
+
+func TestOIDCStartUnknownProvider(t *testing.T) {
+	f := newOIDCFixture(t)
+	if _, _, err := f.svc.StartOIDCLogin(context.Background(), "idp"); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("StartOIDCLogin = %v, want ErrUnknownProvider", err)
+	}
+	if len(f.oidcStates.byHash) != 0 {
+		t.Error("state stored for an unknown provider")
+	}
+}
+
+func TestOIDCLinking(t *testing.T) {
+	unverified := aliceClaims
+	unverified.EmailVerified = false
+	noEmail := aliceClaims
+	noEmail.Email = ""
+
+	tests := []struct {
+		name     string
+		cfg      OIDCProviderConfig
+		claims   ports.OIDCClaims
+		existing bool
+		want     error
+	}{
+		{"link by verified email", OIDCProviderConfig{LinkByEmail: true}, aliceClaims, true, nil},
+		{"link by unverified email", OIDCProviderConfig{LinkByEmail: true, AutoProvision: true}, unverified, true, ErrIdentityConflict},
+		{"email taken", OIDCProviderConfig{AutoProvision: true}, aliceClaims, true, ErrIdentityConflict},
+		{"provisioning off", OIDCProviderConfig{LinkByEmail: true}, aliceClaims, false, ErrProvisioningDisabled},
+		{"no email", OIDCProviderConfig{AutoProvision: true}, noEmail, false, ErrExternalLogin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Provider = newFakeProvider("idp", tt.claims)
+			f := newOIDCFixture(t, tt.cfg)
+			if tt.existing {
+				f.addUser(t, "alice")
+			}
+
+			state, code := startOIDC(t, f, "idp")
+			_, err := f.svc.FinishOIDCLogin(context.Background(), "idp", state, code)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("FinishOIDCLogin = %v, want %v", err, tt.want)
+			}
+			linked := len(f.identities.byID) == 1
+			if linked != (tt.want == nil) {
+				t.Errorf("%d identities linked", len(f.identities.byID))
+			}
+		})
+	}
+}
+
+func TestSanitizeUsername(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Alice", "alice"},
+		{"alice@example.com", "alice"},
+		{".a l-i_c.e-", "al-i_c.e"},
+		{"Ünïcode", "ncode"},
+		{strings.Repeat("a", 40), strings.Repeat("a", maxUsernameLen)},
+	}
+	for _, tt := range tests {
+		if got := sanitizeUsername(tt.in); got != tt.want {
+			t.Errorf("sanitizeUsername(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"gopress/pkg/password"
+)
+
+var ErrWeakPassword = errors.New("password does not satisfy policy")
+
+// PasswordPolicyError tells the user why a new password was rejected.
+type PasswordPolicyError struct {
+	Reason string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return e.Reason
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// validatePassword applies the password policy and the breached password
+// list to a new password. userInputs (username, email) must not be part of
+// it.
+func (s *Service) validatePassword(ctx context.Context, plain string, userInputs ...string) error {
+	if err := s.cfg.PasswordPolicy.Validate(plain, userInputs...); err != nil {
+		var pe *password.PolicyError
+		if errors.As(err, &pe) {
+			return &PasswordPolicyError{Reason: pe.Error()}
+		}
+		return ErrInternalError
+	}
+
+	breached, err := s.breached.IsBreached(ctx, plain)
+	if err != nil {
+		// the list is a safety net, don't lock users out when it's unreadable
+		log.Printf("breached password check: %v", err)
+		return nil
+	}
+	if breached {
+		return &PasswordPolicyError{Reason: "password has appeared in a data breach, choose another one"}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+)
+
+const (
+	maxDisplayNameLen = 100
+	maxBioLen         = 1000
+	maxAvatarURLLen   = 2048
+)
+
+func (s *Service) UpdateProfile(ctx context.Context, userID uuid.UUID, p user.Profile) (*user.User, error) {
+	if !validProfile(p) {
+		return nil, ErrInvalidData
+	}
+
+	u, err := s.repo.UpdateProfile(ctx, userID, p)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// ChangePassword sets a new password and invalidates every issued token.
+// It returns a fresh token for the caller's session so only the other
+// sessions are logged out. Callers without a live session of the user,
+// like API keys and OAuth tokens, get no token.
+func (s *Service) ChangePassword(ctx context.Context, userID, sessionID uuid.UUID, currentPassword, newPassword string) (string, error) {
+	if currentPassword == "" || newPassword == "" {
+		return "", ErrInvalidData
+	}
+
+	u, err := s.GetMe(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if !s.checkPassword(u.Password, currentPassword) {
+		return "", ErrWrongPassword
+	}
+	if err := s.validatePassword(ctx, newPassword, u.Username, u.Email); err != nil {
+		return "", err
+	}
+
+	hashed, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return "", ErrHashPassword
+	}
+
+	var (
+		sess    *user.Session
+		version int
+		revoked []uuid.UUID
+	)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if sessionID != uuid.Nil {
+			if sess, err = s.sessions.Get(ctx, sessionID); err != nil {
+				return ErrInternalError
+			}
+			if !sess.Active(time.Now()) || sess.UserID != userID {
+				sess = nil
+			}
+		}
+		keep := uuid.Nil
+		if sess != nil {
+			keep = sess.ID
+		}
+
+		if version, err = s.repo.UpdatePassword(ctx, userID, hashed); err != nil {
+			return ErrInternalError
+		}
+		if revoked, err = s.sessions.RevokeAll(ctx, userID, keep); err != nil {
+			return ErrInternalError
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	for _, id := range revoked {
+		s.sessionCache.revoke(id)
+	}
+	if sess == nil {
+		return "", nil
+	}
+
+	// same session, new token version; the cached entry still has the old one
+	s.sessionCache.forget(sess.ID)
+
+	token, err := s.jwtManager.GenerateToken(u.ID, u.Username, version, sessionID, sess.ExpiresAt)
+	if err != nil {
+		return "", ErrInternalError
+	}
+	return token, nil
+}
+
+// DeleteAccount removes the user. Their articles go to the trash in the
+// same transaction and are purged with it after the trash retention;
+// their media files lose their owner and go with the media purge.
+func (s *Service) DeleteAccount(ctx context.Context, userID uuid.UUID, userPassword string) error {
+	if userPassword == "" {
+		return ErrInvalidData
+	}
+
+	u, err := s.GetMe(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !s.checkPassword(u.Password, userPassword) {
+		return ErrWrongPassword
+	}
+
+	ev := auditEvent(ctx, audit.ActionUserDelete, &u.ID, u.ID.String())
+	ev.Before = userSnapshot(u)
+	return s.withAudit(ctx, ev, func(ctx context.Context) error {
+		if err := s.RevokeAllSessions(ctx, u.ID, uuid.Nil); err != nil {
+			return err
+		}
+		if err := s.repo.Delete(ctx, u.ID); err != nil {
+			return ErrInternalError
+		}
+		return nil
+	})
+}
+
+func validProfile(p user.Profile) bool {
+	if p.DisplayName != nil && utf8.RuneCountInString(*p.DisplayName) > maxDisplayNameLen {
+		return false
+	}
+	if p.Bio != nil && utf8.RuneCountInString(*p.Bio) > maxBioLen {
+		return false
+	}
+	if p.AvatarURL != nil && *p.AvatarURL != "" {
+		if len(*p.AvatarURL) > maxAvatarURLLen {
+			return false
+		}
+		u, err := url.Parse(*p.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+	"gopress/pkg/token"
+)
+
+const mailTimeout = time.Minute
+
+// RequestPasswordReset emails a single-use reset link. It reports success
+// whether or not the email is registered, and mails in the background so
+// the response time doesn't tell either. Requests are throttled per email
+// and client IP; throttled ones also report success but send nothing.
+func (s *Service) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return ErrInvalidData
+	}
+
+	// counted before the lookup, unknown emails are throttled alike
+	keys := s.resetKeys(ctx, email)
+	if err := s.checkThrottle(ctx, keys); err != nil {
+		if errors.Is(err, ErrTooManyAttempts) {
+			return nil
+		}
+		return err
+	}
+	s.countFailures(ctx, keys)
+
+	u, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		return ErrInternalError
+	}
+	if u == nil {
+		return nil
+	}
+
+	return s.sendResetLink(ctx, u,
+		"someone asked to reset the password for your account.",
+		"If it wasn't you, just ignore this email.")
+}
+
+// sendResetLink emails a new reset link; reason and footer frame it.
+func (s *Service) sendResetLink(ctx context.Context, u *user.User, reason, footer string) error {
+	plain, hash, err := token.Generate()
+	if err != nil {
+		return ErrInternalError
+	}
+	if err := s.resets.Create(ctx, u.ID, hash, time.Now().Add(s.cfg.ResetTokenTTL)); err != nil {
+		return ErrInternalError
+	}
+
+	mail := ports.Mail{
+		To:      u.Email,
+		Subject: "Reset your gopress password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\n%s\n"+
+				"Open the link below to choose a new one:\n\n%s%s\n\n"+
+				"The link expires in %s and can be used once.\n"+
+				"%s\n",
+			u.Username, reason, s.cfg.ResetURL, plain, s.cfg.ResetTokenTTL, footer,
+		),
+	}
+	s.sendAsync(ctx, mail)
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and logs out every
+// existing session.
+func (s *Service) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	if resetToken == "" || newPassword == "" {
+		return ErrInvalidData
+	}
+
+	tokenHash := token.Hash(resetToken)
+
+	// check the new password before using up the token, so a rejected
+	// password can be retried with the same link
+	userID, err := s.resets.Peek(ctx, tokenHash)
+	if err != nil {
+		return ErrInternalError
+	}
+	if userID == uuid.Nil {
+		return ErrInvalidToken
+	}
+	u, err := s.GetMe(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.validatePassword(ctx, newPassword, u.Username, u.Email); err != nil {
+		return err
+	}
+
+	hashed, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return ErrHashPassword
+	}
+
+	// the token is only used up together with the change, and no session
+	// outlives it
+	var revoked []uuid.UUID
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		userID, err := s.resets.Consume(ctx, tokenHash)
+		if err != nil {
+			return ErrInternalError
+		}
+		if userID == uuid.Nil {
+			return ErrInvalidToken
+		}
+
+		// bumps token_version: all issued tokens stop working
+		if _, err := s.repo.UpdatePassword(ctx, userID, hashed); err != nil {
+			return ErrInternalError
+		}
+		if revoked, err = s.sessions.RevokeAll(ctx, userID, uuid.Nil); err != nil {
+			return ErrInternalError
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, id := range revoked {
+		s.sessionCache.revoke(id)
+	}
+	return nil
+}
+
+func (s *Service) sendAsync(ctx context.Context, mail ports.Mail) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), mailTimeout)
+	go func() {
+		defer cancel()
+		if err := s.mailer.Send(ctx, mail); err != nil {
+			log.Printf("send mail to %s: %v", mail.To, err)
+		}
+	}()
+}
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"github.com/google/uuid"
 	"gopress/internal/app/ports"
+	"gopress/internal/domain/audit"
 	"gopress/internal/domain/user"
 	"gopress/pkg/jwt"
 	"gopress/pkg/password"
+	"log"
+	"sync"
+	"time"
 )
 
 var (
@@ -16,50 +20,254 @@ var (
 	ErrCreateUser    = errors.New("cannot create user")
 	ErrUserNotFound  = errors.New("user not found")
 	ErrInternalError = errors.New("internal error")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrWrongPassword = errors.New("wrong password")
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrTooManyEmails = errors.New("too many emails, try later")
+	ErrVerified      = errors.New("email already verified")
+	ErrMFAEnabled    = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled = errors.New("two-factor authentication not enabled")
+	ErrInvalidCode   = errors.New("invalid code")
 )
 
+// Identity is the authenticated caller behind a token.
+type Identity struct {
+	UserID    uuid.UUID
+	Username  string
+	SessionID uuid.UUID
+
+	// APIKeyID is set when the caller used an API key, ClientID when an
+	// OAuth client acts for the user. Both are limited to Scopes, session
+	// tokens may do anything.
+	APIKeyID uuid.UUID
+	ClientID uuid.UUID
+	Scopes   []string
+}
+
+func (i *Identity) IsAPIKey() bool {
+	return i.APIKeyID != uuid.Nil
+}
+
+func (i *Identity) IsOAuthClient() bool {
+	return i.ClientID != uuid.Nil
+}
+
+// Scoped reports whether the caller is limited to Scopes.
+func (i *Identity) Scoped() bool {
+	return i.IsAPIKey() || i.IsOAuthClient()
+}
+
+// Can reports whether the caller may act within scope.
+func (i *Identity) Can(scope string) bool {
+	return !i.Scoped() || user.GrantsScope(i.Scopes, scope)
+}
+
+type Config struct {
+	// ResetURL is the password reset page, the token is appended to it.
+	ResetURL      string
+	ResetTokenTTL time.Duration
+
+	// VerifyURL is the email confirmation page, the token is appended to it.
+	VerifyURL      string
+	VerifyTokenTTL time.Duration
+	// VerifyResendInterval throttles verification emails per user.
+	VerifyResendInterval time.Duration
+
+	// MFAIssuer is shown in authenticator apps.
+	MFAIssuer       string
+	MFAChallengeTTL time.Duration
+
+	// failed login throttling per username and per client IP
+	UserThrottle ThrottlePolicy
+	IPThrottle   ThrottlePolicy
+	// password reset requests per email and per client IP, every request
+	// counts as a failure
+	ResetEmailThrottle ThrottlePolicy
+	ResetIPThrottle    ThrottlePolicy
+	// RunThrottleCleanup drops counters past their window every
+	// ThrottleCleanupInterval.
+	ThrottleCleanupInterval time.Duration
+
+	// Hasher hashes new passwords, password.Default() when nil.
+	Hasher *password.Hasher
+	// PasswordPolicy applies to register, change and reset.
+	PasswordPolicy password.Policy
+
+	// SessionCacheTTL bounds how long a session check is served from memory.
+	SessionCacheTTL time.Duration
+	// SessionTouchInterval throttles last_used_at updates.
+	SessionTouchInterval time.Duration
+
+	// OIDCProviders are the external identity providers, OIDCStateTTL is
+	// how long a user has to finish signing in there.
+	OIDCProviders []OIDCProviderConfig
+	OIDCStateTTL  time.Duration
+
+	// OAuth authorization server: code, access token and refresh token
+	// lifetimes. Refresh tokens are rotated, each use restarts the TTL.
+	OAuthCodeTTL         time.Duration
+	OAuthAccessTokenTTL  time.Duration
+	OAuthRefreshTokenTTL time.Duration
+
+	// AuditRetention is how long audit events are kept, 0 keeps them
+	// forever. RunAuditRetention purges every AuditPurgeInterval.
+	AuditRetention     time.Duration
+	AuditPurgeInterval time.Duration
+}
+
 type Service struct {
 	repo       ports.UserRepo
+	sessions   ports.SessionRepo
+	apiKeys    ports.APIKeyRepo
+	identities ports.UserIdentityRepo
+	oidcStates ports.OIDCStateRepo
+	oauth      oauthRepos
+	resets     ports.PasswordResetRepo
+	verifies   ports.EmailVerificationRepo
+	mfa        ports.MFARepo
+	throttle   ports.LoginThrottleRepo
+	breached   ports.BreachedPasswords
+	audit      ports.AuditLog
+	tx         ports.Transactor
+	mailer     ports.Mailer
 	jwtManager *jwt.Manager
+	hasher     *password.Hasher
+	cfg        Config
+
+	sessionCache *sessionCache
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
-func NewService(repo ports.UserRepo, jwtManager *jwt.Manager) *Service {
+func NewService(
+	repo ports.UserRepo,
+	sessions ports.SessionRepo,
+	apiKeys ports.APIKeyRepo,
+	identities ports.UserIdentityRepo,
+	oidcStates ports.OIDCStateRepo,
+	oauthClients ports.OAuthClientRepo,
+	oauthCodes ports.OAuthCodeRepo,
+	oauthGrants ports.OAuthGrantRepo,
+	resets ports.PasswordResetRepo,
+	verifies ports.EmailVerificationRepo,
+	mfa ports.MFARepo,
+	throttle ports.LoginThrottleRepo,
+	breached ports.BreachedPasswords,
+	auditLog ports.AuditLog,
+	tx ports.Transactor,
+	mailer ports.Mailer,
+	jwtManager *jwt.Manager,
+	cfg Config,
+) *Service {
+	hasher := cfg.Hasher
+	if hasher == nil {
+		hasher = password.Default()
+	}
+
 	return &Service{
 		repo:       repo,
+		sessions:   sessions,
+		apiKeys:    apiKeys,
+		identities: identities,
+		oidcStates: oidcStates,
+		oauth:      oauthRepos{clients: oauthClients, codes: oauthCodes, grants: oauthGrants},
+		resets:     resets,
+		verifies:   verifies,
+		mfa:        mfa,
+		throttle:   throttle,
+		breached:   breached,
+		audit:      auditLog,
+		tx:         tx,
+		mailer:     mailer,
 		jwtManager: jwtManager,
+		hasher:     hasher,
+		cfg:        cfg,
+
+		sessionCache: newSessionCache(cfg.SessionCacheTTL),
 	}
 }
 
-func (s *Service) Login(ctx context.Context, username, userPassword string) (string, error) {
+// LoginResult holds either an access token or, when the account has a
+// second factor, an MFA challenge token for LoginMFA.
+type LoginResult struct {
+	Token    string
+	MFAToken string
+}
+
+func (s *Service) Login(ctx context.Context, username, userPassword string) (*LoginResult, error) {
 	if username == "" || userPassword == "" {
-		return "", ErrInvalidData
+		return nil, ErrInvalidData
+	}
+
+	keys := s.loginKeys(ctx, username)
+	if err := s.checkThrottle(ctx, keys); err != nil {
+		return nil, err
 	}
+
 	u, err := s.repo.GetByUsername(ctx, username)
 	if err != nil {
-		return "", ErrInternalError
+		return nil, ErrInternalError
 	}
 	if u == nil {
-		return "", ErrInvalidData
+		s.checkPasswordUnknownUser(userPassword)
+		s.recordFailure(ctx, keys, nil)
+		return nil, ErrInvalidData
+	}
+
+	ok, rehash, err := s.hasher.Verify(u.Password, userPassword)
+	if err != nil {
+		log.Printf("verify password of user %s: %v", u.ID, err)
+	}
+	if !ok {
+		s.recordFailure(ctx, keys, &u.ID)
+		return nil, ErrInvalidData
 	}
+	s.resetThrottle(ctx, keys[0].key)
 
-	if !password.Check(u.Password, userPassword) {
-		return "", ErrInvalidData
+	if rehash {
+		s.rehashPassword(ctx, u.ID, userPassword)
+	}
+
+	return s.finishLogin(ctx, u)
+}
+
+// finishLogin logs in an authenticated user: an MFA challenge if the
+// account has a second factor, a new session otherwise.
+func (s *Service) finishLogin(ctx context.Context, u *user.User) (*LoginResult, error) {
+	if u.Suspended() {
+		return nil, ErrSuspended
+	}
+
+	t, err := s.mfa.GetTOTP(ctx, u.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if t.Enabled() {
+		mfaToken, err := s.jwtManager.GeneratePurposeToken(u.ID, mfaPurpose, s.cfg.MFAChallengeTTL)
+		if err != nil {
+			return nil, ErrInternalError
+		}
+		return &LoginResult{MFAToken: mfaToken}, nil
 	}
 
-	token, err := s.jwtManager.GenerateToken(u.ID, u.Username)
+	token, err := s.startSession(ctx, u)
 	if err != nil {
-		return "", ErrInternalError
+		return nil, err
 	}
 
-	return token, nil
+	return &LoginResult{Token: token}, nil
 }
 
 func (s *Service) Register(ctx context.Context, username, email, userPassword string) (*user.User, error) {
 	if username == "" || email == "" || userPassword == "" {
 		return nil, ErrInvalidData
 	}
+	if err := s.validatePassword(ctx, userPassword, username, email); err != nil {
+		return nil, err
+	}
 
-	hashed, err := password.Hash(userPassword)
+	hashed, err := s.hasher.Hash(userPassword)
 	if err != nil {
 		return nil, ErrHashPassword
 	}
@@ -70,8 +278,21 @@ func (s *Service) Register(ctx context.Context, username, email, userPassword st
 		Password: hashed,
 	}
 
-	if err := s.repo.Create(ctx, u); err != nil {
-		return nil, ErrCreateUser
+	ev := auditEvent(ctx, audit.ActionRegister, nil, "")
+	err = s.withAudit(ctx, ev, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, u); err != nil {
+			return ErrCreateUser
+		}
+		ev.ActorID, ev.Target, ev.After = &u.ID, u.ID.String(), userSnapshot(u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the account exists already, a failed email can be resent later
+	if err := s.sendVerification(ctx, u); err != nil {
+		log.Printf("send verification to user %s: %v", u.ID, err)
 	}
 
 	return u, nil
@@ -88,3 +309,25 @@ func (s *Service) GetMe(ctx context.Context, userID uuid.UUID) (*user.User, erro
 
 	return u, nil
 }
+
+// checkPassword verifies plain against a stored hash of any known scheme.
+func (s *Service) checkPassword(hash, plain string) bool {
+	ok, _, err := s.hasher.Verify(hash, plain)
+	if err != nil {
+		log.Printf("verify password: %v", err)
+	}
+	return ok
+}
+
+// rehashPassword upgrades a hash made with an old algorithm or old
+// parameters. Sessions stay valid, a failure only postpones the upgrade.
+func (s *Service) rehashPassword(ctx context.Context, userID uuid.UUID, plain string) {
+	hashed, err := s.hasher.Hash(plain)
+	if err != nil {
+		log.Printf("rehash password of user %s: %v", userID, err)
+		return
+	}
+	if err := s.repo.UpdatePasswordHash(ctx, userID, hashed); err != nil {
+		log.Printf("rehash password of user %s: %v", userID, err)
+	}
+}
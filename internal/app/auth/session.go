@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/app/requestinfo"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+const maxUserAgentLen = 512
+
+// startSession records a new login and issues its access token.
+func (s *Service) startSession(ctx context.Context, u *user.User) (string, error) {
+	if u.Suspended() {
+		return "", ErrSuspended
+	}
+
+	info := requestinfo.From(ctx)
+	userAgent := info.UserAgent
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = userAgent[:maxUserAgentLen]
+	}
+
+	sess := &user.Session{
+		UserID:    u.ID,
+		UserAgent: userAgent,
+		IP:        info.IP,
+		ExpiresAt: time.Now().Add(s.jwtManager.TTL()),
+	}
+	ev := auditEvent(ctx, audit.ActionLogin, &u.ID, u.ID.String())
+	err := s.withAudit(ctx, ev, func(ctx context.Context) error {
+		if err := s.sessions.Create(ctx, sess); err != nil {
+			return ErrInternalError
+		}
+		ev.Details = map[string]any{"session_id": sess.ID}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	token, err := s.jwtManager.GenerateToken(u.ID, u.Username, u.TokenVersion, sess.ID, sess.ExpiresAt)
+	if err != nil {
+		return "", ErrInternalError
+	}
+	return token, nil
+}
+
+// ListSessions returns the active sessions of the user, most recently used first.
+func (s *Service) ListSessions(ctx context.Context, userID uuid.UUID) ([]user.Session, error) {
+	sessions, err := s.sessions.ListActive(ctx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	return sessions, nil
+}
+
+// RevokeSession logs out one session of the user, the current one included.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	ok, err := s.sessions.Revoke(ctx, userID, sessionID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	s.sessionCache.revoke(sessionID)
+	return nil
+}
+
+// RevokeAllSessions logs the user out everywhere except keep (uuid.Nil
+// keeps nothing).
+func (s *Service) RevokeAllSessions(ctx context.Context, userID, keep uuid.UUID) error {
+	ids, err := s.sessions.RevokeAll(ctx, userID, keep)
+	if err != nil {
+		return ErrInternalError
+	}
+
+	for _, id := range ids {
+		s.sessionCache.revoke(id)
+	}
+	return nil
+}
+
+// WatchSessions drops sessions revoked on other replicas from the cache
+// until ctx is done. If events may have been missed the whole cache is
+// flushed.
+func (s *Service) WatchSessions(ctx context.Context, bus ports.SessionEventBus) {
+	for ctx.Err() == nil {
+		revoked, err := bus.SubscribeRevoked(ctx)
+		if err != nil {
+			log.Printf("session events: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for id := range revoked {
+			s.sessionCache.revoke(id)
+		}
+		s.sessionCache.clear()
+	}
+}
+
+// Authenticate validates a token and checks that its session is still
+// active and the token hasn't been invalidated (password change, deleted
+// account). Sessions are cached, see SessionCacheTTL. API keys
+// (APIKeyPrefix) are accepted too.
+func (s *Service) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	if strings.HasPrefix(token, APIKeyPrefix) {
+		return s.authenticateAPIKey(ctx, token)
+	}
+
+	claims, err := s.jwtManager.ParseToken(token)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	if claims.ClientID != "" {
+		return s.authenticateOAuth(ctx, claims)
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, ErrUnauthorized
+	}
+
+	now := time.Now()
+	e, ok := s.sessionCache.get(sessionID, now)
+	if !ok {
+		e, err = s.loadSession(ctx, sessionID)
+		if err != nil {
+			return nil, ErrInternalError
+		}
+		e = s.sessionCache.put(sessionID, e, now)
+	}
+
+	if e.revoked || !now.Before(e.expiresAt) || e.userID != userID || e.tokenVersion != claims.TokenVersion {
+		return nil, ErrUnauthorized
+	}
+
+	if s.sessionCache.shouldTouch(sessionID, now, s.cfg.SessionTouchInterval) {
+		if err := s.sessions.Touch(ctx, sessionID, requestinfo.From(ctx).IP, now); err != nil {
+			log.Printf("touch session %s: %v", sessionID, err)
+		}
+	}
+
+	return &Identity{UserID: e.userID, Username: e.username, SessionID: sessionID}, nil
+}
+
+func (s *Service) loadSession(ctx context.Context, id uuid.UUID) (sessionEntry, error) {
+	sess, err := s.sessions.Get(ctx, id)
+	if err != nil {
+		return sessionEntry{}, err
+	}
+	if !sess.Active(time.Now()) {
+		return sessionEntry{revoked: true}, nil
+	}
+
+	u, err := s.repo.GetByID(ctx, sess.UserID)
+	if err != nil {
+		return sessionEntry{}, err
+	}
+	if u == nil || u.Suspended() {
+		return sessionEntry{revoked: true}, nil
+	}
+
+	return sessionEntry{
+		userID:       u.ID,
+		username:     u.Username,
+		tokenVersion: u.TokenVersion,
+		expiresAt:    sess.ExpiresAt,
+		touchedAt:    sess.LastUsedAt,
+	}, nil
+}
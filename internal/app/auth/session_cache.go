@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxCachedSessions = 100_000
+
+type sessionEntry struct {
+	userID       uuid.UUID
+	username     string
+	tokenVersion int
+	expiresAt    time.Time
+	// revoked entries are kept so a concurrent load can't bring a revoked
+	// session back
+	revoked bool
+
+	cachedAt  time.Time
+	touchedAt time.Time
+}
+
+// sessionCache saves a database round trip per authenticated request.
+// Revocations are applied locally right away and from other replicas via
+// WatchSessions; ttl bounds how stale an entry can get if an event is lost.
+type sessionCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]*sessionEntry
+}
+
+func newSessionCache(ttl time.Duration) *sessionCache {
+	return &sessionCache{
+		ttl:     ttl,
+		entries: make(map[uuid.UUID]*sessionEntry),
+	}
+}
+
+func (c *sessionCache) get(id uuid.UUID, now time.Time) (sessionEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[id]
+	if !ok || now.Sub(e.cachedAt) > c.ttl {
+		return sessionEntry{}, false
+	}
+	return *e, true
+}
+
+// put stores a freshly loaded entry and returns what is cached now: a
+// revocation that raced with the load wins.
+func (c *sessionCache) put(id uuid.UUID, e sessionEntry, now time.Time) sessionEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.entries[id]; ok && old.revoked && now.Sub(old.cachedAt) <= c.ttl {
+		return *old
+	}
+
+	if len(c.entries) >= maxCachedSessions {
+		c.evictLocked(now)
+	}
+
+	e.cachedAt = now
+	c.entries[id] = &e
+	return e
+}
+
+func (c *sessionCache) revoke(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[id] = &sessionEntry{revoked: true, cachedAt: time.Now()}
+}
+
+func (c *sessionCache) forget(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, id)
+}
+
+func (c *sessionCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[uuid.UUID]*sessionEntry)
+}
+
+// shouldTouch reports whether last_used_at is due for an update and marks
+// it done, so only one request per interval writes it.
+func (c *sessionCache) shouldTouch(id uuid.UUID, now time.Time, interval time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[id]
+	if !ok || now.Sub(e.touchedAt) < interval {
+		return false
+	}
+	e.touchedAt = now
+	return true
+}
+
+func (c *sessionCache) evictLocked(now time.Time) {
+	for id, e := range c.entries {
+		if now.Sub(e.cachedAt) > c.ttl {
+			delete(c.entries, id)
+		}
+	}
+	if len(c.entries) >= maxCachedSessions {
+		c.entries = make(map[uuid.UUID]*sessionEntry)
+	}
+}
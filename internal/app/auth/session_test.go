@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/audit"
+)
+
+func TestLoginStartsSession(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	u := f.addUser(t, "alice")
+
+	token := f.login(t, u)
+	id, err := f.svc.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.UserID != u.ID || id.Username != "alice" || id.Scoped() {
+		t.Errorf("identity = %+v", id)
+	}
+
+	sessions, err := f.svc.ListSessions(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != id.SessionID {
+		t.Errorf("sessions = %+v, want the one of the token", sessions)
+	}
+	if got := f.audit.actions(); !slices.Equal(got, []string{audit.ActionLogin}) {
+		t.Errorf("audit = %q", got)
+	}
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	f := newFixture(t, Config{})
+	u := f.addUser(t, "alice")
+
+	for _, name := range []string{u.Username, "bob"} {
+		if _, err := f.svc.Login(context.Background(), name, "wrong"); !errors.Is(err, ErrInvalidData) {
+			t.Errorf("login %s = %v, want ErrInvalidData", name, err)
+		}
+	}
+	if len(f.sessions.byID) != 0 {
+		t.Errorf("%d sessions started", len(f.sessions.byID))
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	alice, bob := f.addUser(t, "alice"), f.addUser(t, "bob")
+
+	first, second := f.login(t, alice), f.login(t, alice)
+	firstID, err := f.svc.Authenticate(ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// someone else's session is not found, and stays active
+	if err := f.svc.RevokeSession(ctx, bob.ID, firstID.SessionID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("revoke by another user = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := f.svc.Authenticate(ctx, first); err != nil {
+		t.Fatalf("session revoked by another user: %v", err)
+	}
+
+	if err := f.svc.RevokeSession(ctx, alice.ID, firstID.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	// the cached session is dropped at once
+	if _, err := f.svc.Authenticate(ctx, first); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("revoked session = %v, want ErrUnauthorized", err)
+	}
+	if _, err := f.svc.Authenticate(ctx, second); err != nil {
+		t.Errorf("other session = %v", err)
+	}
+	if sessions, _ := f.svc.ListSessions(ctx, alice.ID); len(sessions) != 1 {
+		t.Errorf("%d sessions listed, want 1", len(sessions))
+	}
+
+	if err := f.svc.RevokeSession(ctx, alice.ID, firstID.SessionID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revoke twice = %v, want ErrSessionNotFound", err)
+	}
+	if err := f.svc.RevokeSession(ctx, alice.ID, uuid.New()); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revoke unknown = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{})
+	alice, bob := f.addUser(t, "alice"), f.addUser(t, "bob")
+
+	tokens := []string{f.login(t, alice), f.login(t, alice), f.login(t, alice)}
+	bobToken := f.login(t, bob)
+	keep, err := f.svc.Authenticate(ctx, tokens[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tok := range tokens[1:] {
+		if _, err := f.svc.Authenticate(ctx, tok); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := f.svc.RevokeAllSessions(ctx, alice.ID, keep.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.Authenticate(ctx, tokens[0]); err != nil {
+		t.Errorf("kept session = %v", err)
+	}
+	for _, tok := range tokens[1:] {
+		if _, err := f.svc.Authenticate(ctx, tok); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("revoked session = %v, want ErrUnauthorized", err)
+		}
+	}
+	if _, err := f.svc.Authenticate(ctx, bobToken); err != nil {
+		t.Errorf("session of another user = %v", err)
+	}
+
+	if err := f.svc.RevokeAllSessions(ctx, alice.ID, uuid.Nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.svc.Authenticate(ctx, tokens[0]); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("session after revoking all = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestAuthenticateChecksSession(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(f *fixture, userID, sessionID uuid.UUID)
+	}{
+		{"revoked", func(f *fixture, _, sessionID uuid.UUID) {
+			now := time.Now()
+			f.sessions.byID[sessionID].RevokedAt = &now
+		}},
+		{"expired", func(f *fixture, _, sessionID uuid.UUID) {
+			f.sessions.byID[sessionID].ExpiresAt = time.Now().Add(-time.Second)
+		}},
+		{"password changed", func(f *fixture, userID, _ uuid.UUID) {
+			f.users.byID[userID].TokenVersion++
+		}},
+		{"user suspended", func(f *fixture, userID, _ uuid.UUID) {
+			now := time.Now()
+			f.users.byID[userID].SuspendedAt = &now
+		}},
+		{"user deleted", func(f *fixture, userID, _ uuid.UUID) {
+			delete(f.users.byID, userID)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newFixture(t, Config{})
+			u := f.addUser(t, "alice")
+			token := f.login(t, u)
+
+			// a fresh service has nothing cached
+			var sessionID uuid.UUID
+			for id := range f.sessions.byID {
+				sessionID = id
+			}
+			tt.change(f, u.ID, sessionID)
+
+			if _, err := f.svc.Authenticate(ctx, token); !errors.Is(err, ErrUnauthorized) {
+				t.Errorf("Authenticate = %v, want ErrUnauthorized", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateBadToken(t *testing.T) {
+	f := newFixture(t, Config{})
+	for _, token := range []string{"", "garbage", "a.b.c"} {
+		if _, err := f.svc.Authenticate(context.Background(), token); !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Authenticate(%q) = %v, want ErrUnauthorized", token, err)
+		}
+	}
+}
+
+func TestAuthenticateTouchesSession(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{SessionTouchInterval: time.Hour})
+	token := f.login(t, f.addUser(t, "alice"))
+
+	for range 3 {
+		if _, err := f.svc.Authenticate(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the session was just created, its last use is recent enough
+	if f.sessions.touches != 0 {
+		t.Errorf("%d touches within the interval", f.sessions.touches)
+	}
+
+	f.svc.cfg.SessionTouchInterval = 0
+	if _, err := f.svc.Authenticate(ctx, token); err != nil {
+		t.Fatal(err)
+	}
+	if f.sessions.touches != 1 {
+		t.Errorf("%d touches after the interval, want 1", f.sessions.touches)
+	}
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/periodic"
+	"gopress/internal/app/requestinfo"
+	"gopress/internal/domain/audit"
+)
+
+var ErrTooManyAttempts = errors.New("too many attempts")
+
+// ThrottleError is returned while a login is backed off or locked out.
+type ThrottleError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottleError) Error() string {
+	return fmt.Sprintf("too many attempts, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottleError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// ThrottlePolicy: the first FreeAttempts failures are free, then every
+// failure blocks for BaseDelay doubling up to MaxDelay; LockoutThreshold
+// failures lock the key for LockoutDuration. Counters restart after Window
+// without failures.
+type ThrottlePolicy struct {
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	Window           time.Duration
+}
+
+type throttleKey struct {
+	key    string
+	policy ThrottlePolicy
+}
+
+// loginKeys are the throttle keys for a password attempt: the username (so
+// one account can't be guessed from many IPs) and the client IP (so one
+// client can't spray many accounts).
+func (s *Service) loginKeys(ctx context.Context, username string) []throttleKey {
+	keys := []throttleKey{{key: "user:" + strings.ToLower(username), policy: s.cfg.UserThrottle}}
+	if ip := requestinfo.From(ctx).IP; ip != "" {
+		keys = append(keys, throttleKey{key: "ip:" + ip, policy: s.cfg.IPThrottle})
+	}
+	return keys
+}
+
+func (s *Service) mfaKeys(ctx context.Context, userID uuid.UUID) []throttleKey {
+	keys := []throttleKey{{key: "mfa:" + userID.String(), policy: s.cfg.UserThrottle}}
+	if ip := requestinfo.From(ctx).IP; ip != "" {
+		keys = append(keys, throttleKey{key: "ip:" + ip, policy: s.cfg.IPThrottle})
+	}
+	return keys
+}
+
+// resetKeys are the throttle keys for a password reset request.
+func (s *Service) resetKeys(ctx context.Context, email string) []throttleKey {
+	keys := []throttleKey{{key: "reset:" + strings.ToLower(email), policy: s.cfg.ResetEmailThrottle}}
+	if ip := requestinfo.From(ctx).IP; ip != "" {
+		keys = append(keys, throttleKey{key: "reset-ip:" + ip, policy: s.cfg.ResetIPThrottle})
+	}
+	return keys
+}
+
+// checkThrottle fails with a *ThrottleError if any key is blocked.
+func (s *Service) checkThrottle(ctx context.Context, keys []throttleKey) error {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		names[i] = k.key
+	}
+
+	entries, err := s.throttle.Get(ctx, names)
+	if err != nil {
+		return ErrInternalError
+	}
+
+	var wait time.Duration
+	now := time.Now()
+	for _, e := range entries {
+		if e.BlockedUntil != nil && e.BlockedUntil.After(now) {
+			wait = max(wait, e.BlockedUntil.Sub(now))
+		}
+	}
+	if wait > 0 {
+		return &ThrottleError{RetryAfter: wait}
+	}
+	return nil
+}
+
+func (s *Service) recordFailure(ctx context.Context, keys []throttleKey, actorID *uuid.UUID) {
+	s.recordAudit(ctx, auditEvent(ctx, audit.ActionLoginFailed, actorID, keys[0].key))
+
+	for _, k := range keys {
+		failures, block := s.countFailure(ctx, k)
+		if block > 0 && failures == k.policy.LockoutThreshold {
+			ev := auditEvent(ctx, audit.ActionLoginLockout, actorID, k.key)
+			ev.Details = map[string]any{
+				"failures":     failures,
+				"locked_until": time.Now().Add(block),
+			}
+			s.recordAudit(ctx, ev)
+		}
+	}
+}
+
+// RunThrottleCleanup deletes throttle counters that have been quiet for
+// longer than any policy window and are not blocked, every
+// ThrottleCleanupInterval until ctx is done. Such counters would restart at
+// the next failure anyway.
+func (s *Service) RunThrottleCleanup(ctx context.Context) {
+	window := max(s.cfg.UserThrottle.Window, s.cfg.IPThrottle.Window,
+		s.cfg.ResetEmailThrottle.Window, s.cfg.ResetIPThrottle.Window)
+	periodic.Run(ctx, "login throttle cleanup", "counters", s.cfg.ThrottleCleanupInterval, func(ctx context.Context) (int64, error) {
+		return s.throttle.DeleteStale(ctx, window)
+	})
+}
+
+// countFailures counts a failure for every key, blocking them as their
+// policies say.
+func (s *Service) countFailures(ctx context.Context, keys []throttleKey) {
+	for _, k := range keys {
+		s.countFailure(ctx, k)
+	}
+}
+
+// countFailure bumps the counter of k and blocks it if its policy says so.
+// It returns the failure count and the block, 0 for none or on errors.
+func (s *Service) countFailure(ctx context.Context, k throttleKey) (int, time.Duration) {
+	failures, err := s.throttle.RecordFailure(ctx, k.key, k.policy.Window)
+	if err != nil {
+		log.Printf("login throttle: %v", err)
+		return 0, 0
+	}
+
+	block := k.policy.delay(failures)
+	if block == 0 {
+		return failures, 0
+	}
+	if err := s.throttle.Block(ctx, k.key, time.Now().Add(block)); err != nil {
+		log.Printf("login throttle: %v", err)
+	}
+	return failures, block
+}
+
+func (s *Service) resetThrottle(ctx context.Context, key string) {
+	if err := s.throttle.Reset(ctx, key); err != nil {
+		log.Printf("login throttle: %v", err)
+	}
+}
+
+func (p ThrottlePolicy) delay(failures int) time.Duration {
+	if p.LockoutThreshold > 0 && failures >= p.LockoutThreshold {
+		return p.LockoutDuration
+	}
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+
+	exp := failures - p.FreeAttempts - 1
+	d := time.Duration(float64(p.BaseDelay) * math.Pow(2, float64(exp)))
+	if d > p.MaxDelay || d <= 0 {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// checkPasswordUnknownUser burns the same hashing time as a real check so
+// unknown usernames can't be told apart by response time.
+func (s *Service) checkPasswordUnknownUser(plain string) {
+	s.dummyHashOnce.Do(func() {
+		s.dummyHash, _ = s.hasher.Hash("gopress-dummy-password")
+	})
+	_, _, _ = s.hasher.Verify(s.dummyHash, plain)
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gopress/internal/app/requestinfo"
+	"gopress/internal/domain/audit"
+)
+
+var testThrottle = ThrottlePolicy{
+	FreeAttempts:     2,
+	BaseDelay:        time.Second,
+	MaxDelay:         time.Minute,
+	LockoutThreshold: 5,
+	LockoutDuration:  15 * time.Minute,
+	Window:           time.Hour,
+}
+
+func TestThrottlePolicyDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 0},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 15 * time.Minute},
+		{50, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := testThrottle.delay(tt.failures); got != tt.want {
+			t.Errorf("delay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+
+	noLockout := testThrottle
+	noLockout.LockoutThreshold = 0
+	if got := noLockout.delay(7); got != 16*time.Second {
+		t.Errorf("delay(7) without lockout = %s, want 16s", got)
+	}
+	if got := noLockout.delay(100); got != time.Minute {
+		t.Errorf("delay(100) without lockout = %s, want MaxDelay", got)
+	}
+}
+
+// unblock ends every block as if its time had passed.
+func (f *fixture) unblock() {
+	for _, e := range f.throttle.entries {
+		e.BlockedUntil = nil
+	}
+}
+
+func retryAfter(err error) time.Duration {
+	var te *ThrottleError
+	if !errors.As(err, &te) {
+		return 0
+	}
+	return te.RetryAfter
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{UserThrottle: testThrottle, IPThrottle: testThrottle})
+	alice := f.addUser(t, "alice")
+
+	for i := 1; i <= testThrottle.LockoutThreshold; i++ {
+		f.unblock()
+		if _, err := f.svc.Login(ctx, "Alice", "wrong"); !errors.Is(err, ErrInvalidData) {
+			t.Fatalf("failure %d = %v, want ErrInvalidData", i, err)
+		}
+
+		e := f.throttle.entries["user:alice"]
+		if i <= testThrottle.FreeAttempts {
+			if e.BlockedUntil != nil {
+				t.Errorf("failure %d: blocked within the free attempts", i)
+			}
+			continue
+		}
+
+		// a block holds off the right password too, without counting it
+		_, err := f.svc.Login(ctx, "alice", testPassword)
+		wait := retryAfter(err)
+		if !errors.Is(err, ErrTooManyAttempts) || wait <= 0 || wait > testThrottle.delay(i) {
+			t.Errorf("failure %d: %v, want a wait up to %s", i, err, testThrottle.delay(i))
+		}
+		if i == testThrottle.LockoutThreshold && wait <= testThrottle.MaxDelay {
+			t.Errorf("failure %d: waits %s, want a lockout", i, wait)
+		}
+		if e.Failures != i {
+			t.Errorf("failure %d: counted %d", i, e.Failures)
+		}
+	}
+
+	var lockouts []*audit.Event
+	for _, e := range f.audit.events {
+		if e.Action == audit.ActionLoginLockout {
+			lockouts = append(lockouts, e)
+		}
+	}
+	if len(lockouts) != 1 || lockouts[0].Target != "user:alice" || *lockouts[0].ActorID != alice.ID ||
+		lockouts[0].Details["failures"] != testThrottle.LockoutThreshold {
+		t.Errorf("lockout events = %+v", lockouts)
+	}
+	if len(f.sessions.byID) != 0 {
+		t.Errorf("%d sessions started while throttled", len(f.sessions.byID))
+	}
+}
+
+func TestLoginSuccessResetsCounter(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t, Config{UserThrottle: testThrottle, IPThrottle: testThrottle})
+	alice := f.addUser(t, "alice")
+
+	for range testThrottle.FreeAttempts {
+		_, _ = f.svc.Login(ctx, "alice", "wrong")
+	}
+	f.login(t, alice)
+	if _, ok := f.throttle.entries["user:alice"]; ok {
+		t.Error("counter kept after a successful login")
+	}
+
+	// the free attempts are there again
+	for range testThrottle.FreeAttempts {
+		_, _ = f.svc.Login(ctx, "alice", "wrong")
+	}
+	f.login(t, alice)
+}
+
+func TestLoginThrottlePerIP(t *testing.T) {
+	ipPolicy := testThrottle
+	ipPolicy.FreeAttempts, ipPolicy.LockoutThreshold = 3, 4
+	f := newFixture(t, Config{UserThrottle: testThrottle, IPThrottle: ipPolicy})
+	f.addUser(t, "alice")
+
+	from := func(ip string) context.Context {
+		return requestinfo.With(context.Background(), requestinfo.Info{IP: ip})
+	}
+
+	// one client spraying different accounts, unknown ones included
+	for _, name := range []string{"bob", "carol", "dave", "erin"} {
+		f.unblock()
+		if _, err := f.svc.Login(from("192.0.2.1"), name, "wrong"); !errors.Is(err, ErrInvalidData) {
+			t.Fatalf("login %s = %v, want ErrInvalidData", name, err)
+		}
+	}
+
+	_, err := f.svc.Login(from("192.0.2.1"), "alice", testPassword)
+	if wait := retryAfter(err); wait <= ipPolicy.MaxDelay {
+		t.Errorf("login from the sprayer = %v, want a lockout", err)
+	}
+	if _, err := f.svc.Login(from("192.0.2.2"), "alice", testPassword); err != nil {
+		t.Errorf("login from another client = %v", err)
+	}
+	if got := f.throttle.entries["user:alice"]; got != nil {
+		t.Errorf("alice's counter = %+v", got)
+	}
+}
+
+func TestRequestPasswordResetThrottled(t *testing.T) {
+	ctx := context.Background()
+	policy := ThrottlePolicy{FreeAttempts: 1, BaseDelay: time.Hour, MaxDelay: time.Hour, Window: time.Hour}
+	f := newFixture(t, Config{ResetEmailThrottle: policy, ResetIPThrottle: policy, ResetTokenTTL: time.Hour})
+	alice := f.addUser(t, "alice")
+
+	for range 3 {
+		// throttled requests look the same to the caller
+		if err := f.svc.RequestPasswordReset(ctx, alice.Email); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-f.mailer.sent
+	<-f.mailer.sent
+	select {
+	case m := <-f.mailer.sent:
+		t.Errorf("mail sent while throttled: %+v", m)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if got := f.throttle.entries["reset:alice@example.com"].Failures; got != 2 {
+		t.Errorf("%d requests counted, want the 2 that got through", got)
+	}
+}
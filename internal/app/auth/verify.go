@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+	"gopress/pkg/token"
+)
+
+// ResendVerification emails a new confirmation link, at most once per
+// VerifyResendInterval.
+func (s *Service) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	u, err := s.GetMe(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.EmailVerified() {
+		return ErrVerified
+	}
+
+	last, err := s.verifies.LastSentAt(ctx, userID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if !last.IsZero() && time.Since(last) < s.cfg.VerifyResendInterval {
+		return ErrTooManyEmails
+	}
+
+	return s.sendVerification(ctx, u)
+}
+
+func (s *Service) VerifyEmail(ctx context.Context, verifyToken string) error {
+	if verifyToken == "" {
+		return ErrInvalidData
+	}
+
+	userID, err := s.verifies.Consume(ctx, token.Hash(verifyToken))
+	if err != nil {
+		return ErrInternalError
+	}
+	if userID == uuid.Nil {
+		return ErrInvalidToken
+	}
+
+	if err := s.repo.MarkEmailVerified(ctx, userID); err != nil {
+		return ErrInternalError
+	}
+	return nil
+}
+
+func (s *Service) sendVerification(ctx context.Context, u *user.User) error {
+	plain, hash, err := token.Generate()
+	if err != nil {
+		return ErrInternalError
+	}
+	if err := s.verifies.Create(ctx, u.ID, hash, time.Now().Add(s.cfg.VerifyTokenTTL)); err != nil {
+		return ErrInternalError
+	}
+
+	s.sendAsync(ctx, ports.Mail{
+		To:      u.Email,
+		Subject: "Confirm your gopress email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nplease confirm your email address:\n\n%s%s\n\n"+
+				"The link expires in %s.\n",
+			u.Username, s.cfg.VerifyURL, plain, s.cfg.VerifyTokenTTL,
+		),
+	})
+	return nil
+}
@@ -0,0 +1,267 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/audited"
+	"gopress/internal/app/periodic"
+	"gopress/internal/app/ports"
+	"gopress/internal/app/requestinfo"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/media"
+)
+
+var (
+	ErrNotFound        = errors.New("media not found")
+	ErrInvalidData     = errors.New("invalid data")
+	ErrTooLarge        = errors.New("file too large")
+	ErrUnsupportedType = errors.New("unsupported file type")
+	ErrQuotaExceeded   = errors.New("storage quota exceeded")
+)
+
+type Config struct {
+	// MaxSize is the largest file Upload accepts, in bytes.
+	MaxSize int64
+	// Quota is how many bytes one user may store, 0 for no limit.
+	Quota int64
+	// PurgeInterval is how often RunPurge deletes the files of deleted
+	// users, 0 never.
+	PurgeInterval time.Duration
+}
+
+// purgeBatch is how many files RunPurge lists at a time.
+const purgeBatch = 100
+
+type Service struct {
+	repo  ports.MediaRepo
+	blobs ports.BlobStore
+	audit ports.AuditLog
+	tx    ports.Transactor
+	cfg   Config
+}
+
+func NewService(repo ports.MediaRepo, blobs ports.BlobStore, auditLog ports.AuditLog, tx ports.Transactor, cfg Config) *Service {
+	return &Service{
+		repo:  repo,
+		blobs: blobs,
+		audit: auditLog,
+		tx:    tx,
+		cfg:   cfg,
+	}
+}
+
+func (s *Service) MaxSize() int64 { return s.cfg.MaxSize }
+
+// Upload stores the file read from r for the user. The content type is
+// sniffed from the data; filename is only kept for downloads. Errors
+// reading r are returned as they are.
+func (s *Service) Upload(ctx context.Context, userID uuid.UUID, filename string, r io.Reader) (*media.Media, error) {
+	// files are small enough to hold in memory, which lets the type and
+	// quota be checked before anything is stored
+	data, err := io.ReadAll(io.LimitReader(r, s.cfg.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.cfg.MaxSize {
+		return nil, ErrTooLarge
+	}
+	if len(data) == 0 {
+		return nil, ErrInvalidData
+	}
+	ct, ok := media.Sniff(data)
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	sum := sha256.Sum256(data)
+	m := &media.Media{
+		ID:          uuid.New(),
+		OwnerID:     userID,
+		Filename:    media.CleanFilename(filename),
+		ContentType: ct,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+	m.Key = media.Key(userID, m.ID, ct)
+	if m.Filename == "" {
+		m.Filename = m.ID.String() + media.Types[ct]
+	}
+
+	// a clear case is refused before uploading; the check that counts is
+	// made again under the lock below
+	if err := s.checkQuota(ctx, userID, m.Size); err != nil {
+		return nil, err
+	}
+
+	// the blob goes first, under its own new key, so no transaction or
+	// lock is held during the transfer
+	if err := s.blobs.Put(ctx, m.Key, bytes.NewReader(data), m.Size, ct); err != nil {
+		return nil, err
+	}
+
+	ev := s.auditEvent(ctx, audit.ActionMediaUpload, userID, m.ID)
+	err = s.withAudit(ctx, ev, func(ctx context.Context) error {
+		// Usage locks out concurrent uploads of the user until commit
+		if err := s.checkQuota(ctx, userID, m.Size); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, m); err != nil {
+			return err
+		}
+		ev.After = snapshot(m)
+		return nil
+	})
+	if err != nil {
+		s.deleteBlob(ctx, m.Key)
+		return nil, err
+	}
+	return m, nil
+}
+
+// checkQuota fails with ErrQuotaExceeded if size more bytes would take the
+// user over the quota.
+func (s *Service) checkQuota(ctx context.Context, userID uuid.UUID, size int64) error {
+	if s.cfg.Quota <= 0 {
+		return nil
+	}
+	used, err := s.repo.Usage(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if used+size > s.cfg.Quota {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*media.Media, error) {
+	m, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrNotFound
+	}
+	return m, nil
+}
+
+// Open returns the content of m; the caller closes it.
+func (s *Service) Open(ctx context.Context, m *media.Media) (io.ReadCloser, error) {
+	rc, err := s.blobs.Get(ctx, m.Key)
+	if err != nil {
+		return nil, err
+	}
+	if rc == nil {
+		log.Printf("media %s: blob %s missing", m.ID, m.Key)
+		return nil, ErrNotFound
+	}
+	return rc, nil
+}
+
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*media.Media, error) {
+	return s.repo.ListByOwner(ctx, userID)
+}
+
+// Usage returns the bytes the user stores and the quota, 0 for none.
+func (s *Service) Usage(ctx context.Context, userID uuid.UUID) (used, quota int64, err error) {
+	used, err = s.repo.Usage(ctx, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return used, s.cfg.Quota, nil
+}
+
+// Delete removes a file of the user. The blob goes after the row is
+// committed; if that fails it is left behind, never a row without blob.
+func (s *Service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	var m *media.Media
+	ev := s.auditEvent(ctx, audit.ActionMediaDelete, userID, id)
+	err := s.withAudit(ctx, ev, func(ctx context.Context) error {
+		var err error
+		if m, err = s.repo.DeleteOwned(ctx, userID, id); err != nil {
+			return err
+		}
+		if m == nil {
+			return ErrNotFound
+		}
+		ev.Before = snapshot(m)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.deleteBlob(ctx, m.Key)
+	return nil
+}
+
+// RunPurge deletes the files of deleted users every PurgeInterval until
+// ctx is done. The blob goes first: one that cannot be deleted keeps its
+// row and is tried again on the next run.
+func (s *Service) RunPurge(ctx context.Context) {
+	periodic.Run(ctx, "media purge", "files", s.cfg.PurgeInterval, s.purgeOrphaned)
+}
+
+func (s *Service) purgeOrphaned(ctx context.Context) (int64, error) {
+	var n int64
+	for {
+		keys, err := s.repo.OrphanedKeys(ctx, purgeBatch)
+		if err != nil {
+			return n, err
+		}
+		for _, key := range keys {
+			if err := s.blobs.Delete(ctx, key); err != nil {
+				return n, fmt.Errorf("delete blob %s: %w", key, err)
+			}
+			if err := s.repo.DeleteOrphaned(ctx, key); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(keys) < purgeBatch {
+			return n, nil
+		}
+	}
+}
+
+func (s *Service) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(context.WithoutCancel(ctx), key); err != nil {
+		log.Printf("delete blob %s: %v", key, err)
+	}
+}
+
+func (s *Service) auditEvent(ctx context.Context, action string, actorID, id uuid.UUID) *audit.Event {
+	info := requestinfo.From(ctx)
+	return &audit.Event{
+		Action:    action,
+		ActorID:   &actorID,
+		Target:    id.String(),
+		IP:        info.IP,
+		RequestID: info.RequestID,
+	}
+}
+
+// withAudit runs fn and records e as audited.Run does.
+func (s *Service) withAudit(ctx context.Context, e *audit.Event, fn func(ctx context.Context) error) error {
+	return audited.Run(ctx, s.tx, s.audit, e, fn)
+}
+
+// snapshot is the state of a file kept in audit events.
+func snapshot(m *media.Media) map[string]any {
+	return map[string]any{
+		"id":           m.ID,
+		"key":          m.Key,
+		"filename":     m.Filename,
+		"content_type": m.ContentType,
+		"size":         m.Size,
+		"sha256":       m.SHA256,
+	}
+}
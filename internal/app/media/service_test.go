@@ -0,0 +1,331 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/media"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type fakeRepo struct {
+	files     map[uuid.UUID]*media.Media
+	orphans   []string
+	createErr error
+}
+
+func (r *fakeRepo) Create(_ context.Context, m *media.Media) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	m.CreatedAt = time.Now()
+	r.files[m.ID] = m
+	return nil
+}
+
+func (r *fakeRepo) GetByID(_ context.Context, id uuid.UUID) (*media.Media, error) {
+	return r.files[id], nil
+}
+
+func (r *fakeRepo) ListByOwner(_ context.Context, ownerID uuid.UUID) ([]*media.Media, error) {
+	var res []*media.Media
+	for _, m := range r.files {
+		if m.OwnerID == ownerID {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeRepo) DeleteOwned(_ context.Context, ownerID, id uuid.UUID) (*media.Media, error) {
+	m := r.files[id]
+	if m == nil || m.OwnerID != ownerID {
+		return nil, nil
+	}
+	delete(r.files, id)
+	return m, nil
+}
+
+func (r *fakeRepo) Usage(_ context.Context, ownerID uuid.UUID) (int64, error) {
+	var used int64
+	for _, m := range r.files {
+		if m.OwnerID == ownerID {
+			used += m.Size
+		}
+	}
+	return used, nil
+}
+
+func (r *fakeRepo) OrphanedKeys(_ context.Context, limit int) ([]string, error) {
+	return slices.Clone(r.orphans[:min(limit, len(r.orphans))]), nil
+}
+
+func (r *fakeRepo) DeleteOrphaned(_ context.Context, key string) error {
+	for i, k := range r.orphans {
+		if k == key {
+			r.orphans = append(r.orphans[:i], r.orphans[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+type fakeBlobs struct {
+	data      map[string][]byte
+	putErr    error
+	deleteErr error
+}
+
+func (b *fakeBlobs) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	if b.putErr != nil {
+		return b.putErr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	b.data[key] = data
+	return nil
+}
+
+func (b *fakeBlobs) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := b.data[key]
+	if !ok {
+		return nil, nil
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (b *fakeBlobs) Delete(_ context.Context, key string) error {
+	if b.deleteErr != nil {
+		return b.deleteErr
+	}
+	delete(b.data, key)
+	return nil
+}
+
+type fakeAudit struct {
+	events []*audit.Event
+}
+
+func (a *fakeAudit) Record(_ context.Context, e *audit.Event) error {
+	a.events = append(a.events, e)
+	return nil
+}
+
+func (a *fakeAudit) List(context.Context, audit.Filter) ([]*audit.Event, error) {
+	return a.events, nil
+}
+
+func (a *fakeAudit) DeleteBefore(context.Context, time.Time) (int64, error) { return 0, nil }
+
+// fakeTx has no rollback; the tests look at what a failed call leaves.
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fixture struct {
+	svc   *Service
+	repo  *fakeRepo
+	blobs *fakeBlobs
+	audit *fakeAudit
+}
+
+func newFixture(cfg Config) *fixture {
+	f := &fixture{
+		repo:  &fakeRepo{files: make(map[uuid.UUID]*media.Media)},
+		blobs: &fakeBlobs{data: make(map[string][]byte)},
+		audit: &fakeAudit{},
+	}
+	f.svc = NewService(f.repo, f.blobs, f.audit, fakeTx{}, cfg)
+	return f
+}
+
+func png(size int) []byte {
+	data := make([]byte, size)
+	copy(data, pngHeader)
+	return data
+}
+
+func TestUpload(t *testing.T) {
+	const maxSize = 64
+	owner := uuid.New()
+
+	tests := []struct {
+		name     string
+		quota    int64
+		used     int64
+		filename string
+		data     []byte
+		err      error
+		wantType string
+	}{
+		{"png", 0, 0, "cat.png", png(maxSize), nil, "image/png"},
+		{"type from content", 0, 0, "cat.txt", png(32), nil, "image/png"},
+		{"gif", 0, 0, "a.gif", append([]byte("GIF89a"), make([]byte, 10)...), nil, "image/gif"},
+		{"too large", 0, 0, "cat.png", png(maxSize + 1), ErrTooLarge, ""},
+		{"empty", 0, 0, "cat.png", nil, ErrInvalidData, ""},
+		{"svg", 0, 0, "x.png", []byte(`<svg><script>alert(1)</script></svg>`), ErrUnsupportedType, ""},
+		{"html", 0, 0, "x.png", []byte("<!DOCTYPE html><html></html>"), ErrUnsupportedType, ""},
+		{"within quota", 100, 36, "cat.png", png(64), nil, "image/png"},
+		{"over quota", 100, 37, "cat.png", png(64), ErrQuotaExceeded, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(Config{MaxSize: maxSize, Quota: tt.quota})
+			if tt.used > 0 {
+				old := &media.Media{ID: uuid.New(), OwnerID: owner, Key: "old", Size: tt.used}
+				f.repo.files[old.ID] = old
+			}
+			before := len(f.repo.files)
+
+			m, err := f.svc.Upload(context.Background(), owner, tt.filename, bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Upload err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				if len(f.repo.files) != before || len(f.blobs.data) != 0 || len(f.audit.events) != 0 {
+					t.Errorf("failed upload left rows %d, blobs %d, events %d", len(f.repo.files)-before, len(f.blobs.data), len(f.audit.events))
+				}
+				return
+			}
+
+			if m.ContentType != tt.wantType || m.OwnerID != owner || m.Size != int64(len(tt.data)) {
+				t.Errorf("media = %+v", m)
+			}
+			if !strings.HasPrefix(m.Key, "media/"+owner.String()+"/") || !strings.HasSuffix(m.Key, media.Types[tt.wantType]) {
+				t.Errorf("key = %q", m.Key)
+			}
+			if !bytes.Equal(f.blobs.data[m.Key], tt.data) {
+				t.Error("stored blob differs from the upload")
+			}
+			if f.repo.files[m.ID] != m {
+				t.Error("row not created")
+			}
+			if len(f.audit.events) != 1 || f.audit.events[0].Action != audit.ActionMediaUpload {
+				t.Errorf("audit events = %v", f.audit.events)
+			}
+		})
+	}
+}
+
+func TestUploadFilename(t *testing.T) {
+	f := newFixture(Config{MaxSize: 64})
+	for filename, want := range map[string]string{
+		"cat.png":           "cat.png",
+		`C:\photos\cat.png`: "cat.png",
+		"../../etc/cat.png": "cat.png",
+		"a\x00b.png":        "ab.png",
+		"":                  "",
+	} {
+		m, err := f.svc.Upload(context.Background(), uuid.New(), filename, bytes.NewReader(png(16)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want == "" {
+			want = m.ID.String() + ".png"
+		}
+		if m.Filename != want {
+			t.Errorf("Upload(%q) filename = %q, want %q", filename, m.Filename, want)
+		}
+	}
+}
+
+func TestUploadDeletesBlobWhenCreateFails(t *testing.T) {
+	f := newFixture(Config{MaxSize: 64})
+	f.repo.createErr = errors.New("db down")
+
+	_, err := f.svc.Upload(context.Background(), uuid.New(), "cat.png", bytes.NewReader(png(16)))
+	if err == nil {
+		t.Fatal("Upload succeeded")
+	}
+	if len(f.blobs.data) != 0 {
+		t.Errorf("blob left behind: %v", f.blobs.data)
+	}
+}
+
+func TestUploadStoreFails(t *testing.T) {
+	f := newFixture(Config{MaxSize: 64})
+	f.blobs.putErr = errors.New("s3 down")
+
+	if _, err := f.svc.Upload(context.Background(), uuid.New(), "cat.png", bytes.NewReader(png(16))); err == nil {
+		t.Fatal("Upload succeeded")
+	}
+	if len(f.repo.files) != 0 {
+		t.Error("row created without a blob")
+	}
+}
+
+func TestUploadReadError(t *testing.T) {
+	f := newFixture(Config{MaxSize: 64})
+	readErr := errors.New("connection reset")
+
+	_, err := f.svc.Upload(context.Background(), uuid.New(), "cat.png", io.MultiReader(bytes.NewReader(pngHeader), errReader{readErr}))
+	if !errors.Is(err, readErr) {
+		t.Errorf("Upload err = %v, want the read error", err)
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestDelete(t *testing.T) {
+	f := newFixture(Config{MaxSize: 64})
+	owner := uuid.New()
+	m, err := f.svc.Upload(context.Background(), owner, "cat.png", bytes.NewReader(png(16)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.svc.Delete(context.Background(), uuid.New(), m.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete by another user = %v, want ErrNotFound", err)
+	}
+	if err := f.svc.Delete(context.Background(), owner, m.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.repo.files) != 0 || len(f.blobs.data) != 0 {
+		t.Errorf("left rows %d, blobs %d", len(f.repo.files), len(f.blobs.data))
+	}
+}
+
+func TestPurgeOrphaned(t *testing.T) {
+	f := newFixture(Config{MaxSize: 64})
+	for i := range purgeBatch + 5 {
+		key := "media/gone/" + strings.Repeat("x", i+1)
+		f.repo.orphans = append(f.repo.orphans, key)
+		f.blobs.data[key] = []byte("x")
+	}
+
+	n, err := f.svc.purgeOrphaned(context.Background())
+	if err != nil || n != purgeBatch+5 {
+		t.Fatalf("purgeOrphaned = %d, %v", n, err)
+	}
+	if len(f.repo.orphans) != 0 || len(f.blobs.data) != 0 {
+		t.Errorf("left rows %d, blobs %d", len(f.repo.orphans), len(f.blobs.data))
+	}
+}
+
+func TestPurgeOrphanedKeepsRowWhenBlobStays(t *testing.T) {
+	f := newFixture(Config{MaxSize: 64})
+	f.repo.orphans = []string{"media/gone/a"}
+	f.blobs.deleteErr = errors.New("s3 down")
+
+	if _, err := f.svc.purgeOrphaned(context.Background()); err == nil {
+		t.Fatal("purgeOrphaned succeeded")
+	}
+	if len(f.repo.orphans) != 1 {
+		t.Error("row deleted although its blob is still stored")
+	}
+}
@@ -0,0 +1,37 @@
+// Package periodic runs the cleanup jobs of the services in the
+// background.
+package periodic
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Run calls job at once and then every interval until ctx is done; an
+// interval of 0 or less returns at once. job returns how many rows it
+// removed: failures and removals are logged under name, with unit naming
+// the rows ("articles", "events").
+func Run(ctx context.Context, name, unit string, interval time.Duration, job func(ctx context.Context) (int64, error)) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := job(ctx)
+		if err != nil {
+			log.Printf("%s: %v", name, err)
+		} else if n > 0 {
+			log.Printf("%s: deleted %d %s", name, n, unit)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
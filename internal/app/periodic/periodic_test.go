@@ -0,0 +1,42 @@
+package periodic
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, "test", "rows", time.Millisecond, func(context.Context) (int64, error) {
+			if calls.Add(1) == 3 {
+				cancel()
+			}
+			return 1, errors.New("failed")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	if n := calls.Load(); n != 3 {
+		t.Errorf("job ran %d times, want 3", n)
+	}
+}
+
+func TestRunDisabled(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		Run(context.Background(), "test", "rows", interval, func(context.Context) (int64, error) {
+			t.Errorf("job ran with interval %v", interval)
+			return 0, nil
+		})
+	}
+}
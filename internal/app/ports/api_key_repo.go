@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+	"time"
+)
+
+type APIKeyRepo interface {
+	Create(ctx context.Context, k *user.APIKey) error
+	// GetByHash returns nil if there is no such key.
+	GetByHash(ctx context.Context, keyHash string) (*user.APIKey, error)
+	// ListByUser returns the unrevoked keys of the user, newest first.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]user.APIKey, error)
+	// Revoke returns false if the user has no such unrevoked key.
+	Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	// Touch sets last_used_at unless it was set less than minInterval ago.
+	Touch(ctx context.Context, id uuid.UUID, at time.Time, minInterval time.Duration) error
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"gopress/internal/domain/article"
+	"time"
+)
+
+// ArticleEventRepo reads the event log. Event IDs grow in commit order.
+type ArticleEventRepo interface {
+	LastID(ctx context.Context) (int64, error)
+	ListSince(ctx context.Context, afterID int64, limit int) ([]*article.Event, error)
+	// DeleteBefore removes events older than t and returns their number.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+// ArticleEventBus delivers live article events. The returned channel is
+// closed when events may have been missed (slow consumer, lost database
+// connection); subscribers should resubscribe and catch up via ArticleEventRepo.
+type ArticleEventBus interface {
+	Subscribe(ctx context.Context) (<-chan *article.Event, error)
+}
@@ -4,13 +4,42 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"gopress/internal/domain/article"
+	"time"
 )
 
+// ArticleRepo reads and changes articles outside the trash unless noted.
 type ArticleRepo interface {
 	Create(ctx context.Context, a *article.Article) error
 	GetByID(ctx context.Context, id int64) (*article.Article, error)
 	ListByAuthor(ctx context.Context, authorID uuid.UUID) ([]*article.Article, error)
 	List(ctx context.Context, limit int, offset int) ([]*article.Article, error)
-	UpdateOwned(ctx context.Context, id int64, authorID uuid.UUID, title, content string) (bool, error)
-	DeleteOwned(ctx context.Context, id int64, authorID uuid.UUID) (bool, error)
+	// ListSummaries is List without reading Content, which is left empty.
+	ListSummaries(ctx context.Context, limit int, offset int) ([]*article.Article, error)
+	// UpdateOwned stores the editable fields of a if a.AuthorID owns it.
+	// UpdateOwned and DeleteOwned only change the given version of the
+	// article, any version if it is 0. UpdateOwned bumps the version.
+	UpdateOwned(ctx context.Context, a *article.Article, version int64) (bool, error)
+	// DeleteOwned moves the article to the trash.
+	DeleteOwned(ctx context.Context, id int64, authorID uuid.UUID, version int64) (bool, error)
+
+	// ResolveSlug finds the article a current or former slug names and
+	// returns its ID and current slug, 0 if there is none.
+	ResolveSlug(ctx context.Context, slug string) (int64, string, error)
+	// TakenSlugs returns the current and former slugs, other than those of
+	// article exceptID, that are base or base-N. Within a transaction it
+	// holds other callers for the same base until commit.
+	TakenSlugs(ctx context.Context, base string, exceptID int64) ([]string, error)
+	// AddSlugRedirect makes the former slug "from" lead to the article, and
+	// drops a redirect from its new slug "to".
+	AddSlugRedirect(ctx context.Context, articleID int64, from, to string) error
+
+	// GetTrashed returns nil if the article is not in the trash.
+	GetTrashed(ctx context.Context, id int64) (*article.Article, error)
+	// ListTrash returns the author's trashed articles, most recently deleted first.
+	ListTrash(ctx context.Context, authorID uuid.UUID) ([]*article.Article, error)
+	RestoreOwned(ctx context.Context, id int64, authorID uuid.UUID) (bool, error)
+	// PurgeOwned permanently deletes a trashed article.
+	PurgeOwned(ctx context.Context, id int64, authorID uuid.UUID) (bool, error)
+	// PurgeTrash permanently deletes articles trashed before t and returns their number.
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
 }
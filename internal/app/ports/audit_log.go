@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"gopress/internal/domain/audit"
+	"time"
+)
+
+// AuditLog is append-only. Record joins the transaction of ctx, if any, so
+// an event is stored together with the change it describes.
+type AuditLog interface {
+	Record(ctx context.Context, e *audit.Event) error
+	List(ctx context.Context, f audit.Filter) ([]*audit.Event, error)
+	// DeleteBefore purges events older than t and returns their number.
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
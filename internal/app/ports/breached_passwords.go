@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// BreachedPasswords tells whether a password appears in a known breach.
+type BreachedPasswords interface {
+	IsBreached(ctx context.Context, plain string) (bool, error)
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "gopress/internal/domain/article"
+
+// ContentRenderer turns article content into sanitized HTML.
+type ContentRenderer interface {
+	Render(format article.Format, content string) (string, error)
+	// Text is what a reader sees of the content, without markup, on one line.
+	Text(format article.Format, content string) (string, error)
+}
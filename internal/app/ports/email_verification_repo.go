@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type EmailVerificationRepo interface {
+	// Create stores a new token hash, dropping any earlier tokens of the user.
+	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// LastSentAt returns when the current token was created, zero if none.
+	LastSentAt(ctx context.Context, userID uuid.UUID) (time.Time, error)
+	// Consume deletes an unexpired token and returns its user, uuid.Nil if
+	// there is no such token.
+	Consume(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"gopress/internal/domain/user"
+	"time"
+)
+
+type LoginThrottleRepo interface {
+	// Get returns the entries that exist for keys.
+	Get(ctx context.Context, keys []string) ([]*user.LoginThrottle, error)
+	// RecordFailure bumps the failure counter, restarting it if the last
+	// failure is older than window, and returns the new count.
+	RecordFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	Block(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	// DeleteStale removes entries without failures for window that are
+	// not blocked, returning their number.
+	DeleteStale(ctx context.Context, window time.Duration) (int64, error)
+}
@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/media"
+)
+
+type MediaRepo interface {
+	Create(ctx context.Context, m *media.Media) error
+	// GetByID returns nil if there is no such media.
+	GetByID(ctx context.Context, id uuid.UUID) (*media.Media, error)
+	// ListByOwner returns the files of the user, newest first.
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*media.Media, error)
+	// DeleteOwned returns the deleted row, nil if the user has no such file.
+	DeleteOwned(ctx context.Context, ownerID, id uuid.UUID) (*media.Media, error)
+	// Usage is the total size of the user's files. Within a transaction it
+	// holds other Usage calls for the same user until commit.
+	Usage(ctx context.Context, ownerID uuid.UUID) (int64, error)
+	// OrphanedKeys returns the blob keys of up to limit files whose owner
+	// was deleted, oldest first.
+	OrphanedKeys(ctx context.Context, limit int) ([]string, error)
+	// DeleteOrphaned removes the row of an orphaned file by its key.
+	DeleteOrphaned(ctx context.Context, key string) error
+}
+
+// BlobStore keeps the content of uploaded files under keys of the form
+// "dir/dir/name".
+type BlobStore interface {
+	// Put stores size bytes read from r, replacing key if it exists.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get returns nil if there is no such key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete succeeds if there is no such key.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+)
+
+type MFARepo interface {
+	GetTOTP(ctx context.Context, userID uuid.UUID) (*user.TOTP, error)
+	// SaveTOTPSecret starts (or restarts) a not yet enabled enrollment.
+	SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	// EnableTOTP enables TOTP and replaces the recovery codes.
+	EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, userID uuid.UUID) error
+	// UseTOTPStep records a used time step, false if it (or a later one) was used already.
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// UseRecoveryCode burns an unused recovery code, false if there is none.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+}
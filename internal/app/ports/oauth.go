@@ -0,0 +1,47 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+)
+
+type OAuthClientRepo interface {
+	Create(ctx context.Context, c *user.OAuthClient) error
+	// Get returns nil if there is no such client.
+	Get(ctx context.Context, id uuid.UUID) (*user.OAuthClient, error)
+	// ListByOwner returns the clients registered by the user, newest first.
+	ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]user.OAuthClient, error)
+	// Delete returns false if the user owns no such client.
+	Delete(ctx context.Context, ownerID, id uuid.UUID) (bool, error)
+}
+
+type OAuthCodeRepo interface {
+	Create(ctx context.Context, codeHash string, c user.OAuthCode) error
+	// Consume deletes and returns the code, nil if it doesn't exist. It
+	// returns expired codes as well.
+	Consume(ctx context.Context, codeHash string) (*user.OAuthCode, error)
+}
+
+type OAuthGrantRepo interface {
+	Create(ctx context.Context, g *user.OAuthGrant, refreshHash string) error
+	// Get returns nil if there is no such grant.
+	Get(ctx context.Context, id uuid.UUID) (*user.OAuthGrant, error)
+	// GetByRefreshHash returns nil unless refreshHash is the grant's current
+	// refresh token.
+	GetByRefreshHash(ctx context.Context, refreshHash string) (*user.OAuthGrant, error)
+	// Rotate replaces the refresh token if it is still oldHash and the grant
+	// is active. It returns false if the token was used concurrently.
+	Rotate(ctx context.Context, id uuid.UUID, oldHash, newHash string, expiresAt, now time.Time) (bool, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	// RevokeClient revokes all grants of the user for the client and
+	// returns false if there were none.
+	RevokeClient(ctx context.Context, userID, clientID uuid.UUID) (bool, error)
+	// ListApps groups the user's active grants by client.
+	ListApps(ctx context.Context, userID uuid.UUID) ([]user.AuthorizedApp, error)
+	// Covers reports whether the user has an active grant for the client
+	// that includes all scopes.
+	Covers(ctx context.Context, userID, clientID uuid.UUID, scopes []string) (bool, error)
+}
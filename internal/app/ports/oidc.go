@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+)
+
+// OIDCClaims are the verified ID token claims gopress uses.
+type OIDCClaims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// OIDCProvider runs the authorization-code flow with PKCE against one
+// OpenID Connect provider.
+type OIDCProvider interface {
+	Name() string
+	// AuthCodeURL is where the user is sent to sign in.
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	// Exchange redeems the code and verifies the ID token, including nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCClaims, error)
+}
+
+type UserIdentityRepo interface {
+	// Get returns nil if the identity is not linked.
+	Get(ctx context.Context, provider, subject string) (*user.ExternalIdentity, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]user.ExternalIdentity, error)
+	Create(ctx context.Context, id *user.ExternalIdentity) error
+	Touch(ctx context.Context, id uuid.UUID, email string) error
+}
+
+type OIDCStateRepo interface {
+	Create(ctx context.Context, stateHash string, s user.OIDCLoginState) error
+	// Consume deletes and returns a state, nil if there is none.
+	Consume(ctx context.Context, stateHash string) (*user.OIDCLoginState, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasswordResetRepo interface {
+	// Create stores a new token hash, dropping any earlier tokens of the user.
+	Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// Peek returns the user of an unused, unexpired token without using it.
+	// Returns uuid.Nil if there is no such token.
+	Peek(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	// Consume marks an unused, unexpired token as used and returns its user.
+	// Returns uuid.Nil if there is no such token.
+	Consume(ctx context.Context, tokenHash string) (uuid.UUID, error)
+}
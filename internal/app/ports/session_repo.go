@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"gopress/internal/domain/user"
+	"time"
+)
+
+type SessionRepo interface {
+	Create(ctx context.Context, s *user.Session) error
+	// Get returns nil if there is no such session.
+	Get(ctx context.Context, id uuid.UUID) (*user.Session, error)
+	// ListActive returns unrevoked, unexpired sessions, most recently used first.
+	ListActive(ctx context.Context, userID uuid.UUID) ([]user.Session, error)
+	// Touch records a use of the session.
+	Touch(ctx context.Context, id uuid.UUID, ip string, at time.Time) error
+	// Revoke revokes one session of the user, false if there is no such active session.
+	Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	// RevokeAll revokes every session of the user except keep (uuid.Nil keeps none)
+	// and returns the revoked IDs.
+	RevokeAll(ctx context.Context, userID, keep uuid.UUID) ([]uuid.UUID, error)
+}
+
+// SessionEventBus delivers IDs of sessions revoked on any replica. The
+// channel is closed when events may have been missed.
+type SessionEventBus interface {
+	SubscribeRevoked(ctx context.Context) (<-chan uuid.UUID, error)
+}
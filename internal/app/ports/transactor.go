@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// Transactor runs fn in a database transaction, committed when fn returns
+// nil. Repositories called with the ctx passed to fn take part in it;
+// nested calls join the outer transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"gopress/internal/domain/user"
 )
@@ -9,6 +11,25 @@ import (
 type UserRepo interface {
 	Create(ctx context.Context, u *user.User) error
 	GetByUsername(ctx context.Context, username string) (*user.User, error)
+	GetByEmail(ctx context.Context, email string) (*user.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*user.User, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, p user.Profile) (*user.User, error)
+	// UpdatePassword stores the new hash and bumps token_version, returning the new version.
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) (int, error)
+	// UpdatePasswordHash replaces the hash of the same password (rehash), sessions stay valid.
+	UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	// List returns users matching the filter, newest first.
+	List(ctx context.Context, f user.ListFilter) ([]*user.User, error)
+	// SetRole and SetSuspended return nil if there is no such user. A nil
+	// suspendedAt lifts the suspension.
+	SetRole(ctx context.Context, id uuid.UUID, role string) (*user.User, error)
+	SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time, reason string) (*user.User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+// UserEventBus delivers IDs of users changed or deleted on any replica.
+// The channel is closed when events may have been missed.
+type UserEventBus interface {
+	SubscribeChanged(ctx context.Context) (<-chan uuid.UUID, error)
+}
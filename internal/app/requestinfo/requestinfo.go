@@ -0,0 +1,23 @@
+// Package requestinfo carries transport-level facts about the current
+// request (client IP, user agent, request ID) into the app layer.
+package requestinfo
+
+import "context"
+
+type ctxKey struct{}
+
+type Info struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+func With(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, ctxKey{}, info)
+}
+
+// From returns the request info, zero if the transport didn't set any.
+func From(ctx context.Context) Info {
+	info, _ := ctx.Value(ctxKey{}).(Info)
+	return info
+}
@@ -1,17 +1,126 @@
 package article
 
 import (
-	"github.com/google/uuid"
+	"encoding/json"
+	"errors"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format is how Content is written.
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatPlain    Format = "plain"
 )
 
+func ValidFormat(f Format) bool {
+	return f == FormatMarkdown || f == FormatHTML || f == FormatPlain
+}
+
 type Article struct {
-	ID        int64     `db:"id"`
-	Title     string    `db:"title"`
-	Content   string    `db:"content"`
-	AuthorID  uuid.UUID `db:"author_id"`
+	ID    int64  `db:"id"`
+	Title string `db:"title"`
+	// Slug names the article in URLs, it follows title changes
+	Slug          string    `db:"slug"`
+	Content       string    `db:"content"`
+	ContentFormat Format    `db:"content_format"`
+	AuthorID      uuid.UUID `db:"author_id"`
+	// Excerpt, WordCount and ReadingMinutes are derived from Content when it
+	// is written, see Summarize
+	Excerpt        string `db:"excerpt"`
+	WordCount      int    `db:"word_count"`
+	ReadingMinutes int    `db:"reading_minutes"`
+	// Version grows with every update, for optimistic locking
+	Version   int64     `db:"version"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
+	// DeletedAt is set while the article is in the trash
+	DeletedAt *time.Time `db:"deleted_at"`
 
 	AuthorUsername string `db:"-"`
+	// ContentHTML is Content rendered and sanitized, set for responses.
+	ContentHTML string `db:"-"`
+}
+
+// Patch changes the editable fields of an article, nil fields are kept.
+// New editable fields go here, in Apply and in patchFields; transports
+// learn them from there.
+type Patch struct {
+	Title         *string
+	Content       *string
+	ContentFormat *Format
+}
+
+func (p Patch) Empty() bool {
+	return p == Patch{}
+}
+
+// Apply sets the patched fields on a.
+func (p Patch) Apply(a *Article) {
+	if p.Title != nil {
+		a.Title = *p.Title
+	}
+	if p.Content != nil {
+		a.Content = *p.Content
+	}
+	if p.ContentFormat != nil {
+		a.ContentFormat = *p.ContentFormat
+	}
+}
+
+var (
+	ErrUnknownField   = errors.New("unknown field")
+	ErrDuplicateField = errors.New("duplicate field")
+	ErrInvalidValue   = errors.New("invalid value")
+	// ErrRequiredField is a null for a field every article has.
+	ErrRequiredField = errors.New("field cannot be removed")
+)
+
+// patchFields sets the editable fields of a Patch from JSON values, by
+// their snake_case name in JSON bodies, protos and update masks.
+var patchFields = map[string]func(p *Patch, raw json.RawMessage) error{
+	"title":          setField(func(p *Patch) **string { return &p.Title }),
+	"content":        setField(func(p *Patch) **string { return &p.Content }),
+	"content_format": setField(func(p *Patch) **Format { return &p.ContentFormat }),
+}
+
+// PatchFieldNames returns the names Set accepts.
+func PatchFieldNames() []string {
+	names := make([]string, 0, len(patchFields))
+	for name := range patchFields {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Set sets the field called name from its JSON value. A field can be set
+// once; null is refused, as every editable field is required.
+func (p *Patch) Set(name string, raw json.RawMessage) error {
+	set, ok := patchFields[name]
+	if !ok {
+		return ErrUnknownField
+	}
+	return set(p, raw)
+}
+
+func setField[T any](field func(p *Patch) **T) func(p *Patch, raw json.RawMessage) error {
+	return func(p *Patch, raw json.RawMessage) error {
+		dst := field(p)
+		if *dst != nil {
+			return ErrDuplicateField
+		}
+		var v *T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return ErrInvalidValue
+		}
+		if v == nil {
+			return ErrRequiredField
+		}
+		*dst = v
+		return nil
+	}
 }
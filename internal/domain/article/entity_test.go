@@ -0,0 +1,78 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPatchSet(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		raw   string
+		err   error
+		want  Article
+	}{
+		{"title", "title", `"New"`, nil, Article{Title: "New"}},
+		{"empty title", "title", `""`, nil, Article{}},
+		{"content", "content", `"Text"`, nil, Article{Content: "Text"}},
+		{"format", "content_format", `"html"`, nil, Article{ContentFormat: FormatHTML}},
+		{"null", "title", `null`, ErrRequiredField, Article{}},
+		{"wrong type", "content", `42`, ErrInvalidValue, Article{}},
+		{"read-only", "version", `3`, ErrUnknownField, Article{}},
+		{"unknown", "tags", `[]`, ErrUnknownField, Article{}},
+		{"not snake case", "contentFormat", `"html"`, ErrUnknownField, Article{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Patch
+			err := p.Set(tt.field, json.RawMessage(tt.raw))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Set = %v, want %v", err, tt.err)
+			}
+			var a Article
+			p.Apply(&a)
+			if a != tt.want {
+				t.Errorf("applied = %+v, want %+v", a, tt.want)
+			}
+			if err == nil && p.Empty() {
+				t.Error("patch is empty after Set")
+			}
+		})
+	}
+}
+
+func TestPatchSetTwice(t *testing.T) {
+	var p Patch
+	if err := p.Set("title", json.RawMessage(`"a"`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Set("title", json.RawMessage(`"b"`)); !errors.Is(err, ErrDuplicateField) {
+		t.Errorf("second Set = %v, want ErrDuplicateField", err)
+	}
+	if *p.Title != "a" {
+		t.Errorf("Title = %q, want the first value", *p.Title)
+	}
+}
+
+// Every field of Patch must be settable by name, or transports miss it.
+func TestPatchFieldNamesCoverPatch(t *testing.T) {
+	names := PatchFieldNames()
+	slices.Sort(names)
+	if want := []string{"content", "content_format", "title"}; !slices.Equal(names, want) {
+		t.Fatalf("PatchFieldNames = %q, want %q", names, want)
+	}
+
+	var p Patch
+	for _, name := range names {
+		raw := json.RawMessage(`"markdown"`)
+		if err := p.Set(name, raw); err != nil {
+			t.Fatalf("Set(%q) = %v", name, err)
+		}
+	}
+	if p.Title == nil || p.Content == nil || p.ContentFormat == nil {
+		t.Errorf("fields left unset: %+v", p)
+	}
+}
@@ -0,0 +1,22 @@
+package article
+
+import "time"
+
+type EventType string
+
+const (
+	EventCreated EventType = "created"
+	EventUpdated EventType = "updated"
+	EventDeleted EventType = "deleted"
+)
+
+// Event is a change notification for a single article. Article is nil for
+// deleted articles.
+type Event struct {
+	ID         int64     `db:"id"`
+	Type       EventType `db:"type"`
+	ArticleID  int64     `db:"article_id"`
+	OccurredAt time.Time `db:"created_at"`
+
+	Article *Article `db:"-"`
+}
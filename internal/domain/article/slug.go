@@ -0,0 +1,85 @@
+package article
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLen is the longest slug Slugify returns, before a "-N" suffix.
+const MaxSlugLen = 80
+
+// cyrillic transliterates Russian, Ukrainian and Belarusian letters, the
+// soft and hard signs are dropped.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+}
+
+// Slugify makes the URL part of a title: lowercase ASCII letters and
+// digits separated by single dashes. Cyrillic is transliterated and
+// accents are dropped ("Café" -> "cafe"); a title with nothing left gives
+// "article".
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	emit := func(s string) {
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(s)
+	}
+
+	for _, r := range strings.ToLower(title) {
+		// before decomposing, which would turn "й" into "и" and a breve
+		if t, ok := cyrillic[r]; ok {
+			if t != "" {
+				emit(t)
+			}
+			continue
+		}
+		for _, d := range norm.NFD.String(string(r)) {
+			switch {
+			case d >= 'a' && d <= 'z' || d >= '0' && d <= '9':
+				emit(string(d))
+			case unicode.Is(unicode.Mn, d):
+			default:
+				dash = b.Len() > 0
+			}
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLen {
+		slug = slug[:MaxSlugLen]
+		if i := strings.LastIndexByte(slug, '-'); i > MaxSlugLen/2 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	if slug == "" {
+		return "article"
+	}
+	return slug
+}
+
+// SlugOf reports whether slug was made from base: base itself or base
+// with a "-N" suffix added to keep it unique.
+func SlugOf(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	n, ok := strings.CutPrefix(slug, base+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(n)
+	return err == nil
+}
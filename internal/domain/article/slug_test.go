@@ -0,0 +1,81 @@
+package article
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  Go 1.25 released  ", "go-1-25-released"},
+		{"Café au lait", "cafe-au-lait"},
+		{"Zürich naïve résumé", "zurich-naive-resume"},
+		{"Привет, мир", "privet-mir"},
+		{"Мой объект", "moy-obekt"},
+		{"Щука и ёж", "shchuka-i-ezh"},
+		{"Київ і Їжак", "kiyiv-i-yizhak"},
+		{"Ґанок", "ganok"},
+		{"a -- b", "a-b"},
+		{"---", "article"},
+		{"", "article"},
+		{"日本語", "article"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if got := Slugify(tt.title); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugifyMaxLen(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{"cut at a dash", strings.Repeat("word ", 30), strings.TrimSuffix(strings.Repeat("word-", 16), "-")},
+		{"one long word", strings.Repeat("x", 100), strings.Repeat("x", MaxSlugLen)},
+		{"dash too early", "a " + strings.Repeat("y", 100), "a-" + strings.Repeat("y", MaxSlugLen-2)},
+		{"exact fit", strings.Repeat("z", MaxSlugLen), strings.Repeat("z", MaxSlugLen)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Slugify(tt.title)
+			if got != tt.want {
+				t.Errorf("Slugify = %q, want %q", got, tt.want)
+			}
+			if len(got) > MaxSlugLen || strings.HasSuffix(got, "-") {
+				t.Errorf("Slugify = %q: too long or ends in a dash", got)
+			}
+		})
+	}
+}
+
+func TestSlugOf(t *testing.T) {
+	tests := []struct {
+		slug string
+		base string
+		want bool
+	}{
+		{"hello", "hello", true},
+		{"hello-2", "hello", true},
+		{"hello-10", "hello", true},
+		{"hello-world", "hello", false},
+		{"hello-", "hello", false},
+		{"hello2", "hello", false},
+		{"hell", "hello", false},
+		{"go-1-25", "go-1", true},
+		{"go-1-25-3", "go-1-25", true},
+	}
+	for _, tt := range tests {
+		if got := SlugOf(tt.slug, tt.base); got != tt.want {
+			t.Errorf("SlugOf(%q, %q) = %v, want %v", tt.slug, tt.base, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package article
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ExcerptLen is the longest excerpt in characters, without the ellipsis.
+	ExcerptLen = 280
+	// WordsPerMinute is the reading speed reading times assume.
+	WordsPerMinute = 200
+)
+
+// Summarize derives the excerpt, word count and reading time in whole
+// minutes (at least 1 for any text) from the text of an article. The
+// excerpt ends at a word boundary.
+func Summarize(text string) (excerpt string, words, minutes int) {
+	fields := strings.Fields(text)
+	words = len(fields)
+	if words == 0 {
+		return "", 0, 0
+	}
+	minutes = (words + WordsPerMinute - 1) / WordsPerMinute
+
+	var b strings.Builder
+	n := 0
+	for i, w := range fields {
+		l := utf8.RuneCountInString(w)
+		if i > 0 {
+			l++
+		}
+		if n+l > ExcerptLen {
+			if i == 0 {
+				// one very long word
+				r := []rune(w)
+				b.WriteString(string(r[:ExcerptLen]))
+			}
+			b.WriteString("…")
+			return b.String(), words, minutes
+		}
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w)
+		n += l
+	}
+	return b.String(), words, minutes
+}
@@ -0,0 +1,39 @@
+package article
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSummarize(t *testing.T) {
+	long := strings.Repeat("word ", 100) // 499 characters
+	tests := []struct {
+		name        string
+		text        string
+		wantExcerpt string
+		wantWords   int
+		wantMinutes int
+	}{
+		{"empty", "", "", 0, 0},
+		{"blank", " \n\t ", "", 0, 0},
+		{"short", "Hello,\n\n  world!", "Hello, world!", 2, 1},
+		{"one minute", strings.Repeat("a ", WordsPerMinute), strings.TrimSpace(strings.Repeat("a ", 140)) + "…", WordsPerMinute, 1},
+		{"rounds up", strings.Repeat("a ", WordsPerMinute+1), strings.TrimSpace(strings.Repeat("a ", 140)) + "…", WordsPerMinute + 1, 2},
+		{"word boundary", long, strings.TrimSpace(strings.Repeat("word ", 56)) + "…", 100, 1},
+		{"exact fit", strings.Repeat("x", ExcerptLen), strings.Repeat("x", ExcerptLen), 1, 1},
+		{"one long word", strings.Repeat("ж", ExcerptLen+20), strings.Repeat("ж", ExcerptLen) + "…", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excerpt, words, minutes := Summarize(tt.text)
+			if excerpt != tt.wantExcerpt || words != tt.wantWords || minutes != tt.wantMinutes {
+				t.Errorf("Summarize = %q, %d, %d, want %q, %d, %d",
+					excerpt, words, minutes, tt.wantExcerpt, tt.wantWords, tt.wantMinutes)
+			}
+			if n := utf8.RuneCountInString(strings.TrimSuffix(excerpt, "…")); n > ExcerptLen {
+				t.Errorf("excerpt is %d characters", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	ActionLogin        = "auth.login"
+	ActionLoginFailed  = "auth.login.failed"
+	ActionLoginLockout = "auth.login.lockout"
+	ActionRegister     = "auth.register"
+
+	// admin actions, Target is the affected user's ID. ActionUserDelete is
+	// also recorded when users delete their own account, with themselves
+	// as the actor
+	ActionUserSuspend       = "admin.user.suspend"
+	ActionUserUnsuspend     = "admin.user.unsuspend"
+	ActionUserPasswordReset = "admin.user.password_reset"
+	ActionUserRoleChange    = "admin.user.role_change"
+	ActionUserDelete        = "admin.user.delete"
+
+	// article actions, Target is the article ID
+	ActionArticleCreate  = "article.create"
+	ActionArticleUpdate  = "article.update"
+	ActionArticleDelete  = "article.delete"
+	ActionArticleRestore = "article.restore"
+	ActionArticlePurge   = "article.purge"
+	// the trash retention job, no actor or target
+	ActionTrashExpire = "article.trash_expire"
+
+	// media actions, Target is the media ID
+	ActionMediaUpload = "media.upload"
+	ActionMediaDelete = "media.delete"
+)
+
+type Event struct {
+	ID        int64          `db:"id"`
+	Action    string         `db:"action"`
+	ActorID   *uuid.UUID     `db:"actor_id"`
+	Target    string         `db:"target"`
+	IP        string         `db:"ip"`
+	RequestID string         `db:"request_id"`
+	Details   map[string]any `db:"details"`
+	// Before and After snapshot the target around a change, nil where it
+	// didn't exist
+	Before    map[string]any `db:"before"`
+	After     map[string]any `db:"after"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+// Filter selects events, newest first. Zero fields match everything;
+// BeforeID pages through older events.
+type Filter struct {
+	ActorID  *uuid.UUID
+	Action   string
+	Target   string
+	Since    time.Time
+	Until    time.Time
+	BeforeID int64
+	Limit    int
+}
@@ -0,0 +1,67 @@
+package media
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const MaxFilenameLen = 255
+
+// Types maps the accepted content types to the extension of stored files.
+// SVG is left out: it can carry scripts.
+var Types = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type Media struct {
+	ID      uuid.UUID `db:"id"`
+	OwnerID uuid.UUID `db:"owner_id"`
+	// Key names the file in the blob store.
+	Key         string    `db:"key"`
+	Filename    string    `db:"filename"`
+	ContentType string    `db:"content_type"`
+	Size        int64     `db:"size"`
+	SHA256      string    `db:"sha256"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// Sniff returns the content type of data judged by its first bytes, never
+// by the name or type the client sent, and whether it is one of Types.
+func Sniff(data []byte) (string, bool) {
+	ct, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	_, ok := Types[ct]
+	return ct, ok
+}
+
+// Key is where a file of owner is stored.
+func Key(owner, id uuid.UUID, contentType string) string {
+	return "media/" + owner.String() + "/" + id.String() + Types[contentType]
+}
+
+// CleanFilename keeps the base name of a client supplied path, without
+// control characters and cut to MaxFilenameLen runes.
+func CleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	if r := []rune(name); len(r) > MaxFilenameLen {
+		name = string(r[:MaxFilenameLen])
+	}
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,51 @@
+package user
+
+import (
+	"github.com/google/uuid"
+	"slices"
+	"strings"
+	"time"
+)
+
+// API key scopes. A ":write" scope includes the matching ":read" one.
+const (
+	ScopeArticlesRead  = "articles:read"
+	ScopeArticlesWrite = "articles:write"
+	ScopeProfileRead   = "profile:read"
+)
+
+var Scopes = []string{ScopeArticlesRead, ScopeArticlesWrite, ScopeProfileRead}
+
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// GrantsScope reports whether scopes allow scope.
+func GrantsScope(scopes []string, scope string) bool {
+	if slices.Contains(scopes, scope) {
+		return true
+	}
+	if resource, ok := strings.CutSuffix(scope, ":read"); ok {
+		return slices.Contains(scopes, resource+":write")
+	}
+	return false
+}
+
+// APIKey is a long-lived personal token for automation. Only the hash of
+// the key is stored.
+type APIKey struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     []string   `db:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+func (k *APIKey) Active(now time.Time) bool {
+	return k != nil && k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -6,10 +6,60 @@ import (
 )
 
 type User struct {
-	ID        uuid.UUID `db:"id"`
-	Email     string    `db:"email"`
-	Username  string    `db:"username"`
-	Password  string    `db:"password_hash"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID           uuid.UUID `db:"id"`
+	Email        string    `db:"email"`
+	Username     string    `db:"username"`
+	Password     string    `db:"password_hash"`
+	DisplayName  string    `db:"display_name"`
+	Bio          string    `db:"bio"`
+	AvatarURL    string    `db:"avatar_url"`
+	TokenVersion int       `db:"token_version"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
+
+	Role string `db:"role"`
+	// SuspendedAt is set while an admin has suspended the account.
+	SuspendedAt   *time.Time `db:"suspended_at"`
+	SuspendReason string     `db:"suspend_reason"`
+}
+
+// Roles. Admins manage other users.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+func ValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
+}
+
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// ListFilter selects users for the admin listing. Empty fields match all.
+type ListFilter struct {
+	// Query matches username, email or display name, case-insensitively.
+	Query     string
+	Role      string
+	Suspended *bool
+	Limit     int
+	Offset    int
+}
+
+// Profile holds the user-editable fields, nil fields are left unchanged.
+type Profile struct {
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
 }
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ExternalIdentity links an account at an OpenID Connect provider to a user.
+type ExternalIdentity struct {
+	ID          uuid.UUID `db:"id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Provider    string    `db:"provider"`
+	Subject     string    `db:"subject"`
+	Email       string    `db:"email"`
+	CreatedAt   time.Time `db:"created_at"`
+	LastLoginAt time.Time `db:"last_login_at"`
+}
+
+// OIDCLoginState is kept between the redirect to the provider and the
+// callback.
+type OIDCLoginState struct {
+	Provider     string    `db:"provider"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
@@ -0,0 +1,18 @@
+package user
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type TOTP struct {
+	UserID       uuid.UUID  `db:"user_id"`
+	Secret       string     `db:"secret"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+	LastUsedStep int64      `db:"last_used_step"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (t *TOTP) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthClient is a third-party application that may act on behalf of
+// users who authorize it, within Scopes.
+type OAuthClient struct {
+	ID      uuid.UUID `db:"id"`
+	OwnerID uuid.UUID `db:"owner_id"`
+	Name    string    `db:"name"`
+	// SecretHash is empty for public clients.
+	SecretHash   string    `db:"secret_hash"`
+	RedirectURIs []string  `db:"redirect_uris"`
+	Scopes       []string  `db:"scopes"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+// Confidential clients authenticate with a secret at the token endpoint.
+func (c *OAuthClient) Confidential() bool {
+	return c.SecretHash != ""
+}
+
+// AllowsRedirect compares redirect URIs exactly, as OAuth 2.1 requires.
+func (c *OAuthClient) AllowsRedirect(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+// OAuthCode is an authorization code waiting to be redeemed.
+type OAuthCode struct {
+	ClientID    uuid.UUID
+	UserID      uuid.UUID
+	RedirectURI string
+	Scopes      []string
+	// CodeChallenge is the S256 PKCE challenge.
+	CodeChallenge string
+	ExpiresAt     time.Time
+}
+
+// OAuthGrant is what a user authorized a client to do. It holds the
+// current refresh token, which is rotated on every use.
+type OAuthGrant struct {
+	ID         uuid.UUID  `db:"id"`
+	ClientID   uuid.UUID  `db:"client_id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	Scopes     []string   `db:"scopes"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+func (g *OAuthGrant) Active(now time.Time) bool {
+	return g != nil && g.RevokedAt == nil && now.Before(g.ExpiresAt)
+}
+
+// AuthorizedApp is a client the user has active grants for.
+type AuthorizedApp struct {
+	ClientID   uuid.UUID
+	Name       string
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
@@ -0,0 +1,22 @@
+package user
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Session is one login: every access token carries its session ID.
+type Session struct {
+	ID         uuid.UUID  `db:"id"`
+	UserID     uuid.UUID  `db:"user_id"`
+	UserAgent  string     `db:"user_agent"`
+	IP         string     `db:"ip"`
+	CreatedAt  time.Time  `db:"created_at"`
+	LastUsedAt time.Time  `db:"last_used_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
+func (s *Session) Active(now time.Time) bool {
+	return s != nil && s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
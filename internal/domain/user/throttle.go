@@ -0,0 +1,11 @@
+package user
+
+import "time"
+
+// LoginThrottle counts recent failed attempts for a username, IP or MFA challenge.
+type LoginThrottle struct {
+	Key           string     `db:"key"`
+	Failures      int        `db:"failures"`
+	BlockedUntil  *time.Time `db:"blocked_until"`
+	LastFailureAt time.Time  `db:"last_failure_at"`
+}
@@ -0,0 +1,42 @@
+package blob
+
+import (
+	"fmt"
+	"os"
+
+	"gopress/internal/app/ports"
+	"gopress/pkg/env"
+)
+
+// FromEnv builds the blob store selected by MEDIA_STORAGE: local (default)
+// or s3.
+func FromEnv() (ports.BlobStore, error) {
+	switch kind := env.String("MEDIA_STORAGE", "local"); kind {
+	case "local":
+		return NewLocalStore(env.String("MEDIA_DIR", "data/media"))
+	case "s3":
+		useSSL, err := env.Bool("S3_USE_SSL", true)
+		if err != nil {
+			return nil, err
+		}
+		pathStyle, err := env.Bool("S3_PATH_STYLE", false)
+		if err != nil {
+			return nil, err
+		}
+		cfg := S3Config{
+			Endpoint:  env.String("S3_ENDPOINT", "s3.amazonaws.com"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			UseSSL:    useSSL,
+			PathStyle: pathStyle,
+		}
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("S3_BUCKET not set")
+		}
+		return NewS3Store(cfg)
+	default:
+		return nil, fmt.Errorf("unknown MEDIA_STORAGE %q", kind)
+	}
+}
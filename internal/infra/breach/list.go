@@ -0,0 +1,148 @@
+package breach
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopress/internal/app/ports"
+	"gopress/pkg/env"
+)
+
+// Lookups follow the Have I Been Pwned k-anonymity model: the SHA-1 of the
+// password is split into a 5 hex digit prefix and a 35 digit suffix, and
+// only the range of suffixes for that prefix is ever consulted.
+const prefixLen = 5
+
+func rangeKey(plain string) (prefix, suffix string) {
+	sum := sha1.Sum([]byte(plain))
+	h := strings.ToUpper(hex.EncodeToString(sum[:]))
+	return h[:prefixLen], h[prefixLen:]
+}
+
+// FromEnv builds the list configured by BREACHED_PASSWORDS:
+//   - unset: no checking
+//   - a file of "SHA1[:count]" lines, loaded into memory
+//   - a directory of range files named by prefix ("5BAA6" containing
+//     "SUFFIX:count" lines, as written by the HIBP downloader), read on
+//     demand so the full dump doesn't have to fit in memory
+func FromEnv() (ports.BreachedPasswords, error) {
+	path := env.String("BREACHED_PASSWORDS", "")
+	if path == "" {
+		return NewNop(), nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("BREACHED_PASSWORDS: %w", err)
+	}
+	if info.IsDir() {
+		return NewRangeDir(path), nil
+	}
+	return LoadFile(path)
+}
+
+type nop struct{}
+
+func NewNop() ports.BreachedPasswords {
+	return nop{}
+}
+
+func (nop) IsBreached(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+// fileList keeps sorted suffixes per prefix.
+type fileList struct {
+	ranges map[string][]string
+}
+
+func LoadFile(path string) (ports.BreachedPasswords, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open breached passwords: %w", err)
+	}
+	defer f.Close()
+
+	l := &fileList{ranges: make(map[string][]string)}
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		hash, _, _ := strings.Cut(strings.TrimSpace(sc.Text()), ":")
+		if hash == "" {
+			continue
+		}
+		if len(hash) != 40 {
+			return nil, fmt.Errorf("breached passwords %s:%d: not a SHA-1 hash", path, line)
+		}
+		hash = strings.ToUpper(hash)
+		l.ranges[hash[:prefixLen]] = append(l.ranges[hash[:prefixLen]], hash[prefixLen:])
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read breached passwords: %w", err)
+	}
+
+	for _, suffixes := range l.ranges {
+		sort.Strings(suffixes)
+	}
+	return l, nil
+}
+
+func (l *fileList) IsBreached(_ context.Context, plain string) (bool, error) {
+	prefix, suffix := rangeKey(plain)
+	suffixes := l.ranges[prefix]
+	i := sort.SearchStrings(suffixes, suffix)
+	return i < len(suffixes) && suffixes[i] == suffix, nil
+}
+
+type rangeDir struct {
+	dir string
+}
+
+func NewRangeDir(dir string) ports.BreachedPasswords {
+	return &rangeDir{dir: dir}
+}
+
+func (d *rangeDir) IsBreached(_ context.Context, plain string) (bool, error) {
+	prefix, suffix := rangeKey(plain)
+
+	f, err := d.open(prefix)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("open breach range %s: %w", prefix, err)
+	}
+	defer f.Close()
+
+	return scanRange(f, suffix)
+}
+
+func (d *rangeDir) open(prefix string) (*os.File, error) {
+	f, err := os.Open(filepath.Join(d.dir, prefix))
+	if errors.Is(err, os.ErrNotExist) {
+		return os.Open(filepath.Join(d.dir, prefix+".txt"))
+	}
+	return f, err
+}
+
+func scanRange(r io.Reader, suffix string) (bool, error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		s, _, _ := strings.Cut(strings.TrimSpace(sc.Text()), ":")
+		if strings.EqualFold(s, suffix) {
+			return true, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return false, fmt.Errorf("read breach range: %w", err)
+	}
+	return false, nil
+}
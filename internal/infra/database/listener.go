@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	listenerBuffer     = 64
+	listenerRetryDelay = 2 * time.Second
+)
+
+// Listener holds a dedicated connection that LISTENs on Postgres channels
+// and fans notifications out to in-process subscribers.
+//
+// A subscriber channel is closed whenever delivery can no longer be
+// guaranteed: the subscriber fell behind, the connection was lost or it was
+// re-established. Subscribers are expected to resubscribe and re-read the
+// missed state from the database.
+type Listener struct {
+	pool     *pgxpool.Pool
+	channels []string
+
+	mu   sync.Mutex
+	subs map[string]map[chan string]struct{}
+}
+
+func NewListener(pool *pgxpool.Pool, channels ...string) *Listener {
+	subs := make(map[string]map[chan string]struct{}, len(channels))
+	for _, ch := range channels {
+		subs[ch] = make(map[chan string]struct{})
+	}
+	return &Listener{
+		pool:     pool,
+		channels: channels,
+		subs:     subs,
+	}
+}
+
+// Run listens until ctx is cancelled, reconnecting on errors.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		l.resetAll()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("postgres listener: %v, reconnecting", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenerRetryDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire conn: %w", err)
+	}
+	// the connection is in LISTEN state, never return it to the pool
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background())
+
+	for _, ch := range l.channels {
+		if _, err := pgConn.Exec(ctx, "LISTEN "+quoteIdent(ch)); err != nil {
+			return fmt.Errorf("listen %s: %w", ch, err)
+		}
+	}
+	// anyone subscribed while we were disconnected has to catch up
+	l.resetAll()
+
+	for {
+		n, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+		l.dispatch(n.Channel, n.Payload)
+	}
+}
+
+// Subscribe registers a subscriber for channel. The returned channel is
+// closed by the listener (see Listener) or after ctx is done.
+func (l *Listener) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	set, ok := l.subs[channel]
+	if !ok {
+		return nil, fmt.Errorf("channel %q is not listened", channel)
+	}
+
+	sub := make(chan string, listenerBuffer)
+	set[sub] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		l.unsubscribe(channel, sub)
+	}()
+
+	return sub, nil
+}
+
+func (l *Listener) unsubscribe(channel string, sub chan string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.subs[channel][sub]; ok {
+		delete(l.subs[channel], sub)
+		close(sub)
+	}
+}
+
+func (l *Listener) dispatch(channel, payload string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for sub := range l.subs[channel] {
+		select {
+		case sub <- payload:
+		default:
+			// slow subscriber: drop it, it will catch up from the database
+			delete(l.subs[channel], sub)
+			close(sub)
+		}
+	}
+}
+
+func (l *Listener) resetAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, set := range l.subs {
+		for sub := range set {
+			delete(set, sub)
+			close(sub)
+		}
+	}
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+
+	"gopress/internal/app/ports"
+	"gopress/pkg/env"
+)
+
+// FromEnv builds the mailer selected by MAILER: smtp, file or log (default).
+func FromEnv() (ports.Mailer, error) {
+	from := env.String("MAIL_FROM", "gopress <no-reply@localhost>")
+
+	switch kind := env.String("MAILER", "log"); kind {
+	case "smtp":
+		port, err := env.Int("SMTP_PORT", 587)
+		if err != nil {
+			return nil, err
+		}
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, fmt.Errorf("SMTP_HOST not set")
+		}
+		return NewSMTPMailer(SMTPConfig{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}), nil
+	case "file":
+		return NewFileMailer(env.String("MAIL_FILE", "mail.log"), from), nil
+	case "log":
+		return NewLogMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q", kind)
+	}
+}
@@ -0,0 +1,51 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"gopress/internal/app/ports"
+)
+
+// fileMailer appends messages to a file instead of sending them, for local
+// development and tests.
+type fileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(path, from string) ports.Mailer {
+	return &fileMailer{path: path, from: from}
+}
+
+func (m *fileMailer) Send(_ context.Context, mail ports.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open mail file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(compose(m.from, mail), "\r\n\r\n"...)); err != nil {
+		return fmt.Errorf("write mail file: %w", err)
+	}
+	return nil
+}
+
+type logMailer struct{}
+
+// NewLogMailer prints messages to the application log.
+func NewLogMailer() ports.Mailer {
+	return logMailer{}
+}
+
+func (logMailer) Send(_ context.Context, mail ports.Mail) error {
+	log.Printf("mail to %s: %s\n%s", mail.To, mail.Subject, mail.Body)
+	return nil
+}
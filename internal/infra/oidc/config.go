@@ -0,0 +1,55 @@
+package oidc
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopress/pkg/env"
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// ConfigsFromEnv reads the providers listed in OIDC_PROVIDERS (comma
+// separated names). For a provider "corp" it reads OIDC_CORP_ISSUER,
+// OIDC_CORP_CLIENT_ID, OIDC_CORP_CLIENT_SECRET and OIDC_CORP_SCOPES. The
+// redirect URL is OIDC_REDIRECT_BASE_URL + name + "/callback".
+func ConfigsFromEnv() ([]Config, error) {
+	list := env.String("OIDC_PROVIDERS", "")
+	if list == "" {
+		return nil, nil
+	}
+	base := env.String("OIDC_REDIRECT_BASE_URL", "http://localhost:8080/login/oidc/")
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	var configs []Config
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if !nameRe.MatchString(name) {
+			return nil, fmt.Errorf("OIDC_PROVIDERS: invalid provider name %q", name)
+		}
+
+		prefix := EnvPrefix(name)
+		cfg := Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  base + name + "/callback",
+			Scopes:       strings.Fields(env.String(prefix+"SCOPES", "openid email profile")),
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID required", prefix, prefix)
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// EnvPrefix is the variable prefix of a provider: "corp-sso" -> "OIDC_CORP_SSO_".
+func EnvPrefix(name string) string {
+	return "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+}
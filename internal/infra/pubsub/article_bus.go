@@ -0,0 +1,64 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/article"
+	"gopress/internal/infra/database"
+)
+
+// ArticleEventsChannel is the Postgres NOTIFY channel used by the
+// notify_article_event trigger.
+const ArticleEventsChannel = "article_events"
+
+type articleBus struct {
+	listener *database.Listener
+}
+
+func NewArticleBus(listener *database.Listener) ports.ArticleEventBus {
+	return &articleBus{listener: listener}
+}
+
+type articleEventPayload struct {
+	ID        int64             `json:"id"`
+	Type      article.EventType `json:"type"`
+	ArticleID int64             `json:"article_id"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (b *articleBus) Subscribe(ctx context.Context) (<-chan *article.Event, error) {
+	raw, err := b.listener.Subscribe(ctx, ArticleEventsChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *article.Event)
+	go func() {
+		defer close(out)
+		for payload := range raw {
+			var p articleEventPayload
+			if err := json.Unmarshal([]byte(payload), &p); err != nil {
+				log.Printf("article events: bad payload %q: %v", payload, err)
+				continue
+			}
+
+			ev := &article.Event{
+				ID:         p.ID,
+				Type:       p.Type,
+				ArticleID:  p.ArticleID,
+				OccurredAt: p.CreatedAt,
+			}
+			select {
+			case out <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
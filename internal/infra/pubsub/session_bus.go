@@ -0,0 +1,49 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/infra/database"
+)
+
+// SessionRevokedChannel is the Postgres NOTIFY channel used by the
+// notify_session_revoked trigger.
+const SessionRevokedChannel = "session_revoked"
+
+type sessionBus struct {
+	listener *database.Listener
+}
+
+func NewSessionBus(listener *database.Listener) ports.SessionEventBus {
+	return &sessionBus{listener: listener}
+}
+
+func (b *sessionBus) SubscribeRevoked(ctx context.Context) (<-chan uuid.UUID, error) {
+	raw, err := b.listener.Subscribe(ctx, SessionRevokedChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan uuid.UUID)
+	go func() {
+		defer close(out)
+		for payload := range raw {
+			id, err := uuid.Parse(payload)
+			if err != nil {
+				log.Printf("session events: bad payload %q: %v", payload, err)
+				continue
+			}
+
+			select {
+			case out <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
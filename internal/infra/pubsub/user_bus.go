@@ -0,0 +1,49 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/infra/database"
+)
+
+// UserChangedChannel is the Postgres NOTIFY channel used by the
+// notify_user_changed trigger.
+const UserChangedChannel = "user_changed"
+
+type userBus struct {
+	listener *database.Listener
+}
+
+func NewUserBus(listener *database.Listener) ports.UserEventBus {
+	return &userBus{listener: listener}
+}
+
+func (b *userBus) SubscribeChanged(ctx context.Context) (<-chan uuid.UUID, error) {
+	raw, err := b.listener.Subscribe(ctx, UserChangedChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan uuid.UUID)
+	go func() {
+		defer close(out)
+		for payload := range raw {
+			id, err := uuid.Parse(payload)
+			if err != nil {
+				log.Printf("user events: bad payload %q: %v", payload, err)
+				continue
+			}
+
+			select {
+			case out <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,108 @@
+// Package render turns article content into HTML that is safe to embed.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/article"
+)
+
+// highlightStyle is the chroma style of code blocks. Its colours are
+// inlined, so clients need no stylesheet.
+const highlightStyle = "github"
+
+var paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+
+type renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+	// strips all tags, for Text
+	text *bluemonday.Policy
+}
+
+func New() ports.ContentRenderer {
+	return &renderer{
+		markdown: goldmark.New(
+			goldmark.WithExtensions(
+				extension.GFM,
+				highlighting.NewHighlighting(
+					highlighting.WithStyle(highlightStyle),
+					highlighting.WithFormatOptions(chromahtml.TabWidth(4)),
+				),
+			),
+			// raw HTML is allowed in Markdown, the sanitizer decides what stays
+			goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+		),
+		policy: policy(),
+		text:   bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true),
+	}
+}
+
+// policy is the allowlist every rendered document goes through: the user
+// generated content defaults of bluemonday (no scripts, event handlers,
+// iframes or javascript: URLs, rel="nofollow" on links) plus the inline
+// colours of highlighted code and GFM task list checkboxes.
+func policy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration").
+		OnElements("span", "pre")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+func (r *renderer) Render(format article.Format, content string) (string, error) {
+	var out string
+	switch format {
+	case article.FormatMarkdown:
+		var buf bytes.Buffer
+		if err := r.markdown.Convert([]byte(content), &buf); err != nil {
+			return "", fmt.Errorf("render markdown: %w", err)
+		}
+		out = buf.String()
+	case article.FormatHTML:
+		out = content
+	case article.FormatPlain:
+		out = plainHTML(content)
+	default:
+		return "", fmt.Errorf("render: unknown format %q", format)
+	}
+	return r.policy.Sanitize(out), nil
+}
+
+func (r *renderer) Text(format article.Format, content string) (string, error) {
+	out, err := r.Render(format, content)
+	if err != nil {
+		return "", err
+	}
+	text := html.UnescapeString(r.text.Sanitize(out))
+	return strings.Join(strings.Fields(text), " "), nil
+}
+
+// plainHTML escapes text and keeps its paragraphs and line breaks.
+func plainHTML(text string) string {
+	text = strings.ReplaceAll(strings.TrimSpace(text), "\r\n", "\n")
+	if text == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, p := range paragraphBreak.Split(text, -1) {
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(p), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"gopress/internal/domain/article"
+)
+
+func TestRenderSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  article.Format
+		content string
+		// banned must not appear in the lowercased output
+		banned []string
+		// kept must appear as is
+		kept []string
+	}{
+		{"script tag", article.FormatHTML, `<p>hi</p><script>alert(1)</script>`, []string{"<script", "alert(1)"}, []string{"<p>hi</p>"}},
+		{"script in markdown", article.FormatMarkdown, "hi\n\n<script>alert(1)</script>", []string{"<script"}, []string{"<p>hi</p>"}},
+		{"inline raw html in markdown", article.FormatMarkdown, `text <img src=x onerror="alert(1)"> more`, []string{"onerror", "alert"}, []string{"text"}},
+		{"javascript href", article.FormatHTML, `<a href="javascript:alert(1)">x</a>`, []string{"javascript:"}, nil},
+		{"obfuscated javascript href", article.FormatHTML, `<a href="  JaVaScRiPt:alert(1)">x</a>`, []string{"javascript:"}, nil},
+		{"entity encoded javascript href", article.FormatHTML, `<a href="&#106;avascript:alert(1)">x</a>`, []string{"javascript:", "&#106;avascript"}, nil},
+		{"javascript link in markdown", article.FormatMarkdown, `[x](javascript:alert(1))`, []string{"javascript:"}, nil},
+		{"data uri", article.FormatHTML, `<a href="data:text/html,<script>alert(1)</script>">x</a>`, []string{"data:text/html", "<script"}, nil},
+		{"event handler", article.FormatHTML, `<p onclick="alert(1)" onmouseover="x()">hi</p>`, []string{"onclick", "onmouseover"}, []string{"hi"}},
+		{"svg onload", article.FormatHTML, `<svg onload="alert(1)"><circle/></svg>`, []string{"<svg", "onload"}, nil},
+		{"iframe", article.FormatHTML, `<iframe src="https://evil.example"></iframe>`, []string{"<iframe"}, nil},
+		{"style tag", article.FormatHTML, `<style>body{display:none}</style>`, []string{"<style"}, nil},
+		{"style attribute", article.FormatHTML, `<p style="position:fixed;top:0">x</p>`, []string{"position"}, nil},
+		{"form", article.FormatHTML, `<form action="/logout"><button>x</button></form>`, []string{"<form", "action="}, nil},
+		{"non-checkbox input", article.FormatHTML, `<input type="text" value="x">`, []string{`type="text"`}, nil},
+		{"plain text is escaped", article.FormatPlain, `<script>alert(1)</script>`, []string{"<script"}, []string{"&lt;script&gt;"}},
+		{"safe link kept", article.FormatMarkdown, `[docs](https://example.com/docs)`, nil, []string{`href="https://example.com/docs"`, `rel="nofollow"`}},
+		{"task list kept", article.FormatMarkdown, "- [x] done", nil, []string{`type="checkbox"`, "checked"}},
+		{"highlight colours kept", article.FormatMarkdown, "```go\nfunc main() {}\n```", nil, []string{"<pre", "color:"}},
+	}
+
+	r := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := r.Render(tt.format, tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lower := strings.ToLower(out)
+			for _, s := range tt.banned {
+				if strings.Contains(lower, s) {
+					t.Errorf("output contains %q:\n%s", s, out)
+				}
+			}
+			for _, s := range tt.kept {
+				if !strings.Contains(out, s) {
+					t.Errorf("output lacks %q:\n%s", s, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderPlain(t *testing.T) {
+	out, err := New().Render(article.FormatPlain, "one\r\ntwo\n\n  three & four  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<p>one<br>\ntwo</p>\n<p>  three &amp; four</p>\n"; out != want {
+		t.Errorf("Render = %q, want %q", out, want)
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if _, err := New().Render("rst", "x"); err == nil {
+		t.Error("Render of an unknown format succeeded")
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		format  article.Format
+		content string
+		want    string
+	}{
+		{article.FormatMarkdown, "# Title\n\nSome *bold* text &amp; more.", "Title Some bold text & more."},
+		{article.FormatHTML, `<p>a<script>alert(1)</script></p><p>b</p>`, "a b"},
+		{article.FormatPlain, "  x <y>  ", "x <y>"},
+	}
+	for _, tt := range tests {
+		got, err := New().Text(tt.format, tt.content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Text(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
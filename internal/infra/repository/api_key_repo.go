@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type apiKeyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAPIKeyRepo(pool *pgxpool.Pool) ports.APIKeyRepo {
+	return &apiKeyRepo{pool: pool}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at, revoked_at`
+
+func scanAPIKey(row pgx.Row, k *user.APIKey) error {
+	return row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &k.Scopes, &k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt, &k.RevokedAt)
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, k *user.APIKey) error {
+	const query = `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns
+
+	row := r.pool.QueryRow(ctx, query, k.UserID, k.Name, k.Prefix, k.KeyHash, k.Scopes, k.ExpiresAt)
+	if err := scanAPIKey(row, k); err != nil {
+		return fmt.Errorf("create api key: %w", err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepo) GetByHash(ctx context.Context, keyHash string) (*user.APIKey, error) {
+	const query = `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	var k user.APIKey
+	if err := scanAPIKey(r.pool.QueryRow(ctx, query, keyHash), &k); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get api key: %w", err)
+	}
+	return &k, nil
+}
+
+func (r *apiKeyRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]user.APIKey, error) {
+	const query = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []user.APIKey
+	for rows.Next() {
+		var k user.APIKey
+		if err := scanAPIKey(rows, &k); err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	const query = `
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("revoke api key: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *apiKeyRepo) Touch(ctx context.Context, id uuid.UUID, at time.Time, minInterval time.Duration) error {
+	const query = `
+		UPDATE api_keys
+		SET last_used_at = $2
+		WHERE id = $1
+			AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, at, at.Add(-minInterval)); err != nil {
+		return fmt.Errorf("touch api key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/article"
+	"time"
+)
+
+type articleEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewArticleEventRepo(pool *pgxpool.Pool) ports.ArticleEventRepo {
+	return &articleEventRepo{pool: pool}
+}
+
+func (r *articleEventRepo) LastID(ctx context.Context) (int64, error) {
+	const query = `SELECT COALESCE(MAX(id), 0) FROM article_events`
+
+	var id int64
+	if err := r.pool.QueryRow(ctx, query).Scan(&id); err != nil {
+		return 0, fmt.Errorf("get last article event id: %w", err)
+	}
+	return id, nil
+}
+
+func (r *articleEventRepo) ListSince(ctx context.Context, afterID int64, limit int) ([]*article.Event, error) {
+	const query = `
+		SELECT id, type, article_id, created_at
+		FROM article_events
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list article events: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*article.Event
+	for rows.Next() {
+		var e article.Event
+		if err := rows.Scan(&e.ID, &e.Type, &e.ArticleID, &e.OccurredAt); err != nil {
+			return nil, fmt.Errorf("scan article events: %w", err)
+		}
+		res = append(res, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list article events: %w", err)
+	}
+	return res, nil
+}
+
+func (r *articleEventRepo) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.pool.Exec(ctx, `DELETE FROM article_events WHERE created_at < $1`, t)
+	if err != nil {
+		return 0, fmt.Errorf("delete article events: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"gopress/internal/app/ports"
 	"gopress/internal/domain/article"
+	"time"
 )
 
 type articleRepo struct {
@@ -19,88 +20,100 @@ func NewArticleRepo(pool *pgxpool.Pool) ports.ArticleRepo {
 	return &articleRepo{pool: pool}
 }
 
-func (r *articleRepo) Create(ctx context.Context, a *article.Article) error {
-	const query = `
-        INSERT INTO articles (author_id, title, content)
-        VALUES ($1, $2, $3)
-        RETURNING id, created_at, updated_at
-    `
+const articleColumns = `a.id, a.title, a.slug, a.content, a.content_format, a.excerpt, a.word_count, a.reading_minutes, a.author_id, a.version, a.created_at, a.updated_at, a.deleted_at, u.username`
 
-	row := r.pool.QueryRow(ctx, query, a.AuthorID, a.Title, a.Content)
-	if err := row.Scan(&a.ID, &a.CreatedAt, &a.UpdatedAt); err != nil {
-		return fmt.Errorf("insert article: %w", err)
-	}
-	return nil
-}
+// articleSummaryColumns are articleColumns with an empty content, for
+// lists that only show excerpts.
+const articleSummaryColumns = `a.id, a.title, a.slug, '' AS content, a.content_format, a.excerpt, a.word_count, a.reading_minutes, a.author_id, a.version, a.created_at, a.updated_at, a.deleted_at, u.username`
 
-func (r *articleRepo) GetByID(ctx context.Context, id int64) (*article.Article, error) {
-	const query = `
-        SELECT a.id, title, content, author_id, a.created_at, a.updated_at, u.username
-        FROM articles a
-        LEFT JOIN users u on u.id = a.author_id
-        WHERE a.id = $1
-    `
+// articleFrom joins the author for AuthorUsername.
+const articleFrom = `FROM articles a LEFT JOIN users u ON u.id = a.author_id`
+
+func scanArticle(row pgx.Row, a *article.Article) error {
+	return row.Scan(&a.ID, &a.Title, &a.Slug, &a.Content, &a.ContentFormat, &a.Excerpt, &a.WordCount, &a.ReadingMinutes, &a.AuthorID, &a.Version, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt, &a.AuthorUsername)
+}
 
+func (r *articleRepo) getArticle(ctx context.Context, query string, args ...any) (*article.Article, error) {
 	var a article.Article
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&a.ID,
-		&a.Title,
-		&a.Content,
-		&a.AuthorID,
-		&a.CreatedAt,
-		&a.UpdatedAt,
-		&a.AuthorUsername,
-	)
-	if err != nil {
+	if err := scanArticle(conn(ctx, r.pool).QueryRow(ctx, query, args...), &a); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("get article by id: %w", err)
+		return nil, err
 	}
-
 	return &a, nil
 }
 
-func (r *articleRepo) ListByAuthor(ctx context.Context, authorID uuid.UUID) ([]*article.Article, error) {
-	const query = `
-		SELECT a.id, a.title, a.content, a.author_id, a.created_at, a.updated_at, u.username
-		FROM articles a
-		LEFT JOIN users u on u.id = a.author_id
-		WHERE author_id = $1
-		ORDER BY a.created_at DESC
-	`
-
-	rows, err := r.pool.Query(ctx, query, authorID)
+func (r *articleRepo) listArticles(ctx context.Context, query string, args ...any) ([]*article.Article, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("get articles by author: %w", err)
+		return nil, err
 	}
 	defer rows.Close()
 
 	var res []*article.Article
 	for rows.Next() {
 		var a article.Article
-		if err := rows.Scan(
-			&a.ID,
-			&a.Title,
-			&a.Content,
-			&a.AuthorID,
-			&a.CreatedAt,
-			&a.UpdatedAt,
-			&a.AuthorUsername,
-		); err != nil {
-			return nil, fmt.Errorf("scan articles by author: %w", err)
+		if err := scanArticle(rows, &a); err != nil {
+			return nil, err
 		}
 		res = append(res, &a)
 	}
+	return res, rows.Err()
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("get articles by author: %w", err)
+func (r *articleRepo) Create(ctx context.Context, a *article.Article) error {
+	const query = `
+		INSERT INTO articles (author_id, title, slug, content, content_format, excerpt, word_count, reading_minutes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, version, created_at, updated_at
+	`
+
+	row := conn(ctx, r.pool).QueryRow(ctx, query, a.AuthorID, a.Title, a.Slug, a.Content, a.ContentFormat, a.Excerpt, a.WordCount, a.ReadingMinutes)
+	if err := row.Scan(&a.ID, &a.Version, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return fmt.Errorf("insert article: %w", err)
 	}
+	return nil
+}
+
+func (r *articleRepo) GetByID(ctx context.Context, id int64) (*article.Article, error) {
+	const query = `
+		SELECT ` + articleColumns + ` ` + articleFrom + `
+		WHERE a.id = $1
+			AND a.deleted_at IS NULL
+	`
 
+	a, err := r.getArticle(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("get article by id: %w", err)
+	}
+	return a, nil
+}
+
+func (r *articleRepo) ListByAuthor(ctx context.Context, authorID uuid.UUID) ([]*article.Article, error) {
+	const query = `
+		SELECT ` + articleColumns + ` ` + articleFrom + `
+		WHERE a.author_id = $1
+			AND a.deleted_at IS NULL
+		ORDER BY a.created_at DESC
+	`
+
+	res, err := r.listArticles(ctx, query, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("get articles by author: %w", err)
+	}
 	return res, nil
 }
 
 func (r *articleRepo) List(ctx context.Context, limit int, offset int) ([]*article.Article, error) {
+	return r.list(ctx, articleColumns, limit, offset)
+}
+
+func (r *articleRepo) ListSummaries(ctx context.Context, limit int, offset int) ([]*article.Article, error) {
+	return r.list(ctx, articleSummaryColumns, limit, offset)
+}
+
+func (r *articleRepo) list(ctx context.Context, columns string, limit int, offset int) ([]*article.Article, error) {
 	if limit < 1 || limit > 20 {
 		limit = 20
 	}
@@ -108,79 +121,211 @@ func (r *articleRepo) List(ctx context.Context, limit int, offset int) ([]*artic
 		offset = 0
 	}
 
-	const query = `
-		SELECT a.id, author_id, title, content, a.created_at, a.updated_at, u.username
-		FROM articles a
-		LEFT JOIN users u on u.id = a.author_id
-		ORDER BY a.created_at DESC 
+	query := `
+		SELECT ` + columns + ` ` + articleFrom + `
+		WHERE a.deleted_at IS NULL
+		ORDER BY a.created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	res, err := r.listArticles(ctx, query, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("get articles: %w", err)
 	}
-	defer rows.Close()
-
-	var res []*article.Article
-	for rows.Next() {
-		var a article.Article
-		if err := rows.Scan(
-			&a.ID,
-			&a.AuthorID,
-			&a.Title,
-			&a.Content,
-			&a.CreatedAt,
-			&a.UpdatedAt,
-			&a.AuthorUsername,
-		); err != nil {
-			return nil, fmt.Errorf("scan articles: %w", err)
-		}
-		res = append(res, &a)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("get articles: %w", err)
-	}
 	return res, nil
 }
 
-func (r *articleRepo) UpdateOwned(ctx context.Context, id int64, authorID uuid.UUID, title, content string) (bool, error) {
+func (r *articleRepo) UpdateOwned(ctx context.Context, a *article.Article, version int64) (bool, error) {
 	const query = `
 		UPDATE articles
-		SET title = $1, content = $2, updated_at = NOW()
-		WHERE id = $3
-			AND author_id = $4
+		SET title = $1, slug = $2, content = $3, content_format = $4,
+			excerpt = $5, word_count = $6, reading_minutes = $7,
+			version = version + 1, updated_at = NOW()
+		WHERE id = $8
+			AND author_id = $9
+			AND deleted_at IS NULL
+			AND ($10 = 0 OR version = $10)
 	`
 
-	res, err := r.pool.Exec(ctx, query, title, content, id, authorID)
+	res, err := conn(ctx, r.pool).Exec(ctx, query, a.Title, a.Slug, a.Content, a.ContentFormat,
+		a.Excerpt, a.WordCount, a.ReadingMinutes, a.ID, a.AuthorID, version)
 	if err != nil {
 		return false, fmt.Errorf("update owned articles: %w", err)
 	}
 
 	if res.RowsAffected() == 0 {
-		// article isn't found or execute not owner
+		// article isn't found, execute not owner or version changed
 		return false, nil
 	}
 
 	return true, nil
 }
 
-func (r *articleRepo) DeleteOwned(ctx context.Context, id int64, authorID uuid.UUID) (bool, error) {
+func (r *articleRepo) DeleteOwned(ctx context.Context, id int64, authorID uuid.UUID, version int64) (bool, error) {
 	const query = `
-		DELETE FROM articles
+		UPDATE articles
+		SET deleted_at = NOW()
 		WHERE id = $1
 			AND author_id = $2
+			AND deleted_at IS NULL
+			AND ($3 = 0 OR version = $3)
 	`
 
-	res, err := r.pool.Exec(ctx, query, id, authorID)
+	res, err := conn(ctx, r.pool).Exec(ctx, query, id, authorID, version)
 	if err != nil {
 		return false, fmt.Errorf("delete owned articles: %w", err)
 	}
 
 	if res.RowsAffected() == 0 {
-		// article isn't found or execute not owner
+		// article isn't found, execute not owner or version changed
 		return false, nil
 	}
 	return true, nil
 }
+
+func (r *articleRepo) ResolveSlug(ctx context.Context, slug string) (int64, string, error) {
+	const query = `
+		SELECT a.id, a.slug
+		FROM articles a
+		WHERE a.slug = $1
+			AND a.deleted_at IS NULL
+		UNION ALL
+		SELECT a.id, a.slug
+		FROM article_slug_redirects r
+		JOIN articles a ON a.id = r.article_id
+		WHERE r.slug = $1
+			AND a.deleted_at IS NULL
+		LIMIT 1
+	`
+
+	var (
+		id      int64
+		current string
+	)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, slug).Scan(&id, &current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, "", nil
+		}
+		return 0, "", fmt.Errorf("resolve slug: %w", err)
+	}
+	return id, current, nil
+}
+
+func (r *articleRepo) TakenSlugs(ctx context.Context, base string, exceptID int64) ([]string, error) {
+	q := conn(ctx, r.pool)
+
+	if err := lockXact(ctx, q, "article_slug:"+base); err != nil {
+		return nil, fmt.Errorf("lock slug: %w", err)
+	}
+
+	// slugs hold only [a-z0-9-], no LIKE wildcards
+	const query = `
+		SELECT slug FROM articles
+		WHERE (slug = $1 OR slug LIKE $1 || '-%') AND id <> $2
+		UNION
+		SELECT slug FROM article_slug_redirects
+		WHERE (slug = $1 OR slug LIKE $1 || '-%') AND article_id <> $2
+	`
+
+	rows, err := q.Query(ctx, query, base, exceptID)
+	if err != nil {
+		return nil, fmt.Errorf("taken slugs: %w", err)
+	}
+	slugs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("taken slugs: %w", err)
+	}
+	return slugs, nil
+}
+
+func (r *articleRepo) AddSlugRedirect(ctx context.Context, articleID int64, from, to string) error {
+	q := conn(ctx, r.pool)
+
+	const drop = `DELETE FROM article_slug_redirects WHERE slug = $1 AND article_id = $2`
+	if _, err := q.Exec(ctx, drop, to, articleID); err != nil {
+		return fmt.Errorf("drop slug redirect: %w", err)
+	}
+
+	const add = `
+		INSERT INTO article_slug_redirects (slug, article_id)
+		VALUES ($1, $2)
+		ON CONFLICT (slug) DO UPDATE SET article_id = EXCLUDED.article_id, created_at = now()
+	`
+	if _, err := q.Exec(ctx, add, from, articleID); err != nil {
+		return fmt.Errorf("add slug redirect: %w", err)
+	}
+	return nil
+}
+
+func (r *articleRepo) GetTrashed(ctx context.Context, id int64) (*article.Article, error) {
+	const query = `
+		SELECT ` + articleColumns + ` ` + articleFrom + `
+		WHERE a.id = $1
+			AND a.deleted_at IS NOT NULL
+			-- articles of deleted users are in nobody's trash
+			AND a.author_id IS NOT NULL
+	`
+
+	a, err := r.getArticle(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("get trashed article: %w", err)
+	}
+	return a, nil
+}
+
+func (r *articleRepo) ListTrash(ctx context.Context, authorID uuid.UUID) ([]*article.Article, error) {
+	const query = `
+		SELECT ` + articleColumns + ` ` + articleFrom + `
+		WHERE a.author_id = $1
+			AND a.deleted_at IS NOT NULL
+		ORDER BY a.deleted_at DESC
+	`
+
+	res, err := r.listArticles(ctx, query, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("list trash: %w", err)
+	}
+	return res, nil
+}
+
+func (r *articleRepo) RestoreOwned(ctx context.Context, id int64, authorID uuid.UUID) (bool, error) {
+	const query = `
+		UPDATE articles
+		SET deleted_at = NULL
+		WHERE id = $1
+			AND author_id = $2
+			AND deleted_at IS NOT NULL
+	`
+
+	res, err := conn(ctx, r.pool).Exec(ctx, query, id, authorID)
+	if err != nil {
+		return false, fmt.Errorf("restore article: %w", err)
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (r *articleRepo) PurgeOwned(ctx context.Context, id int64, authorID uuid.UUID) (bool, error) {
+	const query = `
+		DELETE FROM articles
+		WHERE id = $1
+			AND author_id = $2
+			AND deleted_at IS NOT NULL
+	`
+
+	res, err := conn(ctx, r.pool).Exec(ctx, query, id, authorID)
+	if err != nil {
+		return false, fmt.Errorf("purge article: %w", err)
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (r *articleRepo) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	const query = `DELETE FROM articles WHERE deleted_at < $1`
+
+	res, err := conn(ctx, r.pool).Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("purge trash: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/audit"
+)
+
+type auditRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewAuditRepo stores audit events in the append-only audit_log table.
+func NewAuditRepo(pool *pgxpool.Pool) ports.AuditLog {
+	return &auditRepo{pool: pool}
+}
+
+const auditColumns = `id, action, actor_id, target, ip, request_id, details, before, after, created_at`
+
+func scanAuditEvent(row pgx.Row, e *audit.Event) error {
+	return row.Scan(&e.ID, &e.Action, &e.ActorID, &e.Target, &e.IP, &e.RequestID, &e.Details, &e.Before, &e.After, &e.CreatedAt)
+}
+
+func (r *auditRepo) Record(ctx context.Context, e *audit.Event) error {
+	const query = `
+		INSERT INTO audit_log (action, actor_id, target, ip, request_id, details, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	row := conn(ctx, r.pool).QueryRow(ctx, query,
+		e.Action, e.ActorID, e.Target, e.IP, e.RequestID, e.Details, e.Before, e.After)
+	if err := row.Scan(&e.ID, &e.CreatedAt); err != nil {
+		return fmt.Errorf("insert audit event: %w", err)
+	}
+	return nil
+}
+
+func (r *auditRepo) List(ctx context.Context, f audit.Filter) ([]*audit.Event, error) {
+	// an action also matches its sub-actions: "auth.login" finds
+	// "auth.login.failed"
+	const query = `
+		SELECT ` + auditColumns + `
+		FROM audit_log
+		WHERE ($1::uuid IS NULL OR actor_id = $1)
+			AND ($2 = '' OR action = $2 OR action LIKE $3 || '.%')
+			AND ($4 = '' OR target = $4)
+			AND ($5::timestamptz IS NULL OR created_at >= $5)
+			AND ($6::timestamptz IS NULL OR created_at < $6)
+			AND ($7 = 0 OR id < $7)
+		ORDER BY id DESC
+		LIMIT $8
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query,
+		f.ActorID, f.Action, escapeLike(f.Action), f.Target,
+		optionalTime(f.Since), optionalTime(f.Until), f.BeforeID, f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*audit.Event
+	for rows.Next() {
+		var e audit.Event
+		if err := scanAuditEvent(rows, &e); err != nil {
+			return nil, fmt.Errorf("scan audit event: %w", err)
+		}
+		res = append(res, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+	return res, nil
+}
+
+func (r *auditRepo) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// lifts the append-only trigger for this transaction
+	if _, err := tx.Exec(ctx, `SELECT set_config('gopress.audit_purge', 'on', true)`); err != nil {
+		return 0, fmt.Errorf("enable audit purge: %w", err)
+	}
+	res, err := tx.Exec(ctx, `DELETE FROM audit_log WHERE created_at < $1`, t)
+	if err != nil {
+		return 0, fmt.Errorf("purge audit log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
+// optionalTime turns a zero time into SQL NULL.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
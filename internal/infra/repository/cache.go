@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/sync/singleflight"
+	"gopress/pkg/lru"
+)
+
+const cacheResubscribeDelay = 2 * time.Second
+
+// cacheStats are published on /debug/vars as repo_cache.<name>_hits etc.
+var cacheStats = expvar.NewMap("repo_cache")
+
+// CacheConfig sizes the read-through caches of NewCachedArticleRepo and
+// NewCachedUserRepo.
+type CacheConfig struct {
+	Size int
+	// TTL bounds how long an entry lives, also when an invalidation is lost.
+	TTL time.Duration
+}
+
+// readThrough keeps rows by key for the cached repositories. Concurrent
+// misses of a key share one query, and a row read while an invalidation
+// happens is not stored, as it may predate the change.
+type readThrough[K comparable, V any] struct {
+	items *lru.Cache[K, *V]
+	group singleflight.Group
+	gen   atomic.Uint64
+
+	hits, misses, invalidations expvar.Int
+}
+
+func newReadThrough[K comparable, V any](name string, cfg CacheConfig) *readThrough[K, V] {
+	c := &readThrough[K, V]{items: lru.New[K, *V](cfg.Size, cfg.TTL)}
+	cacheStats.Set(name+"_hits", &c.hits)
+	cacheStats.Set(name+"_misses", &c.misses)
+	cacheStats.Set(name+"_invalidations", &c.invalidations)
+	cacheStats.Set(name+"_size", expvar.Func(func() any { return c.items.Len() }))
+	return c
+}
+
+// get returns a copy of the cached row or loads it. Inside a transaction
+// the cache is bypassed: the row may be uncommitted.
+func (c *readThrough[K, V]) get(ctx context.Context, key K, load func(ctx context.Context) (*V, error)) (*V, error) {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return load(ctx)
+	}
+
+	if v, ok := c.items.Get(key); ok {
+		c.hits.Add(1)
+		return clone(v), nil
+	}
+	c.misses.Add(1)
+
+	res, err, _ := c.group.Do(fmt.Sprint(key), func() (any, error) {
+		gen := c.gen.Load()
+		// shared by every waiting caller, none of them may cancel it
+		v, err := load(context.WithoutCancel(ctx))
+		if err == nil && v != nil && c.gen.Load() == gen {
+			c.items.Add(key, v)
+		}
+		return v, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return clone(res.(*V)), nil
+}
+
+func (c *readThrough[K, V]) remove(key K) {
+	c.gen.Add(1)
+	c.items.Remove(key)
+	c.invalidations.Add(1)
+}
+
+func (c *readThrough[K, V]) removeFunc(fn func(*V) bool) {
+	c.gen.Add(1)
+	c.items.RemoveFunc(func(_ K, v *V) bool { return fn(v) })
+	c.invalidations.Add(1)
+}
+
+func (c *readThrough[K, V]) purge() {
+	c.gen.Add(1)
+	c.items.Purge()
+	c.invalidations.Add(1)
+}
+
+// callers may change what they get, the cache keeps its own copy
+func clone[V any](v *V) *V {
+	if v == nil {
+		return nil
+	}
+	cp := *v
+	return &cp
+}
+
+// follow calls fn for every message of the subscription and lost when
+// messages may have been missed, resubscribing until ctx is done.
+func follow[T any](ctx context.Context, name string, subscribe func(context.Context) (<-chan T, error), fn func(T), lost func()) {
+	for {
+		ch, err := subscribe(ctx)
+		if err != nil {
+			log.Printf("%s cache: subscribe: %v", name, err)
+		} else {
+			for v := range ch {
+				fn(v)
+			}
+		}
+		lost()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cacheResubscribeDelay):
+		}
+	}
+}
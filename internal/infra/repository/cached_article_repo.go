@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/article"
+)
+
+// CachedArticleRepo serves GetByID, with the author's username, from
+// memory. Run keeps it in step with changes made by other replicas.
+type CachedArticleRepo struct {
+	ports.ArticleRepo
+	cache *readThrough[int64, article.Article]
+}
+
+func NewCachedArticleRepo(next ports.ArticleRepo, cfg CacheConfig) *CachedArticleRepo {
+	return &CachedArticleRepo{
+		ArticleRepo: next,
+		cache:       newReadThrough[int64, article.Article]("articles", cfg),
+	}
+}
+
+func (r *CachedArticleRepo) GetByID(ctx context.Context, id int64) (*article.Article, error) {
+	return r.cache.get(ctx, id, func(ctx context.Context) (*article.Article, error) {
+		return r.ArticleRepo.GetByID(ctx, id)
+	})
+}
+
+// UpdateOwned and DeleteOwned drop the article at once on this replica,
+// the notification after commit drops it again everywhere.
+
+func (r *CachedArticleRepo) UpdateOwned(ctx context.Context, a *article.Article, version int64) (bool, error) {
+	ok, err := r.ArticleRepo.UpdateOwned(ctx, a, version)
+	r.cache.remove(a.ID)
+	return ok, err
+}
+
+func (r *CachedArticleRepo) DeleteOwned(ctx context.Context, id int64, authorID uuid.UUID, version int64) (bool, error) {
+	ok, err := r.ArticleRepo.DeleteOwned(ctx, id, authorID, version)
+	r.cache.remove(id)
+	return ok, err
+}
+
+// Run drops changed articles, and those of users who changed (their
+// username is part of the row), until ctx is done.
+func (r *CachedArticleRepo) Run(ctx context.Context, articles ports.ArticleEventBus, users ports.UserEventBus) {
+	go follow(ctx, "article", users.SubscribeChanged, func(id uuid.UUID) {
+		r.cache.removeFunc(func(a *article.Article) bool { return a.AuthorID == id })
+	}, r.cache.purge)
+
+	follow(ctx, "article", articles.Subscribe, func(ev *article.Event) {
+		r.cache.remove(ev.ArticleID)
+	}, r.cache.purge)
+}
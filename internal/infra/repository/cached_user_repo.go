@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+// CachedUserRepo serves GetByID from memory. Lookups by username or email
+// (logins) always go to the database. Run keeps it in step with changes
+// made by other replicas.
+type CachedUserRepo struct {
+	ports.UserRepo
+	cache *readThrough[uuid.UUID, user.User]
+}
+
+func NewCachedUserRepo(next ports.UserRepo, cfg CacheConfig) *CachedUserRepo {
+	return &CachedUserRepo{
+		UserRepo: next,
+		cache:    newReadThrough[uuid.UUID, user.User]("users", cfg),
+	}
+}
+
+func (r *CachedUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return r.cache.get(ctx, id, func(ctx context.Context) (*user.User, error) {
+		return r.UserRepo.GetByID(ctx, id)
+	})
+}
+
+// The writes drop the user at once on this replica, the notification
+// after commit drops it again everywhere.
+
+func (r *CachedUserRepo) UpdateProfile(ctx context.Context, id uuid.UUID, p user.Profile) (*user.User, error) {
+	defer r.cache.remove(id)
+	return r.UserRepo.UpdateProfile(ctx, id, p)
+}
+
+func (r *CachedUserRepo) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) (int, error) {
+	defer r.cache.remove(id)
+	return r.UserRepo.UpdatePassword(ctx, id, passwordHash)
+}
+
+func (r *CachedUserRepo) UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	defer r.cache.remove(id)
+	return r.UserRepo.UpdatePasswordHash(ctx, id, passwordHash)
+}
+
+func (r *CachedUserRepo) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	defer r.cache.remove(id)
+	return r.UserRepo.MarkEmailVerified(ctx, id)
+}
+
+func (r *CachedUserRepo) SetRole(ctx context.Context, id uuid.UUID, role string) (*user.User, error) {
+	defer r.cache.remove(id)
+	return r.UserRepo.SetRole(ctx, id, role)
+}
+
+func (r *CachedUserRepo) SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time, reason string) (*user.User, error) {
+	defer r.cache.remove(id)
+	return r.UserRepo.SetSuspended(ctx, id, suspendedAt, reason)
+}
+
+func (r *CachedUserRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	defer r.cache.remove(id)
+	return r.UserRepo.Delete(ctx, id)
+}
+
+// Run drops users changed anywhere until ctx is done.
+func (r *CachedUserRepo) Run(ctx context.Context, users ports.UserEventBus) {
+	follow(ctx, "user", users.SubscribeChanged, r.cache.remove, r.cache.purge)
+}
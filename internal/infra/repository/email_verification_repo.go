@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"time"
+)
+
+type emailVerificationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewEmailVerificationRepo(pool *pgxpool.Pool) ports.EmailVerificationRepo {
+	return &emailVerificationRepo{pool: pool}
+}
+
+func (r *emailVerificationRepo) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM email_verification_tokens WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete old verification tokens: %w", err)
+	}
+
+	const query = `
+		INSERT INTO email_verification_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(ctx, query, userID, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("insert verification token: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *emailVerificationRepo) LastSentAt(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	const query = `
+		SELECT created_at
+		FROM email_verification_tokens
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var t time.Time
+	err := r.pool.QueryRow(ctx, query, userID).Scan(&t)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("get last verification token: %w", err)
+	}
+	return t, nil
+}
+
+func (r *emailVerificationRepo) Consume(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	const query = `
+		DELETE FROM email_verification_tokens
+		WHERE token_hash = $1
+			AND expires_at > NOW()
+		RETURNING user_id
+	`
+
+	var userID uuid.UUID
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, fmt.Errorf("consume verification token: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+	"time"
+)
+
+type loginThrottleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewLoginThrottleRepo(pool *pgxpool.Pool) ports.LoginThrottleRepo {
+	return &loginThrottleRepo{pool: pool}
+}
+
+func (r *loginThrottleRepo) Get(ctx context.Context, keys []string) ([]*user.LoginThrottle, error) {
+	const query = `
+		SELECT key, failures, blocked_until, last_failure_at
+		FROM login_throttle
+		WHERE key = ANY($1)
+	`
+
+	rows, err := r.pool.Query(ctx, query, keys)
+	if err != nil {
+		return nil, fmt.Errorf("get login throttle: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*user.LoginThrottle
+	for rows.Next() {
+		var t user.LoginThrottle
+		if err := rows.Scan(&t.Key, &t.Failures, &t.BlockedUntil, &t.LastFailureAt); err != nil {
+			return nil, fmt.Errorf("scan login throttle: %w", err)
+		}
+		res = append(res, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get login throttle: %w", err)
+	}
+	return res, nil
+}
+
+func (r *loginThrottleRepo) RecordFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	const query = `
+		INSERT INTO login_throttle (key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET failures = CASE
+				WHEN login_throttle.last_failure_at < NOW() - $2::interval THEN 1
+				ELSE login_throttle.failures + 1
+			END,
+			last_failure_at = NOW()
+		RETURNING failures
+	`
+
+	var failures int
+	if err := r.pool.QueryRow(ctx, query, key, window).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("record login failure: %w", err)
+	}
+	return failures, nil
+}
+
+func (r *loginThrottleRepo) Block(ctx context.Context, key string, until time.Time) error {
+	const query = `UPDATE login_throttle SET blocked_until = $2 WHERE key = $1`
+
+	if _, err := r.pool.Exec(ctx, query, key, until); err != nil {
+		return fmt.Errorf("block login: %w", err)
+	}
+	return nil
+}
+
+func (r *loginThrottleRepo) Reset(ctx context.Context, key string) error {
+	const query = `DELETE FROM login_throttle WHERE key = $1`
+
+	if _, err := r.pool.Exec(ctx, query, key); err != nil {
+		return fmt.Errorf("reset login throttle: %w", err)
+	}
+	return nil
+}
+
+func (r *loginThrottleRepo) DeleteStale(ctx context.Context, window time.Duration) (int64, error) {
+	const query = `
+		DELETE FROM login_throttle
+		WHERE last_failure_at < NOW() - $1::interval
+			AND (blocked_until IS NULL OR blocked_until < NOW())
+	`
+
+	res, err := r.pool.Exec(ctx, query, window)
+	if err != nil {
+		return 0, fmt.Errorf("delete stale login throttle: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
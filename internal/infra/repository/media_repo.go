@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/media"
+)
+
+const mediaColumns = `id, owner_id, key, filename, content_type, size, sha256, created_at`
+
+type mediaRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewMediaRepo(pool *pgxpool.Pool) ports.MediaRepo {
+	return &mediaRepo{pool: pool}
+}
+
+func scanMedia(row pgx.Row) (*media.Media, error) {
+	var m media.Media
+	err := row.Scan(&m.ID, &m.OwnerID, &m.Key, &m.Filename, &m.ContentType, &m.Size, &m.SHA256, &m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *mediaRepo) Create(ctx context.Context, m *media.Media) error {
+	const query = `
+		INSERT INTO media (id, owner_id, key, filename, content_type, size, sha256)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	row := conn(ctx, r.pool).QueryRow(ctx, query,
+		m.ID, m.OwnerID, m.Key, m.Filename, m.ContentType, m.Size, m.SHA256)
+	if err := row.Scan(&m.CreatedAt); err != nil {
+		return fmt.Errorf("insert media: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaRepo) GetByID(ctx context.Context, id uuid.UUID) (*media.Media, error) {
+	// files of deleted users are only left for the purge
+	const query = `SELECT ` + mediaColumns + ` FROM media WHERE id = $1 AND owner_id IS NOT NULL`
+
+	m, err := scanMedia(conn(ctx, r.pool).QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get media: %w", err)
+	}
+	return m, nil
+}
+
+func (r *mediaRepo) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]*media.Media, error) {
+	const query = `
+		SELECT ` + mediaColumns + `
+		FROM media
+		WHERE owner_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list media: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*media.Media
+	for rows.Next() {
+		m, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan media: %w", err)
+		}
+		res = append(res, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list media: %w", err)
+	}
+	return res, nil
+}
+
+func (r *mediaRepo) DeleteOwned(ctx context.Context, ownerID, id uuid.UUID) (*media.Media, error) {
+	const query = `
+		DELETE FROM media
+		WHERE id = $1 AND owner_id = $2
+		RETURNING ` + mediaColumns
+
+	m, err := scanMedia(conn(ctx, r.pool).QueryRow(ctx, query, id, ownerID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("delete media: %w", err)
+	}
+	return m, nil
+}
+
+func (r *mediaRepo) Usage(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	q := conn(ctx, r.pool)
+
+	if err := lockXact(ctx, q, "media_usage:"+ownerID.String()); err != nil {
+		return 0, fmt.Errorf("lock media usage: %w", err)
+	}
+
+	var used int64
+	err := q.QueryRow(ctx, `SELECT COALESCE(SUM(size), 0) FROM media WHERE owner_id = $1`, ownerID).Scan(&used)
+	if err != nil {
+		return 0, fmt.Errorf("media usage: %w", err)
+	}
+	return used, nil
+}
+
+func (r *mediaRepo) OrphanedKeys(ctx context.Context, limit int) ([]string, error) {
+	const query = `
+		SELECT key FROM media
+		WHERE owner_id IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("orphaned media: %w", err)
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("orphaned media: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *mediaRepo) DeleteOrphaned(ctx context.Context, key string) error {
+	const query = `DELETE FROM media WHERE key = $1 AND owner_id IS NULL`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, key); err != nil {
+		return fmt.Errorf("delete orphaned media: %w", err)
+	}
+	return nil
+}
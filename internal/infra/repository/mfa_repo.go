@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type mfaRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewMFARepo(pool *pgxpool.Pool) ports.MFARepo {
+	return &mfaRepo{pool: pool}
+}
+
+func (r *mfaRepo) GetTOTP(ctx context.Context, userID uuid.UUID) (*user.TOTP, error) {
+	const query = `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at
+		FROM user_totp
+		WHERE user_id = $1
+	`
+
+	var t user.TOTP
+	err := r.pool.QueryRow(ctx, query, userID).Scan(
+		&t.UserID,
+		&t.Secret,
+		&t.EnabledAt,
+		&t.LastUsedStep,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get totp: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *mfaRepo) SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	const query = `
+		INSERT INTO user_totp (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+		WHERE user_totp.enabled_at IS NULL
+	`
+
+	if _, err := r.pool.Exec(ctx, query, userID, secret); err != nil {
+		return fmt.Errorf("save totp secret: %w", err)
+	}
+	return nil
+}
+
+func (r *mfaRepo) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	const enable = `
+		UPDATE user_totp
+		SET enabled_at = NOW(), last_used_step = $2
+		WHERE user_id = $1
+	`
+	if _, err := tx.Exec(ctx, enable, userID, step); err != nil {
+		return fmt.Errorf("enable totp: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	const insert = `
+		INSERT INTO mfa_recovery_codes (user_id, code_hash)
+		SELECT $1, unnest($2::text[])
+	`
+	if _, err := tx.Exec(ctx, insert, userID, recoveryCodeHashes); err != nil {
+		return fmt.Errorf("insert recovery codes: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *mfaRepo) DisableTOTP(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete totp: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *mfaRepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	const query = `
+		UPDATE user_totp
+		SET last_used_step = $2
+		WHERE user_id = $1
+			AND last_used_step < $2
+	`
+
+	res, err := r.pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("use totp step: %w", err)
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (r *mfaRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	const query = `
+		UPDATE mfa_recovery_codes
+		SET used_at = NOW()
+		WHERE id = (
+			SELECT id FROM mfa_recovery_codes
+			WHERE user_id = $1
+				AND code_hash = $2
+				AND used_at IS NULL
+			LIMIT 1
+		)
+	`
+
+	res, err := r.pool.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("use recovery code: %w", err)
+	}
+	return res.RowsAffected() > 0, nil
+}
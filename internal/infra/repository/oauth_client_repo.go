@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type oauthClientRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOAuthClientRepo(pool *pgxpool.Pool) ports.OAuthClientRepo {
+	return &oauthClientRepo{pool: pool}
+}
+
+const oauthClientColumns = `id, owner_id, name, COALESCE(secret_hash, ''), redirect_uris, scopes, created_at`
+
+func scanOAuthClient(row pgx.Row, c *user.OAuthClient) error {
+	return row.Scan(&c.ID, &c.OwnerID, &c.Name, &c.SecretHash, &c.RedirectURIs, &c.Scopes, &c.CreatedAt)
+}
+
+func (r *oauthClientRepo) Create(ctx context.Context, c *user.OAuthClient) error {
+	const query = `
+		INSERT INTO oauth_clients (owner_id, name, secret_hash, redirect_uris, scopes)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		RETURNING ` + oauthClientColumns
+
+	row := r.pool.QueryRow(ctx, query, c.OwnerID, c.Name, c.SecretHash, c.RedirectURIs, c.Scopes)
+	if err := scanOAuthClient(row, c); err != nil {
+		return fmt.Errorf("create oauth client: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthClientRepo) Get(ctx context.Context, id uuid.UUID) (*user.OAuthClient, error) {
+	const query = `SELECT ` + oauthClientColumns + ` FROM oauth_clients WHERE id = $1`
+
+	var c user.OAuthClient
+	if err := scanOAuthClient(r.pool.QueryRow(ctx, query, id), &c); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get oauth client: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *oauthClientRepo) ListByOwner(ctx context.Context, ownerID uuid.UUID) ([]user.OAuthClient, error) {
+	const query = `
+		SELECT ` + oauthClientColumns + `
+		FROM oauth_clients
+		WHERE owner_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("list oauth clients: %w", err)
+	}
+	defer rows.Close()
+
+	var clients []user.OAuthClient
+	for rows.Next() {
+		var c user.OAuthClient
+		if err := scanOAuthClient(rows, &c); err != nil {
+			return nil, fmt.Errorf("scan oauth client: %w", err)
+		}
+		clients = append(clients, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list oauth clients: %w", err)
+	}
+	return clients, nil
+}
+
+func (r *oauthClientRepo) Delete(ctx context.Context, ownerID, id uuid.UUID) (bool, error) {
+	const query = `DELETE FROM oauth_clients WHERE id = $1 AND owner_id = $2`
+
+	tag, err := r.pool.Exec(ctx, query, id, ownerID)
+	if err != nil {
+		return false, fmt.Errorf("delete oauth client: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type oauthCodeRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOAuthCodeRepo(pool *pgxpool.Pool) ports.OAuthCodeRepo {
+	return &oauthCodeRepo{pool: pool}
+}
+
+func (r *oauthCodeRepo) Create(ctx context.Context, codeHash string, c user.OAuthCode) error {
+	// codes that were never redeemed are cleaned up on the way
+	if _, err := r.pool.Exec(ctx, `DELETE FROM oauth_codes WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("delete expired oauth codes: %w", err)
+	}
+
+	const query = `
+		INSERT INTO oauth_codes (code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.pool.Exec(ctx, query, codeHash, c.ClientID, c.UserID, c.RedirectURI, c.Scopes, c.CodeChallenge, c.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("insert oauth code: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthCodeRepo) Consume(ctx context.Context, codeHash string) (*user.OAuthCode, error) {
+	const query = `
+		DELETE FROM oauth_codes
+		WHERE code_hash = $1
+		RETURNING client_id, user_id, redirect_uri, scopes, code_challenge, expires_at
+	`
+
+	var c user.OAuthCode
+	err := r.pool.QueryRow(ctx, query, codeHash).Scan(&c.ClientID, &c.UserID, &c.RedirectURI, &c.Scopes, &c.CodeChallenge, &c.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("consume oauth code: %w", err)
+	}
+	return &c, nil
+}
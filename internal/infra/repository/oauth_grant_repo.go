@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type oauthGrantRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOAuthGrantRepo(pool *pgxpool.Pool) ports.OAuthGrantRepo {
+	return &oauthGrantRepo{pool: pool}
+}
+
+const oauthGrantColumns = `id, client_id, user_id, scopes, expires_at, created_at, last_used_at, revoked_at`
+
+func scanOAuthGrant(row pgx.Row, g *user.OAuthGrant) error {
+	return row.Scan(&g.ID, &g.ClientID, &g.UserID, &g.Scopes, &g.ExpiresAt, &g.CreatedAt, &g.LastUsedAt, &g.RevokedAt)
+}
+
+func (r *oauthGrantRepo) Create(ctx context.Context, g *user.OAuthGrant, refreshHash string) error {
+	const query = `
+		INSERT INTO oauth_grants (client_id, user_id, scopes, refresh_token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + oauthGrantColumns
+
+	row := r.pool.QueryRow(ctx, query, g.ClientID, g.UserID, g.Scopes, refreshHash, g.ExpiresAt)
+	if err := scanOAuthGrant(row, g); err != nil {
+		return fmt.Errorf("create oauth grant: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthGrantRepo) Get(ctx context.Context, id uuid.UUID) (*user.OAuthGrant, error) {
+	const query = `SELECT ` + oauthGrantColumns + ` FROM oauth_grants WHERE id = $1`
+	return r.getOne(ctx, query, id)
+}
+
+func (r *oauthGrantRepo) GetByRefreshHash(ctx context.Context, refreshHash string) (*user.OAuthGrant, error) {
+	const query = `SELECT ` + oauthGrantColumns + ` FROM oauth_grants WHERE refresh_token_hash = $1`
+	return r.getOne(ctx, query, refreshHash)
+}
+
+func (r *oauthGrantRepo) getOne(ctx context.Context, query string, arg any) (*user.OAuthGrant, error) {
+	var g user.OAuthGrant
+	if err := scanOAuthGrant(r.pool.QueryRow(ctx, query, arg), &g); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get oauth grant: %w", err)
+	}
+	return &g, nil
+}
+
+func (r *oauthGrantRepo) Rotate(ctx context.Context, id uuid.UUID, oldHash, newHash string, expiresAt, now time.Time) (bool, error) {
+	const query = `
+		UPDATE oauth_grants
+		SET refresh_token_hash = $3, expires_at = $4, last_used_at = $5
+		WHERE id = $1 AND refresh_token_hash = $2
+			AND revoked_at IS NULL AND expires_at > $5
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, oldHash, newHash, expiresAt, now)
+	if err != nil {
+		return false, fmt.Errorf("rotate refresh token: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *oauthGrantRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	const query = `UPDATE oauth_grants SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.pool.Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("revoke oauth grant: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthGrantRepo) RevokeClient(ctx context.Context, userID, clientID uuid.UUID) (bool, error) {
+	const query = `
+		UPDATE oauth_grants
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND client_id = $2 AND revoked_at IS NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userID, clientID)
+	if err != nil {
+		return false, fmt.Errorf("revoke oauth grants: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *oauthGrantRepo) ListApps(ctx context.Context, userID uuid.UUID) ([]user.AuthorizedApp, error) {
+	const query = `
+		SELECT c.id, c.name,
+			ARRAY(
+				SELECT DISTINCT s
+				FROM oauth_grants g2, unnest(g2.scopes) s
+				WHERE g2.user_id = $1 AND g2.client_id = c.id
+					AND g2.revoked_at IS NULL AND g2.expires_at > NOW()
+				ORDER BY s
+			),
+			MIN(g.created_at), MAX(g.last_used_at)
+		FROM oauth_grants g
+		JOIN oauth_clients c ON c.id = g.client_id
+		WHERE g.user_id = $1 AND g.revoked_at IS NULL AND g.expires_at > NOW()
+		GROUP BY c.id
+		ORDER BY MAX(COALESCE(g.last_used_at, g.created_at)) DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list authorized apps: %w", err)
+	}
+	defer rows.Close()
+
+	var apps []user.AuthorizedApp
+	for rows.Next() {
+		var a user.AuthorizedApp
+		if err := rows.Scan(&a.ClientID, &a.Name, &a.Scopes, &a.CreatedAt, &a.LastUsedAt); err != nil {
+			return nil, fmt.Errorf("scan authorized app: %w", err)
+		}
+		apps = append(apps, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list authorized apps: %w", err)
+	}
+	return apps, nil
+}
+
+func (r *oauthGrantRepo) Covers(ctx context.Context, userID, clientID uuid.UUID, scopes []string) (bool, error) {
+	const query = `
+		SELECT EXISTS (
+			SELECT 1 FROM oauth_grants
+			WHERE user_id = $1 AND client_id = $2
+				AND revoked_at IS NULL AND expires_at > NOW()
+				AND scopes @> $3
+		)
+	`
+
+	var ok bool
+	if err := r.pool.QueryRow(ctx, query, userID, clientID, scopes).Scan(&ok); err != nil {
+		return false, fmt.Errorf("check oauth grant: %w", err)
+	}
+	return ok, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type oidcStateRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOIDCStateRepo(pool *pgxpool.Pool) ports.OIDCStateRepo {
+	return &oidcStateRepo{pool: pool}
+}
+
+func (r *oidcStateRepo) Create(ctx context.Context, stateHash string, s user.OIDCLoginState) error {
+	// abandoned logins are cleaned up on the way
+	if _, err := r.pool.Exec(ctx, `DELETE FROM oidc_login_states WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("delete expired oidc states: %w", err)
+	}
+
+	const query = `
+		INSERT INTO oidc_login_states (state_hash, provider, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := r.pool.Exec(ctx, query, stateHash, s.Provider, s.Nonce, s.CodeVerifier, s.ExpiresAt); err != nil {
+		return fmt.Errorf("insert oidc state: %w", err)
+	}
+	return nil
+}
+
+func (r *oidcStateRepo) Consume(ctx context.Context, stateHash string) (*user.OIDCLoginState, error) {
+	const query = `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1
+		RETURNING provider, nonce, code_verifier, expires_at
+	`
+
+	var s user.OIDCLoginState
+	err := r.pool.QueryRow(ctx, query, stateHash).Scan(&s.Provider, &s.Nonce, &s.CodeVerifier, &s.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("consume oidc state: %w", err)
+	}
+	return &s, nil
+}
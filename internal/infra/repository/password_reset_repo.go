@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"time"
+)
+
+type passwordResetRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPasswordResetRepo(pool *pgxpool.Pool) ports.PasswordResetRepo {
+	return &passwordResetRepo{pool: pool}
+}
+
+func (r *passwordResetRepo) Create(ctx context.Context, userID uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// only the latest link works
+	if _, err := tx.Exec(ctx, `DELETE FROM password_reset_tokens WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete old reset tokens: %w", err)
+	}
+
+	const query = `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := tx.Exec(ctx, query, userID, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("insert reset token: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *passwordResetRepo) Peek(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	const query = `
+		SELECT user_id
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+			AND used_at IS NULL
+			AND expires_at > NOW()
+	`
+
+	var userID uuid.UUID
+	err := conn(ctx, r.pool).QueryRow(ctx, query, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, fmt.Errorf("peek reset token: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *passwordResetRepo) Consume(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	const query = `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1
+			AND used_at IS NULL
+			AND expires_at > NOW()
+		RETURNING user_id
+	`
+
+	var userID uuid.UUID
+	err := conn(ctx, r.pool).QueryRow(ctx, query, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, nil
+		}
+		return uuid.Nil, fmt.Errorf("consume reset token: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type sessionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSessionRepo(pool *pgxpool.Pool) ports.SessionRepo {
+	return &sessionRepo{pool: pool}
+}
+
+const sessionColumns = `id, user_id, user_agent, ip, created_at, last_used_at, expires_at, revoked_at`
+
+func scanSession(row pgx.Row, s *user.Session) error {
+	return row.Scan(&s.ID, &s.UserID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt, &s.RevokedAt)
+}
+
+func (r *sessionRepo) Create(ctx context.Context, s *user.Session) error {
+	const query = `
+		INSERT INTO user_sessions (user_id, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + sessionColumns
+
+	if err := scanSession(conn(ctx, r.pool).QueryRow(ctx, query, s.UserID, s.UserAgent, s.IP, s.ExpiresAt), s); err != nil {
+		return fmt.Errorf("create session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepo) Get(ctx context.Context, id uuid.UUID) (*user.Session, error) {
+	const query = `SELECT ` + sessionColumns + ` FROM user_sessions WHERE id = $1`
+
+	var s user.Session
+	if err := scanSession(conn(ctx, r.pool).QueryRow(ctx, query, id), &s); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *sessionRepo) ListActive(ctx context.Context, userID uuid.UUID) ([]user.Session, error) {
+	const query = `
+		SELECT ` + sessionColumns + `
+		FROM user_sessions
+		WHERE user_id = $1
+			AND revoked_at IS NULL
+			AND expires_at > NOW()
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []user.Session
+	for rows.Next() {
+		var s user.Session
+		if err := scanSession(rows, &s); err != nil {
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepo) Touch(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	const query = `
+		UPDATE user_sessions
+		SET last_used_at = $2,
+			ip = CASE WHEN $3 = '' THEN ip ELSE $3 END
+		WHERE id = $1
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, at, ip); err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepo) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	const query = `
+		UPDATE user_sessions
+		SET revoked_at = NOW()
+		WHERE id = $1
+			AND user_id = $2
+			AND revoked_at IS NULL
+			AND expires_at > NOW()
+	`
+
+	tag, err := conn(ctx, r.pool).Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("revoke session: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *sessionRepo) RevokeAll(ctx context.Context, userID, keep uuid.UUID) ([]uuid.UUID, error) {
+	const query = `
+		UPDATE user_sessions
+		SET revoked_at = NOW()
+		WHERE user_id = $1
+			AND id <> $2
+			AND revoked_at IS NULL
+			AND expires_at > NOW()
+		RETURNING id
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, userID, keep)
+	if err != nil {
+		return nil, fmt.Errorf("revoke sessions: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("revoke sessions: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopress/internal/app/ports"
+	"gopress/internal/domain/user"
+)
+
+type userIdentityRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewUserIdentityRepo(pool *pgxpool.Pool) ports.UserIdentityRepo {
+	return &userIdentityRepo{pool: pool}
+}
+
+const identityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func scanIdentity(row pgx.Row, i *user.ExternalIdentity) error {
+	return row.Scan(&i.ID, &i.UserID, &i.Provider, &i.Subject, &i.Email, &i.CreatedAt, &i.LastLoginAt)
+}
+
+func (r *userIdentityRepo) Get(ctx context.Context, provider, subject string) (*user.ExternalIdentity, error) {
+	const query = `SELECT ` + identityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	var i user.ExternalIdentity
+	if err := scanIdentity(r.pool.QueryRow(ctx, query, provider, subject), &i); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get identity: %w", err)
+	}
+	return &i, nil
+}
+
+func (r *userIdentityRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]user.ExternalIdentity, error) {
+	const query = `
+		SELECT ` + identityColumns + `
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list identities: %w", err)
+	}
+	defer rows.Close()
+
+	var res []user.ExternalIdentity
+	for rows.Next() {
+		var i user.ExternalIdentity
+		if err := scanIdentity(rows, &i); err != nil {
+			return nil, fmt.Errorf("scan identity: %w", err)
+		}
+		res = append(res, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list identities: %w", err)
+	}
+	return res, nil
+}
+
+func (r *userIdentityRepo) Create(ctx context.Context, i *user.ExternalIdentity) error {
+	const query = `
+		INSERT INTO user_identities (user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + identityColumns
+
+	if err := scanIdentity(r.pool.QueryRow(ctx, query, i.UserID, i.Provider, i.Subject, i.Email), i); err != nil {
+		return fmt.Errorf("create identity: %w", err)
+	}
+	return nil
+}
+
+func (r *userIdentityRepo) Touch(ctx context.Context, id uuid.UUID, email string) error {
+	const query = `
+		UPDATE user_identities
+		SET last_login_at = NOW(), email = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.pool.Exec(ctx, query, id, email); err != nil {
+		return fmt.Errorf("touch identity: %w", err)
+	}
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -11,6 +14,8 @@ import (
 	"gopress/internal/domain/user"
 )
 
+const userColumns = `id, email, username, password_hash, display_name, bio, avatar_url, token_version, created_at, updated_at, email_verified_at, role, suspended_at, suspend_reason`
+
 type userRepo struct {
 	pool *pgxpool.Pool
 }
@@ -19,6 +24,30 @@ func NewUserRepo(pool *pgxpool.Pool) ports.UserRepo {
 	return &userRepo{pool: pool}
 }
 
+func scanUser(row pgx.Row) (*user.User, error) {
+	var u user.User
+	err := row.Scan(
+		&u.ID,
+		&u.Email,
+		&u.Username,
+		&u.Password,
+		&u.DisplayName,
+		&u.Bio,
+		&u.AvatarURL,
+		&u.TokenVersion,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+		&u.EmailVerifiedAt,
+		&u.Role,
+		&u.SuspendedAt,
+		&u.SuspendReason,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
 func (r *userRepo) Create(ctx context.Context, u *user.User) error {
 	const query = `
 		INSERT INTO users (email, username, password_hash)
@@ -26,7 +55,7 @@ func (r *userRepo) Create(ctx context.Context, u *user.User) error {
 		RETURNING id, created_at
 	`
 
-	row := r.pool.QueryRow(ctx, query, u.Email, u.Username, u.Password)
+	row := conn(ctx, r.pool).QueryRow(ctx, query, u.Email, u.Username, u.Password)
 	if err := row.Scan(&u.ID, &u.CreatedAt); err != nil {
 		return fmt.Errorf("insert user: %w", err)
 	}
@@ -35,54 +64,184 @@ func (r *userRepo) Create(ctx context.Context, u *user.User) error {
 }
 func (r *userRepo) GetByUsername(ctx context.Context, username string) (*user.User, error) {
 	const query = `
-		SELECT id, email, username, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE username = $1
 	`
-	var u user.User
-	err := r.pool.QueryRow(ctx, query, username).Scan(
-		&u.ID,
-		&u.Email,
-		&u.Username,
-		&u.Password,
-		&u.CreatedAt,
-		&u.UpdatedAt,
-	)
+	u, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, username))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("get user by username: %w", err)
 	}
-	return &u, nil
+	return u, nil
+}
+func (r *userRepo) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	const query = `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE lower(email) = lower($1)
+	`
+	u, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user by email: %w", err)
+	}
+	return u, nil
 }
 func (r *userRepo) GetByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
 	const query = `
-		SELECT id, email, username, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = $1
 	`
-	var u user.User
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&u.ID,
-		&u.Email,
-		&u.Username,
-		&u.Password,
-		&u.CreatedAt,
-		&u.UpdatedAt,
-	)
+	u, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("get user by id: %w", err)
 	}
-	return &u, nil
+	return u, nil
 }
+func (r *userRepo) UpdateProfile(ctx context.Context, id uuid.UUID, p user.Profile) (*user.User, error) {
+	const query = `
+		UPDATE users
+		SET display_name = COALESCE($2, display_name),
+			bio = COALESCE($3, bio),
+			avatar_url = COALESCE($4, avatar_url),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	u, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, id, p.DisplayName, p.Bio, p.AvatarURL))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("update user profile: %w", err)
+	}
+	return u, nil
+}
+func (r *userRepo) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) (int, error) {
+	const query = `
+		UPDATE users
+		SET password_hash = $2,
+			token_version = token_version + 1,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING token_version
+	`
+
+	var version int
+	if err := conn(ctx, r.pool).QueryRow(ctx, query, id, passwordHash).Scan(&version); err != nil {
+		return 0, fmt.Errorf("update user password: %w", err)
+	}
+	return version, nil
+}
+
+func (r *userRepo) UpdatePasswordHash(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	const query = `
+		UPDATE users
+		SET password_hash = $2
+		WHERE id = $1
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id, passwordHash); err != nil {
+		return fmt.Errorf("update user password hash: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepo) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	const query = `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, NOW())
+		WHERE id = $1
+	`
+
+	if _, err := conn(ctx, r.pool).Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("mark email verified: %w", err)
+	}
+	return nil
+}
+func (r *userRepo) List(ctx context.Context, f user.ListFilter) ([]*user.User, error) {
+	const query = `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE ($1 = '' OR username ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%' OR display_name ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR role = $2)
+			AND ($3::boolean IS NULL OR (suspended_at IS NOT NULL) = $3)
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := conn(ctx, r.pool).Query(ctx, query, escapeLike(f.Query), f.Role, f.Suspended, f.Limit, f.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*user.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	return users, nil
+}
+
+func (r *userRepo) SetRole(ctx context.Context, id uuid.UUID, role string) (*user.User, error) {
+	const query = `
+		UPDATE users
+		SET role = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	u, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, id, role))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("set user role: %w", err)
+	}
+	return u, nil
+}
+
+func (r *userRepo) SetSuspended(ctx context.Context, id uuid.UUID, suspendedAt *time.Time, reason string) (*user.User, error) {
+	const query = `
+		UPDATE users
+		SET suspended_at = $2, suspend_reason = $3, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	u, err := scanUser(conn(ctx, r.pool).QueryRow(ctx, query, id, suspendedAt, reason))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("set user suspension: %w", err)
+	}
+	return u, nil
+}
+
+// escapeLike makes s match literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 func (r *userRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	const query = `DELETE FROM users WHERE id = $1`
 
-	cmdTag, err := r.pool.Exec(ctx, query, id)
+	cmdTag, err := conn(ctx, r.pool).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("delete user: %w", err)
 	}
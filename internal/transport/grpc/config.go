@@ -0,0 +1,164 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopress/pkg/env"
+)
+
+type Config struct {
+	Addr string
+
+	// Reflection registers grpc.reflection so grpcurl & co can explore the API.
+	Reflection bool
+
+	// TrustProxyHeaders takes the client IP from x-forwarded-for metadata.
+	TrustProxyHeaders bool
+
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
+
+	Keepalive KeepaliveConfig
+	TLS       TLSConfig
+}
+
+type KeepaliveConfig struct {
+	// server → client pings
+	Time    time.Duration
+	Timeout time.Duration
+
+	MaxConnectionIdle     time.Duration
+	MaxConnectionAge      time.Duration
+	MaxConnectionAgeGrace time.Duration
+
+	// client → server ping enforcement
+	MinTime             time.Duration
+	PermitWithoutStream bool
+}
+
+type ClientAuth string
+
+const (
+	ClientAuthNone    ClientAuth = "none"
+	ClientAuthRequest ClientAuth = "request"
+	ClientAuthRequire ClientAuth = "require"
+)
+
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile enables mutual TLS: client certificates are verified
+	// against it according to ClientAuth.
+	ClientCAFile string
+	ClientAuth   ClientAuth
+}
+
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// ConfigFromEnv reads GRPC_* environment variables.
+func ConfigFromEnv() (Config, error) {
+	addr := env.String("GRPC_PORT", ":50051")
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+
+	cfg := Config{
+		Addr: addr,
+		TLS: TLSConfig{
+			CertFile:     os.Getenv("GRPC_TLS_CERT_FILE"),
+			KeyFile:      os.Getenv("GRPC_TLS_KEY_FILE"),
+			ClientCAFile: os.Getenv("GRPC_TLS_CLIENT_CA_FILE"),
+			ClientAuth:   ClientAuth(env.String("GRPC_TLS_CLIENT_AUTH", string(ClientAuthRequire))),
+		},
+	}
+
+	var errs []error
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	var err error
+
+	cfg.Reflection, err = env.Bool("GRPC_REFLECTION", false)
+	collect(err)
+	cfg.TrustProxyHeaders, err = env.Bool("TRUST_PROXY_HEADERS", false)
+	collect(err)
+	cfg.MaxRecvMsgSize, err = env.Int("GRPC_MAX_RECV_MSG_BYTES", 4<<20)
+	collect(err)
+	cfg.MaxSendMsgSize, err = env.Int("GRPC_MAX_SEND_MSG_BYTES", 4<<20)
+	collect(err)
+
+	ka := &cfg.Keepalive
+	ka.Time, err = env.Duration("GRPC_KEEPALIVE_TIME", 2*time.Hour)
+	collect(err)
+	ka.Timeout, err = env.Duration("GRPC_KEEPALIVE_TIMEOUT", 20*time.Second)
+	collect(err)
+	ka.MaxConnectionIdle, err = env.Duration("GRPC_MAX_CONNECTION_IDLE", 0)
+	collect(err)
+	ka.MaxConnectionAge, err = env.Duration("GRPC_MAX_CONNECTION_AGE", 0)
+	collect(err)
+	ka.MaxConnectionAgeGrace, err = env.Duration("GRPC_MAX_CONNECTION_AGE_GRACE", 0)
+	collect(err)
+	ka.MinTime, err = env.Duration("GRPC_KEEPALIVE_MIN_TIME", 5*time.Minute)
+	collect(err)
+	ka.PermitWithoutStream, err = env.Bool("GRPC_KEEPALIVE_PERMIT_WITHOUT_STREAM", false)
+	collect(err)
+
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c TLSConfig) build() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("both GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE are required for TLS")
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls key pair: %w", err)
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.ClientCAFile == "" {
+		return tlsCfg, nil
+	}
+
+	pem, err := os.ReadFile(c.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client ca: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("client ca %s: no certificates found", c.ClientCAFile)
+	}
+	tlsCfg.ClientCAs = pool
+
+	switch c.ClientAuth {
+	case ClientAuthRequire:
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	case ClientAuthRequest:
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthNone:
+		tlsCfg.ClientAuth = tls.NoClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth mode %q", c.ClientAuth)
+	}
+
+	return tlsCfg, nil
+}
@@ -2,6 +2,7 @@ package interceptor
 
 import (
 	"context"
+	"errors"
 	"strings"
 
 	"github.com/google/uuid"
@@ -10,29 +11,54 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
-	jwtpkg "gopress/pkg/jwt"
+	optionspb "gopress/api/proto/options"
+	authSvc "gopress/internal/app/auth"
 )
 
 type ctxKey string
 
 const CtxUserIDKey ctxKey = "user_id"
 const CtxUsernameKey ctxKey = "username"
+const CtxSessionIDKey ctxKey = "session_id"
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*authSvc.Identity, error)
+}
 
 type AuthInterceptor struct {
-	jwtManager *jwtpkg.Manager
+	auth Authenticator
 
-	public map[string]struct{}
+	policies        map[string]optionspb.AuthPolicy
+	servicePolicies map[string]optionspb.AuthPolicy
+	scopes          map[string]string
 }
 
-func NewAuthInterceptor(jwtManager *jwtpkg.Manager, publicMethods []string) *AuthInterceptor {
-	m := make(map[string]struct{}, len(publicMethods))
-	for _, v := range publicMethods {
-		m[v] = struct{}{}
-	}
+func NewAuthInterceptor(auth Authenticator) *AuthInterceptor {
 	return &AuthInterceptor{
-		jwtManager: jwtManager,
-		public:     m,
+		auth:            auth,
+		policies:        make(map[string]optionspb.AuthPolicy),
+		servicePolicies: make(map[string]optionspb.AuthPolicy),
+		scopes:          make(map[string]string),
+	}
+}
+
+// SetServicePolicy sets the policy for all methods of a service whose proto
+// we don't control, e.g. grpc.reflection.v1.ServerReflection.
+func (a *AuthInterceptor) SetServicePolicy(service string, policy optionspb.AuthPolicy) {
+	a.servicePolicies[service] = policy
+}
+
+// LoadPolicies reads the auth policy and API key scope of every registered
+// method from its proto options. Call it after all services are registered
+// and before Serve.
+func (a *AuthInterceptor) LoadPolicies(services map[string]grpc.ServiceInfo) error {
+	policies, err := methodPolicies(services, a.servicePolicies)
+	if err != nil {
+		return err
 	}
+	a.policies = policies
+	a.scopes = methodScopes(services)
+	return nil
 }
 
 func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
@@ -42,30 +68,74 @@ func (a *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (any, error) {
-		if _, ok := a.public[info.FullMethod]; ok {
-			return handler(ctx, req)
-		}
-
-		token, err := extractBearer(ctx)
+		ctx, err := a.authorize(ctx, info.FullMethod)
 		if err != nil {
 			return nil, err
 		}
+		return handler(ctx, req)
+	}
+}
 
-		claims, err := a.jwtManager.ParseToken(token)
+func (a *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
 		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (a *AuthInterceptor) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	policy, ok := a.policies[fullMethod]
+	if !ok {
+		// unknown methods are never public
+		policy = optionspb.AuthPolicy_AUTH_POLICY_AUTHENTICATED
+	}
+	if policy == optionspb.AuthPolicy_AUTH_POLICY_PUBLIC {
+		return ctx, nil
+	}
+
+	token, err := extractBearer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := a.auth.Authenticate(ctx, token)
+	if err != nil {
+		if errors.Is(err, authSvc.ErrUnauthorized) {
 			return nil, status.Error(codes.Unauthenticated, "invalid token")
 		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
 
-		userID, err := uuid.Parse(claims.UserID)
-		if err != nil {
-			return nil, status.Error(codes.Unauthenticated, "invalid token user_id")
+	if id.Scoped() {
+		scope := a.scopes[fullMethod]
+		if scope == "" || !id.Can(scope) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient scope")
 		}
+	}
 
-		ctx = context.WithValue(ctx, CtxUserIDKey, userID)
-		ctx = context.WithValue(ctx, CtxUsernameKey, claims.Username)
+	ctx = context.WithValue(ctx, CtxUserIDKey, id.UserID)
+	ctx = context.WithValue(ctx, CtxUsernameKey, id.Username)
+	ctx = context.WithValue(ctx, CtxSessionIDKey, id.SessionID)
 
-		return handler(ctx, req)
-	}
+	return ctx, nil
+}
+
+// wrappedStream overrides the context of a server stream.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
 }
 
 func extractBearer(ctx context.Context) (string, error) {
@@ -97,3 +167,9 @@ func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	id, ok := v.(uuid.UUID)
 	return id, ok
 }
+
+func SessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	v := ctx.Value(CtxSessionIDKey)
+	id, ok := v.(uuid.UUID)
+	return id, ok
+}
@@ -0,0 +1,262 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	adminpb "gopress/api/proto/admin"
+	articlepb "gopress/api/proto/article"
+	authpb "gopress/api/proto/auth"
+	mediapb "gopress/api/proto/media"
+	optionspb "gopress/api/proto/options"
+	userpb "gopress/api/proto/user"
+	authSvc "gopress/internal/app/auth"
+)
+
+// stubAuth knows a fixed set of tokens.
+type stubAuth map[string]*authSvc.Identity
+
+func (s stubAuth) Authenticate(_ context.Context, token string) (*authSvc.Identity, error) {
+	if token == "broken" {
+		return nil, errors.New("db down")
+	}
+	id, ok := s[token]
+	if !ok {
+		return nil, authSvc.ErrUnauthorized
+	}
+	return id, nil
+}
+
+var (
+	testUser   = uuid.New()
+	testTokens = stubAuth{
+		"session":      {UserID: testUser, Username: "alice", SessionID: uuid.New()},
+		"key-read":     {UserID: testUser, Username: "alice", APIKeyID: uuid.New(), Scopes: []string{"articles:read"}},
+		"key-write":    {UserID: testUser, Username: "alice", APIKeyID: uuid.New(), Scopes: []string{"articles:write"}},
+		"client-write": {UserID: testUser, Username: "alice", ClientID: uuid.New(), Scopes: []string{"articles:write"}},
+	}
+)
+
+// ourServices registers every service the server has.
+func ourServices() *grpc.Server {
+	srv := grpc.NewServer()
+	authpb.RegisterAuthServiceServer(srv, authpb.UnimplementedAuthServiceServer{})
+	userpb.RegisterUserServiceServer(srv, userpb.UnimplementedUserServiceServer{})
+	articlepb.RegisterArticleServiceServer(srv, articlepb.UnimplementedArticleServiceServer{})
+	adminpb.RegisterAdminServiceServer(srv, adminpb.UnimplementedAdminServiceServer{})
+	mediapb.RegisterMediaServiceServer(srv, mediapb.UnimplementedMediaServiceServer{})
+	return srv
+}
+
+func loadedInterceptor(t *testing.T) *AuthInterceptor {
+	t.Helper()
+	a := NewAuthInterceptor(testTokens)
+	if err := a.LoadPolicies(ourServices().GetServiceInfo()); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestLoadPoliciesCoversEveryMethod(t *testing.T) {
+	a := loadedInterceptor(t)
+
+	services := ourServices().GetServiceInfo()
+	for name, info := range services {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		methods := d.(protoreflect.ServiceDescriptor).Methods()
+		for _, m := range info.Methods {
+			fullMethod := "/" + name + "/" + m.Name
+			opts := methods.ByName(protoreflect.Name(m.Name)).Options()
+
+			want := proto.GetExtension(opts, optionspb.E_AuthPolicy).(optionspb.AuthPolicy)
+			if got := a.policies[fullMethod]; got != want || got == optionspb.AuthPolicy_AUTH_POLICY_UNSPECIFIED {
+				t.Errorf("%s: policy %v, declared %v", fullMethod, got, want)
+			}
+			scope := proto.GetExtension(opts, optionspb.E_ApiKeyScope).(string)
+			if got := a.scopes[fullMethod]; got != scope {
+				t.Errorf("%s: scope %q, declared %q", fullMethod, got, scope)
+			}
+			if scope != "" && want == optionspb.AuthPolicy_AUTH_POLICY_PUBLIC {
+				t.Errorf("%s: public method with a scope", fullMethod)
+			}
+		}
+	}
+	if len(a.policies) == 0 {
+		t.Fatal("no policies loaded")
+	}
+}
+
+func TestLoadPoliciesKnownMethods(t *testing.T) {
+	a := loadedInterceptor(t)
+	tests := []struct {
+		method string
+		policy optionspb.AuthPolicy
+		scope  string
+	}{
+		{"/article.ArticleService/List", optionspb.AuthPolicy_AUTH_POLICY_PUBLIC, ""},
+		{"/article.ArticleService/WatchArticles", optionspb.AuthPolicy_AUTH_POLICY_PUBLIC, ""},
+		{"/article.ArticleService/Create", optionspb.AuthPolicy_AUTH_POLICY_AUTHENTICATED, "articles:write"},
+		{"/article.ArticleService/ListTrash", optionspb.AuthPolicy_AUTH_POLICY_AUTHENTICATED, "articles:read"},
+		{"/auth.AuthService/Login", optionspb.AuthPolicy_AUTH_POLICY_PUBLIC, ""},
+	}
+	for _, tt := range tests {
+		if got := a.policies[tt.method]; got != tt.policy {
+			t.Errorf("%s: policy %v, want %v", tt.method, got, tt.policy)
+		}
+		if got := a.scopes[tt.method]; got != tt.scope {
+			t.Errorf("%s: scope %q, want %q", tt.method, got, tt.scope)
+		}
+	}
+}
+
+func TestLoadPoliciesForeignService(t *testing.T) {
+	srv := ourServices()
+	reflection.Register(srv)
+	service := reflectionv1.ServerReflection_ServiceDesc.ServiceName
+
+	a := NewAuthInterceptor(testTokens)
+	err := a.LoadPolicies(srv.GetServiceInfo())
+	if err == nil || !strings.Contains(err.Error(), service) {
+		t.Fatalf("LoadPolicies without an override = %v, want an error naming %s", err, service)
+	}
+
+	a.SetServicePolicy(service, optionspb.AuthPolicy_AUTH_POLICY_PUBLIC)
+	a.SetServicePolicy("grpc.reflection.v1alpha.ServerReflection", optionspb.AuthPolicy_AUTH_POLICY_PUBLIC)
+	if err := a.LoadPolicies(srv.GetServiceInfo()); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.policies["/"+service+"/ServerReflectionInfo"]; got != optionspb.AuthPolicy_AUTH_POLICY_PUBLIC {
+		t.Errorf("reflection policy = %v", got)
+	}
+}
+
+var authorizeTests = []struct {
+	name   string
+	method string
+	header string
+	code   codes.Code
+}{
+	{"public without token", "/article.ArticleService/List", "", codes.OK},
+	{"public with a bad token", "/article.ArticleService/List", "Bearer nope", codes.OK},
+	{"missing token", "/article.ArticleService/Create", "", codes.Unauthenticated},
+	{"not bearer", "/article.ArticleService/Create", "Basic session", codes.Unauthenticated},
+	{"empty bearer", "/article.ArticleService/Create", "Bearer ", codes.Unauthenticated},
+	{"unknown token", "/article.ArticleService/Create", "Bearer nope", codes.Unauthenticated},
+	{"authenticator fails", "/article.ArticleService/Create", "Bearer broken", codes.Internal},
+	{"session", "/article.ArticleService/Create", "Bearer session", codes.OK},
+	{"bearer in any case", "/article.ArticleService/Create", "bearer session", codes.OK},
+	{"key with scope", "/article.ArticleService/Create", "Bearer key-write", codes.OK},
+	{"write covers read", "/article.ArticleService/ListTrash", "Bearer key-write", codes.OK},
+	{"key with wrong scope", "/article.ArticleService/Create", "Bearer key-read", codes.PermissionDenied},
+	{"client with scope", "/article.ArticleService/Delete", "Bearer client-write", codes.OK},
+	{"key on a session-only method", "/user.UserService/ChangePassword", "Bearer key-write", codes.PermissionDenied},
+	{"session on a session-only method", "/user.UserService/ChangePassword", "Bearer session", codes.OK},
+	{"unknown method is not public", "/article.ArticleService/Nope", "", codes.Unauthenticated},
+	{"unknown method and a key", "/article.ArticleService/Nope", "Bearer key-write", codes.PermissionDenied},
+}
+
+func incoming(header string) context.Context {
+	ctx := context.Background()
+	if header == "" {
+		return metadata.NewIncomingContext(ctx, metadata.MD{})
+	}
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", header))
+}
+
+func TestUnary(t *testing.T) {
+	a := loadedInterceptor(t)
+	unary := a.Unary()
+
+	for _, tt := range authorizeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			_, err := unary(incoming(tt.header), nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, _ any) (any, error) {
+					called = true
+					checkIdentity(t, ctx, tt.header)
+					return nil, nil
+				})
+			if status.Code(err) != tt.code {
+				t.Fatalf("err = %v, want %v", err, tt.code)
+			}
+			if called != (tt.code == codes.OK) {
+				t.Errorf("handler called = %v", called)
+			}
+		})
+	}
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context { return s.ctx }
+
+func TestStream(t *testing.T) {
+	a := loadedInterceptor(t)
+	stream := a.Stream()
+
+	for _, tt := range authorizeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			err := stream(nil, &testStream{ctx: incoming(tt.header)}, &grpc.StreamServerInfo{FullMethod: tt.method, IsServerStream: true},
+				func(_ any, ss grpc.ServerStream) error {
+					called = true
+					checkIdentity(t, ss.Context(), tt.header)
+					return nil
+				})
+			if status.Code(err) != tt.code {
+				t.Fatalf("err = %v, want %v", err, tt.code)
+			}
+			if called != (tt.code == codes.OK) {
+				t.Errorf("handler called = %v", called)
+			}
+		})
+	}
+}
+
+func TestMissingMetadata(t *testing.T) {
+	a := loadedInterceptor(t)
+	_, err := a.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/article.ArticleService/Create"},
+		func(context.Context, any) (any, error) { return nil, nil })
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("err = %v, want Unauthenticated", err)
+	}
+}
+
+// checkIdentity checks what the handler sees: the user of a valid token,
+// nothing on public calls.
+func checkIdentity(t *testing.T, ctx context.Context, header string) {
+	t.Helper()
+	_, token, _ := strings.Cut(header, " ")
+	id, known := testTokens[token]
+
+	userID, ok := UserIDFromContext(ctx)
+	if known {
+		if !ok || userID != id.UserID {
+			t.Errorf("user in context = %v, %v, want %v", userID, ok, id.UserID)
+		}
+		if sessionID, _ := SessionIDFromContext(ctx); sessionID != id.SessionID {
+			t.Errorf("session in context = %v, want %v", sessionID, id.SessionID)
+		}
+	} else if ok {
+		t.Errorf("public call got user %v", userID)
+	}
+}
@@ -0,0 +1,66 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/x509"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+const CtxClientIdentityKey ctxKey = "client_identity"
+
+// ClientIdentity describes a verified mTLS client certificate.
+type ClientIdentity struct {
+	CommonName   string
+	Organization []string
+	DNSNames     []string
+	URIs         []string
+	SerialNumber string
+}
+
+// ClientCertUnary puts the verified client certificate identity (if any)
+// into the context.
+func ClientCertUnary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withClientIdentity(ctx), req)
+	}
+}
+
+func ClientCertStream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: withClientIdentity(ss.Context())})
+	}
+}
+
+func withClientIdentity(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, CtxClientIdentityKey, identityFromCert(info.State.VerifiedChains[0][0]))
+}
+
+func identityFromCert(cert *x509.Certificate) *ClientIdentity {
+	id := &ClientIdentity{
+		CommonName:   cert.Subject.CommonName,
+		Organization: cert.Subject.Organization,
+		DNSNames:     cert.DNSNames,
+		SerialNumber: cert.SerialNumber.String(),
+	}
+	for _, u := range cert.URIs {
+		id.URIs = append(id.URIs, u.String())
+	}
+	return id
+}
+
+func ClientIdentityFromContext(ctx context.Context) (*ClientIdentity, bool) {
+	id, ok := ctx.Value(CtxClientIdentityKey).(*ClientIdentity)
+	return id, ok
+}
@@ -0,0 +1,90 @@
+package interceptor
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	optionspb "gopress/api/proto/options"
+)
+
+// methodPolicies resolves the (options.auth_policy) option of every method of
+// the registered services. Services we don't own (reflection, health) take
+// their policy from overrides. A method without a declared policy is an error.
+func methodPolicies(services map[string]grpc.ServiceInfo, overrides map[string]optionspb.AuthPolicy) (map[string]optionspb.AuthPolicy, error) {
+	res := make(map[string]optionspb.AuthPolicy)
+	var missing []string
+
+	for name, info := range services {
+		if policy, ok := overrides[name]; ok {
+			for _, m := range info.Methods {
+				res["/"+name+"/"+m.Name] = policy
+			}
+			continue
+		}
+
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("service %s: descriptor not found: %w", name, err)
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a service", name)
+		}
+
+		for _, m := range info.Methods {
+			fullMethod := "/" + name + "/" + m.Name
+
+			md := sd.Methods().ByName(protoreflect.Name(m.Name))
+			if md == nil {
+				missing = append(missing, fullMethod)
+				continue
+			}
+
+			policy := proto.GetExtension(md.Options(), optionspb.E_AuthPolicy).(optionspb.AuthPolicy)
+			if policy == optionspb.AuthPolicy_AUTH_POLICY_UNSPECIFIED {
+				missing = append(missing, fullMethod)
+				continue
+			}
+			res[fullMethod] = policy
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no auth policy declared for: %s", strings.Join(missing, ", "))
+	}
+	return res, nil
+}
+
+// methodScopes resolves the (options.api_key_scope) option of every method
+// that declares one. Methods without a scope can't be called with API keys
+// or OAuth client tokens.
+func methodScopes(services map[string]grpc.ServiceInfo) map[string]string {
+	res := make(map[string]string)
+
+	for name, info := range services {
+		d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			continue
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+
+		for _, m := range info.Methods {
+			md := sd.Methods().ByName(protoreflect.Name(m.Name))
+			if md == nil {
+				continue
+			}
+			if scope := proto.GetExtension(md.Options(), optionspb.E_ApiKeyScope).(string); scope != "" {
+				res["/"+name+"/"+m.Name] = scope
+			}
+		}
+	}
+	return res
+}
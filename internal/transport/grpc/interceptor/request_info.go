@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"gopress/internal/app/requestinfo"
+)
+
+// RequestInfoUnary records client IP, user agent and request ID for the app
+// layer. x-forwarded-for is only trusted when trustProxy is set.
+func RequestInfoUnary(trustProxy bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestInfo(ctx, trustProxy), req)
+	}
+}
+
+func RequestInfoStream(trustProxy bool) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: withRequestInfo(ss.Context(), trustProxy)})
+	}
+}
+
+func withRequestInfo(ctx context.Context, trustProxy bool) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	info := requestinfo.Info{
+		UserAgent: first("user-agent"),
+		RequestID: first("x-request-id"),
+	}
+	if info.RequestID == "" || len(info.RequestID) > 128 {
+		info.RequestID = uuid.NewString()
+	}
+
+	if trustProxy {
+		if fwd := first("x-forwarded-for"); fwd != "" {
+			ip, _, _ := strings.Cut(fwd, ",")
+			if parsed := net.ParseIP(strings.TrimSpace(ip)); parsed != nil {
+				info.IP = parsed.String()
+			}
+		}
+	}
+	if info.IP == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			host, _, err := net.SplitHostPort(p.Addr.String())
+			if err != nil {
+				host = p.Addr.String()
+			}
+			info.IP = host
+		}
+	}
+
+	return requestinfo.With(ctx, info)
+}
@@ -1,49 +1,110 @@
 package grpc
 
 import (
+	articleSvc "gopress/internal/app/article"
+	authSvc "gopress/internal/app/auth"
+	mediaSvc "gopress/internal/app/media"
 	"gopress/internal/app/ports"
 	"gopress/internal/transport/grpc/interceptor"
 	"gopress/internal/transport/grpc/services"
 	"net"
+	"time"
 
+	adminpb "gopress/api/proto/admin"
 	articlepb "gopress/api/proto/article"
 	authpb "gopress/api/proto/auth"
-	jwtpkg "gopress/pkg/jwt"
+	mediapb "gopress/api/proto/media"
+	optionspb "gopress/api/proto/options"
+	userpb "gopress/api/proto/user"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 )
 
+// stopTimeout bounds GracefulStop, open streams are cut after it.
+const stopTimeout = 5 * time.Second
+
+type Deps struct {
+	ArticleRepo    ports.ArticleRepo
+	AuthService    *authSvc.Service
+	ArticleService *articleSvc.Service
+	MediaService   *mediaSvc.Service
+}
+
 type Server struct {
 	srv  *grpc.Server
 	lis  net.Listener
 	addr string
 }
 
-func NewServer(userRepo ports.UserRepo, articleRepo ports.ArticleRepo, jwtManager *jwtpkg.Manager, addr string) (*Server, error) {
-	authI := interceptor.NewAuthInterceptor(jwtManager, []string{
-		// публичные auth методы:
-		"/auth.AuthService/Register",
-		"/auth.AuthService/Login",
+func NewServer(cfg Config, deps Deps) (*Server, error) {
+	authI := interceptor.NewAuthInterceptor(deps.AuthService)
 
-		// публичные методы статей:
-		"/article.ArticleService/List",
-		"/article.ArticleService/Get",
-	})
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			interceptor.RequestInfoUnary(cfg.TrustProxyHeaders),
+			interceptor.ClientCertUnary(),
+			authI.Unary(),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.RequestInfoStream(cfg.TrustProxyHeaders),
+			interceptor.ClientCertStream(),
+			authI.Stream(),
+		),
+		grpc.MaxRecvMsgSize(cfg.MaxRecvMsgSize),
+		grpc.MaxSendMsgSize(cfg.MaxSendMsgSize),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			MaxConnectionIdle:     cfg.Keepalive.MaxConnectionIdle,
+			MaxConnectionAge:      cfg.Keepalive.MaxConnectionAge,
+			MaxConnectionAgeGrace: cfg.Keepalive.MaxConnectionAgeGrace,
+			Time:                  cfg.Keepalive.Time,
+			Timeout:               cfg.Keepalive.Timeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.Keepalive.MinTime,
+			PermitWithoutStream: cfg.Keepalive.PermitWithoutStream,
+		}),
+	}
 
-	grpcSrv := grpc.NewServer(
-		grpc.UnaryInterceptor(authI.Unary()),
-	)
+	if cfg.TLS.Enabled() {
+		tlsCfg, err := cfg.TLS.build()
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	grpcSrv := grpc.NewServer(opts...)
 
 	// регистрируем сервисы
-	authpb.RegisterAuthServiceServer(grpcSrv, services.NewAuthServer(userRepo, jwtManager))
-	articlepb.RegisterArticleServiceServer(grpcSrv, services.NewArticleServer(articleRepo))
+	authpb.RegisterAuthServiceServer(grpcSrv, services.NewAuthServer(deps.AuthService))
+	userpb.RegisterUserServiceServer(grpcSrv, services.NewUserServer(deps.AuthService))
+	articlepb.RegisterArticleServiceServer(grpcSrv, services.NewArticleServer(deps.ArticleRepo, deps.ArticleService))
+	adminpb.RegisterAdminServiceServer(grpcSrv, services.NewAdminServer(deps.AuthService))
+	mediapb.RegisterMediaServiceServer(grpcSrv, services.NewMediaServer(deps.MediaService))
 
-	lis, err := net.Listen("tcp", addr)
+	if cfg.Reflection {
+		reflection.Register(grpcSrv)
+		authI.SetServicePolicy(reflectionv1.ServerReflection_ServiceDesc.ServiceName, optionspb.AuthPolicy_AUTH_POLICY_PUBLIC)
+		authI.SetServicePolicy(reflectionv1alpha.ServerReflection_ServiceDesc.ServiceName, optionspb.AuthPolicy_AUTH_POLICY_PUBLIC)
+	}
+
+	// политики доступа объявлены в .proto (options.auth_policy)
+	if err := authI.LoadPolicies(grpcSrv.GetServiceInfo()); err != nil {
+		return nil, err
+	}
+
+	lis, err := net.Listen("tcp", cfg.Addr)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Server{srv: grpcSrv, lis: lis, addr: addr}, nil
+	return &Server{srv: grpcSrv, lis: lis, addr: cfg.Addr}, nil
 }
 
 func (s *Server) Start() error {
@@ -51,5 +112,15 @@ func (s *Server) Start() error {
 }
 
 func (s *Server) Stop() {
-	s.srv.GracefulStop()
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(stopTimeout):
+		s.srv.Stop()
+	}
 }
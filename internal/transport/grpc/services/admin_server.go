@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+	"gopress/internal/transport/grpc/interceptor"
+
+	adminpb "gopress/api/proto/admin"
+)
+
+type AdminServer struct {
+	adminpb.UnimplementedAdminServiceServer
+	service *authSvc.Service
+}
+
+func NewAdminServer(service *authSvc.Service) *AdminServer {
+	return &AdminServer{service: service}
+}
+
+func (s *AdminServer) ListUsers(ctx context.Context, req *adminpb.ListUsersRequest) (*adminpb.ListUsersResponse, error) {
+	actorID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	users, err := s.service.ListUsers(ctx, actorID, user.ListFilter{
+		Query:     req.Query,
+		Role:      req.Role,
+		Suspended: req.Suspended,
+		Limit:     int(req.Limit),
+		Offset:    int(req.Offset),
+	})
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	out := make([]*adminpb.User, 0, len(users))
+	for _, u := range users {
+		out = append(out, mapAdminUser(u))
+	}
+	return &adminpb.ListUsersResponse{Users: out}, nil
+}
+
+func (s *AdminServer) GetUser(ctx context.Context, req *adminpb.GetUserRequest) (*adminpb.GetUserResponse, error) {
+	actorID, id, err := adminIDs(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.service.GetUser(ctx, actorID, id)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	return &adminpb.GetUserResponse{User: mapAdminUser(u)}, nil
+}
+
+func (s *AdminServer) SuspendUser(ctx context.Context, req *adminpb.SuspendUserRequest) (*adminpb.SuspendUserResponse, error) {
+	actorID, id, err := adminIDs(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.service.SuspendUser(ctx, actorID, id, req.Reason)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	return &adminpb.SuspendUserResponse{User: mapAdminUser(u)}, nil
+}
+
+func (s *AdminServer) UnsuspendUser(ctx context.Context, req *adminpb.UnsuspendUserRequest) (*adminpb.UnsuspendUserResponse, error) {
+	actorID, id, err := adminIDs(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.service.UnsuspendUser(ctx, actorID, id)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	return &adminpb.UnsuspendUserResponse{User: mapAdminUser(u)}, nil
+}
+
+func (s *AdminServer) ForcePasswordReset(ctx context.Context, req *adminpb.ForcePasswordResetRequest) (*adminpb.ForcePasswordResetResponse, error) {
+	actorID, id, err := adminIDs(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.ForcePasswordReset(ctx, actorID, id); err != nil {
+		return nil, adminError(err)
+	}
+
+	return &adminpb.ForcePasswordResetResponse{Status: "ok"}, nil
+}
+
+func (s *AdminServer) SetUserRole(ctx context.Context, req *adminpb.SetUserRoleRequest) (*adminpb.SetUserRoleResponse, error) {
+	actorID, id, err := adminIDs(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.service.SetUserRole(ctx, actorID, id, req.Role)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	return &adminpb.SetUserRoleResponse{User: mapAdminUser(u)}, nil
+}
+
+func (s *AdminServer) DeleteUser(ctx context.Context, req *adminpb.DeleteUserRequest) (*adminpb.DeleteUserResponse, error) {
+	actorID, id, err := adminIDs(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.service.DeleteUser(ctx, actorID, id); err != nil {
+		return nil, adminError(err)
+	}
+
+	return &adminpb.DeleteUserResponse{Status: "ok"}, nil
+}
+
+func (s *AdminServer) ListAuditEvents(ctx context.Context, req *adminpb.ListAuditEventsRequest) (*adminpb.ListAuditEventsResponse, error) {
+	actorID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	f := audit.Filter{
+		Action:   req.Action,
+		Target:   req.Target,
+		BeforeID: req.BeforeId,
+		Limit:    int(req.Limit),
+	}
+	if req.ActorId != "" {
+		id, err := uuid.Parse(req.ActorId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid actor_id")
+		}
+		f.ActorID = &id
+	}
+	if req.SinceUnix > 0 {
+		f.Since = time.Unix(req.SinceUnix, 0)
+	}
+	if req.UntilUnix > 0 {
+		f.Until = time.Unix(req.UntilUnix, 0)
+	}
+
+	events, err := s.service.ListAuditEvents(ctx, actorID, f)
+	if err != nil {
+		return nil, adminError(err)
+	}
+
+	out := make([]*adminpb.AuditEvent, 0, len(events))
+	for _, e := range events {
+		out = append(out, mapAuditEvent(e))
+	}
+	return &adminpb.ListAuditEventsResponse{Events: out}, nil
+}
+
+// adminIDs returns the caller and the parsed target user ID.
+func adminIDs(ctx context.Context, rawID string) (uuid.UUID, uuid.UUID, error) {
+	actorID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+	return actorID, id, nil
+}
+
+func adminError(err error) error {
+	switch {
+	case errors.Is(err, authSvc.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "forbidden")
+	case errors.Is(err, authSvc.ErrSelfAction):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, authSvc.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, "invalid role")
+	case errors.Is(err, authSvc.ErrInvalidData):
+		return status.Error(codes.InvalidArgument, "invalid data")
+	case errors.Is(err, authSvc.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func mapAdminUser(u *user.User) *adminpb.User {
+	out := &adminpb.User{
+		Id:            u.ID.String(),
+		Email:         u.Email,
+		Username:      u.Username,
+		DisplayName:   u.DisplayName,
+		Role:          u.Role,
+		EmailVerified: u.EmailVerified(),
+		SuspendReason: u.SuspendReason,
+	}
+	if !u.CreatedAt.IsZero() {
+		out.CreatedAtUnix = u.CreatedAt.Unix()
+	}
+	if u.SuspendedAt != nil {
+		out.SuspendedAtUnix = u.SuspendedAt.Unix()
+	}
+	return out
+}
+
+func mapAuditEvent(e *audit.Event) *adminpb.AuditEvent {
+	out := &adminpb.AuditEvent{
+		Id:            e.ID,
+		Action:        e.Action,
+		Target:        e.Target,
+		Ip:            e.IP,
+		RequestId:     e.RequestID,
+		DetailsJson:   jsonObject(e.Details),
+		BeforeJson:    jsonObject(e.Before),
+		AfterJson:     jsonObject(e.After),
+		CreatedAtUnix: e.CreatedAt.Unix(),
+	}
+	if e.ActorID != nil {
+		out.ActorId = e.ActorID.String()
+	}
+	return out
+}
+
+func jsonObject(m map[string]any) string {
+	if m == nil {
+		return ""
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
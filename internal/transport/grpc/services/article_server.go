@@ -2,8 +2,14 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	articleSvc "gopress/internal/app/article"
 	"gopress/internal/app/ports"
 	"gopress/internal/domain/article"
 	"gopress/internal/transport/grpc/interceptor"
@@ -13,18 +19,27 @@ import (
 
 type ArticleServer struct {
 	articlepb.UnimplementedArticleServiceServer
-	repo ports.ArticleRepo
+	repo    ports.ArticleRepo
+	service *articleSvc.Service
 }
 
-func NewArticleServer(repo ports.ArticleRepo) *ArticleServer {
-	return &ArticleServer{repo: repo}
+func NewArticleServer(repo ports.ArticleRepo, service *articleSvc.Service) *ArticleServer {
+	return &ArticleServer{
+		repo:    repo,
+		service: service,
+	}
 }
 
 func (s *ArticleServer) List(ctx context.Context, req *articlepb.ListArticlesRequest) (*articlepb.ListArticlesResponse, error) {
 	limit := int(req.Limit)
 	offset := int(req.Offset)
 
-	items, err := s.repo.List(ctx, limit, offset)
+	view := articleSvc.ViewFull
+	if req.View == articlepb.ArticleView_ARTICLE_VIEW_SUMMARY {
+		view = articleSvc.ViewSummary
+	}
+
+	items, err := s.service.List(ctx, limit, offset, view)
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to list articles")
 	}
@@ -41,37 +56,70 @@ func (s *ArticleServer) Get(ctx context.Context, req *articlepb.GetArticleReques
 		return nil, status.Error(codes.InvalidArgument, "invalid id")
 	}
 
-	a, err := s.repo.GetByID(ctx, req.Id)
+	a, err := s.service.GetByID(ctx, req.Id)
 	if err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "article not found")
+		}
 		return nil, status.Error(codes.Internal, "failed to get article")
 	}
-	if a == nil {
-		return nil, status.Error(codes.NotFound, "article not found")
-	}
 
 	return &articlepb.GetArticleResponse{Article: mapArticle(a)}, nil
 }
 
-func (s *ArticleServer) Create(ctx context.Context, req *articlepb.CreateArticleRequest) (*articlepb.CreateArticleResponse, error) {
-	userID, ok := interceptor.UserIDFromContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.Unauthenticated, "missing auth")
+func (s *ArticleServer) GetBySlug(ctx context.Context, req *articlepb.GetArticleBySlugRequest) (*articlepb.GetArticleBySlugResponse, error) {
+	if req.Slug == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid slug")
 	}
 
-	title := req.Title
-	content := req.Content
-	if title == "" || content == "" {
-		return nil, status.Error(codes.InvalidArgument, "title and content required")
+	a, err := s.service.GetBySlug(ctx, req.Slug)
+	if err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "article not found")
+		}
+		return nil, status.Error(codes.Internal, "failed to get article")
 	}
 
-	a := &article.Article{
-		AuthorID: userID,
-		Title:    title,
-		Content:  content,
+	return &articlepb.GetArticleBySlugResponse{Article: mapArticle(a), Moved: a.Slug != req.Slug}, nil
+}
+
+func (s *ArticleServer) WatchArticles(req *articlepb.WatchArticlesRequest, stream grpc.ServerStreamingServer[articlepb.ArticleEvent]) error {
+	if req.LastEventId < 0 {
+		return status.Error(codes.InvalidArgument, "invalid last_event_id")
+	}
+
+	ctx := stream.Context()
+	err := s.service.Watch(ctx, req.LastEventId, func(e *article.Event) error {
+		return stream.Send(mapArticleEvent(e))
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, "failed to watch articles")
+	}
+	return nil
+}
+
+func (s *ArticleServer) Create(ctx context.Context, req *articlepb.CreateArticleRequest) (*articlepb.CreateArticleResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
 	}
 
-	if err := s.repo.Create(ctx, a); err != nil {
-		return nil, status.Error(codes.Internal, "failed to create article")
+	a, err := s.service.Create(ctx, userID, req.Title, req.Content, article.Format(req.ContentFormat))
+	if err != nil {
+		switch {
+		case errors.Is(err, articleSvc.ErrInvalidData):
+			return nil, status.Error(codes.InvalidArgument, "title and content required, content_format markdown, html or plain")
+		case errors.Is(err, articleSvc.ErrEmailNotVerified):
+			return nil, status.Error(codes.PermissionDenied, "email not verified")
+		default:
+			return nil, status.Error(codes.Internal, "failed to create article")
+		}
 	}
 
 	return &articlepb.CreateArticleResponse{
@@ -89,20 +137,69 @@ func (s *ArticleServer) Update(ctx context.Context, req *articlepb.UpdateArticle
 	if req.Id <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "invalid id")
 	}
-	if req.Title == "" || req.Content == "" {
-		return nil, status.Error(codes.InvalidArgument, "title and content required")
-	}
 
-	okOwned, err := s.repo.UpdateOwned(ctx, req.Id, userID, req.Title, req.Content)
+	var (
+		a   *article.Article
+		err error
+	)
+	if len(req.GetUpdateMask().GetPaths()) == 0 {
+		if req.Title == "" || req.Content == "" {
+			return nil, status.Error(codes.InvalidArgument, "title and content required")
+		}
+		a, err = s.service.Update(ctx, userID, req.Id, req.Title, req.Content, article.Format(req.ContentFormat), req.ExpectedVersion)
+	} else {
+		p, perr := maskedPatch(req)
+		if perr != nil {
+			return nil, perr
+		}
+		a, err = s.service.Patch(ctx, userID, req.Id, p, req.ExpectedVersion)
+	}
 	if err != nil {
+		if errors.Is(err, articleSvc.ErrInvalidData) {
+			return nil, status.Error(codes.InvalidArgument, "title and content must not be empty, content_format markdown, html or plain")
+		}
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			// либо нет статьи, либо не владелец
+			return nil, status.Error(codes.NotFound, "article not found")
+		}
+		if e := versionError(err); e != nil {
+			return nil, e
+		}
 		return nil, status.Error(codes.Internal, "failed to update article")
 	}
-	if !okOwned {
-		// либо нет статьи, либо не владелец
-		return nil, status.Error(codes.NotFound, "article not found")
+
+	return &articlepb.UpdateArticleResponse{Status: "ok", Version: a.Version}, nil
+}
+
+// maskedPatch takes the fields named in the update mask from req. Every
+// editable field of an article has a request field of the same name.
+func maskedPatch(req *articlepb.UpdateArticleRequest) (article.Patch, error) {
+	var p article.Patch
+	// without the mask, whose JSON form refuses some paths
+	values := proto.CloneOf(req)
+	values.UpdateMask = nil
+	b, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(values)
+	if err != nil {
+		return p, status.Error(codes.Internal, "failed to read update_mask")
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return p, status.Error(codes.Internal, "failed to read update_mask")
 	}
 
-	return &articlepb.UpdateArticleResponse{Status: "ok"}, nil
+	for _, path := range req.UpdateMask.Paths {
+		raw, ok := fields[path]
+		if !ok {
+			return p, status.Errorf(codes.InvalidArgument, "update_mask: %q cannot be updated", path)
+		}
+		if err := p.Set(path, raw); err != nil {
+			if errors.Is(err, article.ErrDuplicateField) {
+				return p, status.Errorf(codes.InvalidArgument, "update_mask: %q named twice", path)
+			}
+			return p, status.Errorf(codes.InvalidArgument, "update_mask: %q cannot be updated", path)
+		}
+	}
+	return p, nil
 }
 
 func (s *ArticleServer) Delete(ctx context.Context, req *articlepb.DeleteArticleRequest) (*articlepb.DeleteArticleResponse, error) {
@@ -115,17 +212,91 @@ func (s *ArticleServer) Delete(ctx context.Context, req *articlepb.DeleteArticle
 		return nil, status.Error(codes.InvalidArgument, "invalid id")
 	}
 
-	okOwned, err := s.repo.DeleteOwned(ctx, req.Id, userID)
-	if err != nil {
+	if err := s.service.Delete(ctx, userID, req.Id, req.ExpectedVersion); err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "article not found")
+		}
+		if e := versionError(err); e != nil {
+			return nil, e
+		}
 		return nil, status.Error(codes.Internal, "failed to delete article")
 	}
-	if !okOwned {
-		return nil, status.Error(codes.NotFound, "article not found")
-	}
 
 	return &articlepb.DeleteArticleResponse{Status: "ok"}, nil
 }
 
+// versionError maps the optimistic locking errors, nil for others.
+func versionError(err error) error {
+	switch {
+	case errors.Is(err, articleSvc.ErrVersionMismatch):
+		return status.Error(codes.FailedPrecondition, "article was modified, reload it")
+	case errors.Is(err, articleSvc.ErrVersionRequired):
+		return status.Error(codes.FailedPrecondition, "expected_version required")
+	case errors.Is(err, articleSvc.ErrInvalidData):
+		return status.Error(codes.InvalidArgument, "invalid expected_version")
+	default:
+		return nil
+	}
+}
+
+func (s *ArticleServer) ListTrash(ctx context.Context, _ *articlepb.ListTrashRequest) (*articlepb.ListTrashResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	items, err := s.service.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list trash")
+	}
+
+	res := &articlepb.ListTrashResponse{Articles: make([]*articlepb.Article, 0, len(items))}
+	for _, a := range items {
+		res.Articles = append(res.Articles, mapArticle(a))
+	}
+	return res, nil
+}
+
+func (s *ArticleServer) Restore(ctx context.Context, req *articlepb.RestoreArticleRequest) (*articlepb.RestoreArticleResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	if err := s.service.Restore(ctx, userID, req.Id); err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "article not found in trash")
+		}
+		return nil, status.Error(codes.Internal, "failed to restore article")
+	}
+
+	return &articlepb.RestoreArticleResponse{Status: "ok"}, nil
+}
+
+func (s *ArticleServer) Purge(ctx context.Context, req *articlepb.PurgeArticleRequest) (*articlepb.PurgeArticleResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	if err := s.service.Purge(ctx, userID, req.Id); err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "article not found in trash")
+		}
+		return nil, status.Error(codes.Internal, "failed to purge article")
+	}
+
+	return &articlepb.PurgeArticleResponse{Status: "ok"}, nil
+}
+
 func mapArticle(a *article.Article) *articlepb.Article {
 	var createdUnix int64
 	var updatedUnix int64
@@ -136,13 +307,43 @@ func mapArticle(a *article.Article) *articlepb.Article {
 		updatedUnix = a.UpdatedAt.Unix()
 	}
 
-	return &articlepb.Article{
+	res := &articlepb.Article{
 		Id:             a.ID,
 		Title:          a.Title,
+		Slug:           a.Slug,
+		Excerpt:        a.Excerpt,
+		WordCount:      int32(a.WordCount),
+		ReadingMinutes: int32(a.ReadingMinutes),
 		Content:        a.Content,
 		AuthorId:       a.AuthorID.String(),
 		AuthorUsername: a.AuthorUsername,
 		CreatedAtUnix:  createdUnix,
 		UpdatedAtUnix:  updatedUnix,
+		Version:        a.Version,
+		ContentFormat:  string(a.ContentFormat),
+		ContentHtml:    a.ContentHTML,
+	}
+	if a.DeletedAt != nil {
+		res.DeletedAtUnix = a.DeletedAt.Unix()
+	}
+	return res
+}
+
+var articleEventTypes = map[article.EventType]articlepb.ArticleEventType{
+	article.EventCreated: articlepb.ArticleEventType_ARTICLE_EVENT_TYPE_CREATED,
+	article.EventUpdated: articlepb.ArticleEventType_ARTICLE_EVENT_TYPE_UPDATED,
+	article.EventDeleted: articlepb.ArticleEventType_ARTICLE_EVENT_TYPE_DELETED,
+}
+
+func mapArticleEvent(e *article.Event) *articlepb.ArticleEvent {
+	res := &articlepb.ArticleEvent{
+		Id:             e.ID,
+		Type:           articleEventTypes[e.Type],
+		ArticleId:      e.ArticleID,
+		OccurredAtUnix: e.OccurredAt.Unix(),
+	}
+	if e.Article != nil {
+		res.Article = mapArticle(e.Article)
 	}
+	return res
 }
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	articlepb "gopress/api/proto/article"
+	"gopress/internal/domain/article"
+)
+
+func TestMaskedPatch(t *testing.T) {
+	req := func(paths ...string) *articlepb.UpdateArticleRequest {
+		return &articlepb.UpdateArticleRequest{
+			Id:              1,
+			Title:           "New title",
+			Content:         "New content",
+			ContentFormat:   "html",
+			ExpectedVersion: 3,
+			UpdateMask:      &fieldmaskpb.FieldMask{Paths: paths},
+		}
+	}
+	title, content, html := "New title", "New content", article.FormatHTML
+
+	tests := []struct {
+		name string
+		req  *articlepb.UpdateArticleRequest
+		want article.Patch
+		code codes.Code
+	}{
+		{"title", req("title"), article.Patch{Title: &title}, codes.OK},
+		{"all", req("title", "content", "content_format"), article.Patch{Title: &title, Content: &content, ContentFormat: &html}, codes.OK},
+		{"empty value", &articlepb.UpdateArticleRequest{UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"content_format"}}}, article.Patch{ContentFormat: new(article.Format)}, codes.OK},
+		{"not editable", req("id"), article.Patch{}, codes.InvalidArgument},
+		{"mask itself", req("update_mask"), article.Patch{}, codes.InvalidArgument},
+		{"unknown", req("slug"), article.Patch{}, codes.InvalidArgument},
+		{"camel case", req("contentFormat"), article.Patch{}, codes.InvalidArgument},
+		{"twice", req("title", "title"), article.Patch{}, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := maskedPatch(tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("maskedPatch err = %v, want %v", err, tt.code)
+			}
+			if err != nil {
+				return
+			}
+			var got, want article.Article
+			p.Apply(&got)
+			tt.want.Apply(&want)
+			if got != want || (p.Title == nil) != (tt.want.Title == nil) ||
+				(p.Content == nil) != (tt.want.Content == nil) || (p.ContentFormat == nil) != (tt.want.ContentFormat == nil) {
+				t.Errorf("patch = %+v, want %+v", p, tt.want)
+			}
+		})
+	}
+}
@@ -2,46 +2,39 @@ package services
 
 import (
 	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"gopress/api/proto/auth"
-	"gopress/internal/app/ports"
-	"gopress/internal/domain/user"
-	jwtpkg "gopress/pkg/jwt"
-	"gopress/pkg/password"
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/transport/grpc/interceptor"
 )
 
 type AuthServer struct {
 	auth.UnimplementedAuthServiceServer
-	userRepo   ports.UserRepo
-	jwtManager *jwtpkg.Manager
+	service *authSvc.Service
 }
 
-func NewAuthServer(userRepo ports.UserRepo, jwtManager *jwtpkg.Manager) *AuthServer {
-	return &AuthServer{
-		userRepo:   userRepo,
-		jwtManager: jwtManager,
-	}
+func NewAuthServer(service *authSvc.Service) *AuthServer {
+	return &AuthServer{service: service}
 }
 
 func (s *AuthServer) Register(ctx context.Context, req *auth.RegisterRequest) (*auth.RegisterResponse, error) {
-	if req.Username == "" || req.Email == "" || req.Password == "" {
-		return nil, status.Error(codes.InvalidArgument, "empty fields")
-	}
-
-	hashed, err := password.Hash(req.Password)
+	u, err := s.service.Register(ctx, req.Username, req.Email, req.Password)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to hash password")
-	}
-
-	u := &user.User{
-		Email:    req.Email,
-		Username: req.Username,
-		Password: hashed,
-	}
-
-	if err := s.userRepo.Create(ctx, u); err != nil {
-		return nil, status.Error(codes.Internal, "failed to create u")
+		switch {
+		case errors.Is(err, authSvc.ErrInvalidData):
+			return nil, status.Error(codes.InvalidArgument, "empty fields")
+		case errors.Is(err, authSvc.ErrWeakPassword):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, authSvc.ErrHashPassword):
+			return nil, status.Error(codes.Internal, "failed to hash password")
+		default:
+			return nil, status.Error(codes.Internal, "failed to create user")
+		}
 	}
 
 	return &auth.RegisterResponse{
@@ -52,24 +45,118 @@ func (s *AuthServer) Register(ctx context.Context, req *auth.RegisterRequest) (*
 }
 
 func (s *AuthServer) Login(ctx context.Context, req *auth.LoginRequest) (*auth.LoginResponse, error) {
-	u, err := s.userRepo.GetByUsername(ctx, req.Username)
+	res, err := s.service.Login(ctx, req.Username, req.Password)
 	if err != nil {
+		if errors.Is(err, authSvc.ErrTooManyAttempts) {
+			return nil, throttledError(err)
+		}
+		if errors.Is(err, authSvc.ErrInvalidData) {
+			return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+		}
+		if errors.Is(err, authSvc.ErrSuspended) {
+			return nil, status.Error(codes.PermissionDenied, "account suspended")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
-	if u == nil {
-		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
-	}
 
-	if !password.Check(u.Password, req.Password) {
-		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+	if res.MFAToken != "" {
+		return &auth.LoginResponse{
+			MfaRequired: true,
+			MfaToken:    res.MFAToken,
+		}, nil
 	}
 
-	token, err := s.jwtManager.GenerateToken(u.ID, u.Username)
+	return &auth.LoginResponse{
+		Token: res.Token,
+	}, nil
+}
+
+func (s *AuthServer) LoginMFA(ctx context.Context, req *auth.LoginMFARequest) (*auth.LoginResponse, error) {
+	token, err := s.service.LoginMFA(ctx, req.MfaToken, req.Code)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "failed to generate token")
+		return nil, mfaError(err)
 	}
 
-	return &auth.LoginResponse{
-		Token: token,
-	}, nil
+	return &auth.LoginResponse{Token: token}, nil
+}
+
+func (s *AuthServer) RequestPasswordReset(ctx context.Context, req *auth.RequestPasswordResetRequest) (*auth.RequestPasswordResetResponse, error) {
+	if err := s.service.RequestPasswordReset(ctx, req.Email); err != nil {
+		if errors.Is(err, authSvc.ErrInvalidData) {
+			return nil, status.Error(codes.InvalidArgument, "email required")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &auth.RequestPasswordResetResponse{Status: "ok"}, nil
+}
+
+func (s *AuthServer) ResetPassword(ctx context.Context, req *auth.ResetPasswordRequest) (*auth.ResetPasswordResponse, error) {
+	if err := s.service.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, authSvc.ErrInvalidData):
+			return nil, status.Error(codes.InvalidArgument, "token and new_password required")
+		case errors.Is(err, authSvc.ErrWeakPassword):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, authSvc.ErrInvalidToken):
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired token")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &auth.ResetPasswordResponse{Status: "ok"}, nil
+}
+
+func (s *AuthServer) VerifyEmail(ctx context.Context, req *auth.VerifyEmailRequest) (*auth.VerifyEmailResponse, error) {
+	if err := s.service.VerifyEmail(ctx, req.Token); err != nil {
+		switch {
+		case errors.Is(err, authSvc.ErrInvalidData):
+			return nil, status.Error(codes.InvalidArgument, "token required")
+		case errors.Is(err, authSvc.ErrInvalidToken):
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired token")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &auth.VerifyEmailResponse{Status: "ok"}, nil
+}
+
+func (s *AuthServer) ResendVerification(ctx context.Context, _ *auth.ResendVerificationRequest) (*auth.ResendVerificationResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	if err := s.service.ResendVerification(ctx, userID); err != nil {
+		switch {
+		case errors.Is(err, authSvc.ErrVerified):
+			return nil, status.Error(codes.FailedPrecondition, "email already verified")
+		case errors.Is(err, authSvc.ErrTooManyEmails):
+			return nil, status.Error(codes.ResourceExhausted, "too many emails, try later")
+		case errors.Is(err, authSvc.ErrUserNotFound):
+			return nil, status.Error(codes.NotFound, "user not found")
+		default:
+			return nil, status.Error(codes.Internal, "internal error")
+		}
+	}
+
+	return &auth.ResendVerificationResponse{Status: "ok"}, nil
+}
+
+// throttledError maps a login backoff to RESOURCE_EXHAUSTED with RetryInfo
+// so clients know when to try again.
+func throttledError(err error) error {
+	st := status.New(codes.ResourceExhausted, "too many attempts")
+
+	var te *authSvc.ThrottleError
+	if errors.As(err, &te) {
+		if withInfo, derr := st.WithDetails(&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(te.RetryAfter),
+		}); derr == nil {
+			st = withInfo
+		}
+	}
+	return st.Err()
 }
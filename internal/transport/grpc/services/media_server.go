@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	mediaSvc "gopress/internal/app/media"
+	"gopress/internal/domain/media"
+	"gopress/internal/transport/grpc/interceptor"
+
+	mediapb "gopress/api/proto/media"
+)
+
+type MediaServer struct {
+	mediapb.UnimplementedMediaServiceServer
+	service *mediaSvc.Service
+}
+
+func NewMediaServer(service *mediaSvc.Service) *MediaServer {
+	return &MediaServer{service: service}
+}
+
+func (s *MediaServer) UploadMedia(stream grpc.ClientStreamingServer[mediapb.UploadMediaRequest, mediapb.UploadMediaResponse]) error {
+	ctx := stream.Context()
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return status.Error(codes.InvalidArgument, "info expected")
+		}
+		return err
+	}
+	info := first.GetInfo()
+	if info == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry info")
+	}
+
+	m, err := s.service.Upload(ctx, userID, info.Filename, &chunkReader{stream: stream})
+	if err != nil {
+		switch {
+		case errors.Is(err, mediaSvc.ErrTooLarge):
+			return status.Error(codes.InvalidArgument, "file too large")
+		case errors.Is(err, mediaSvc.ErrInvalidData):
+			return status.Error(codes.InvalidArgument, "empty file")
+		case errors.Is(err, mediaSvc.ErrUnsupportedType):
+			return status.Error(codes.InvalidArgument, "unsupported file type")
+		case errors.Is(err, mediaSvc.ErrQuotaExceeded):
+			return status.Error(codes.ResourceExhausted, "storage quota exceeded")
+		}
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, "failed to upload media")
+	}
+	return stream.SendAndClose(&mediapb.UploadMediaResponse{Media: mapMedia(m)})
+}
+
+// chunkReader reads the chunks of an upload stream until the client
+// closes it.
+type chunkReader struct {
+	stream grpc.ClientStreamingServer[mediapb.UploadMediaRequest, mediapb.UploadMediaResponse]
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		msg, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := msg.Data.(*mediapb.UploadMediaRequest_Chunk); !ok {
+			return 0, status.Error(codes.InvalidArgument, "only chunks may follow info")
+		}
+		r.buf = msg.GetChunk()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func mapMedia(m *media.Media) *mediapb.Media {
+	return &mediapb.Media{
+		Id:            m.ID.String(),
+		Url:           "/media/" + m.ID.String(),
+		Filename:      m.Filename,
+		ContentType:   m.ContentType,
+		Size:          m.Size,
+		Sha256:        m.SHA256,
+		CreatedAtUnix: m.CreatedAt.Unix(),
+	}
+}
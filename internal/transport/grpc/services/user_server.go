@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/domain/user"
+	"gopress/internal/transport/grpc/interceptor"
+
+	userpb "gopress/api/proto/user"
+)
+
+type UserServer struct {
+	userpb.UnimplementedUserServiceServer
+	service *authSvc.Service
+}
+
+func NewUserServer(service *authSvc.Service) *UserServer {
+	return &UserServer{service: service}
+}
+
+func (s *UserServer) GetMe(ctx context.Context, _ *userpb.GetMeRequest) (*userpb.GetMeResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	u, err := s.service.GetMe(ctx, userID)
+	if err != nil {
+		return nil, userError(err)
+	}
+
+	return &userpb.GetMeResponse{User: mapUser(u)}, nil
+}
+
+func (s *UserServer) UpdateProfile(ctx context.Context, req *userpb.UpdateProfileRequest) (*userpb.UpdateProfileResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	u, err := s.service.UpdateProfile(ctx, userID, user.Profile{
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarUrl,
+	})
+	if err != nil {
+		return nil, userError(err)
+	}
+
+	return &userpb.UpdateProfileResponse{User: mapUser(u)}, nil
+}
+
+func (s *UserServer) ChangePassword(ctx context.Context, req *userpb.ChangePasswordRequest) (*userpb.ChangePasswordResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	sessionID, _ := interceptor.SessionIDFromContext(ctx)
+	token, err := s.service.ChangePassword(ctx, userID, sessionID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		return nil, userError(err)
+	}
+
+	return &userpb.ChangePasswordResponse{Token: token}, nil
+}
+
+func (s *UserServer) DeleteAccount(ctx context.Context, req *userpb.DeleteAccountRequest) (*userpb.DeleteAccountResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	if err := s.service.DeleteAccount(ctx, userID, req.Password); err != nil {
+		return nil, userError(err)
+	}
+
+	return &userpb.DeleteAccountResponse{Status: "ok"}, nil
+}
+
+func (s *UserServer) StartTOTP(ctx context.Context, _ *userpb.StartTOTPRequest) (*userpb.StartTOTPResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	e, err := s.service.StartTOTP(ctx, userID)
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &userpb.StartTOTPResponse{Secret: e.Secret, Uri: e.URI}, nil
+}
+
+func (s *UserServer) ConfirmTOTP(ctx context.Context, req *userpb.ConfirmTOTPRequest) (*userpb.ConfirmTOTPResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	recovery, err := s.service.ConfirmTOTP(ctx, userID, req.Code)
+	if err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &userpb.ConfirmTOTPResponse{RecoveryCodes: recovery}, nil
+}
+
+func (s *UserServer) DisableTOTP(ctx context.Context, req *userpb.DisableTOTPRequest) (*userpb.DisableTOTPResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	if err := s.service.DisableTOTP(ctx, userID, req.Code); err != nil {
+		return nil, mfaError(err)
+	}
+
+	return &userpb.DisableTOTPResponse{Status: "ok"}, nil
+}
+
+func mfaError(err error) error {
+	switch {
+	case errors.Is(err, authSvc.ErrTooManyAttempts):
+		return throttledError(err)
+	case errors.Is(err, authSvc.ErrInvalidData):
+		return status.Error(codes.InvalidArgument, "invalid data")
+	case errors.Is(err, authSvc.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, "invalid or expired mfa token")
+	case errors.Is(err, authSvc.ErrInvalidCode):
+		return status.Error(codes.Unauthenticated, "invalid code")
+	case errors.Is(err, authSvc.ErrMFAEnabled):
+		return status.Error(codes.FailedPrecondition, "two-factor authentication already enabled")
+	case errors.Is(err, authSvc.ErrMFANotEnabled):
+		return status.Error(codes.FailedPrecondition, "two-factor authentication not enabled")
+	case errors.Is(err, authSvc.ErrUserNotFound):
+		return status.Error(codes.Unauthenticated, "user not found")
+	case errors.Is(err, authSvc.ErrSuspended):
+		return status.Error(codes.PermissionDenied, "account suspended")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func userError(err error) error {
+	switch {
+	case errors.Is(err, authSvc.ErrInvalidData):
+		return status.Error(codes.InvalidArgument, "invalid data")
+	case errors.Is(err, authSvc.ErrWeakPassword):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, authSvc.ErrWrongPassword):
+		return status.Error(codes.PermissionDenied, "wrong password")
+	case errors.Is(err, authSvc.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, authSvc.ErrSessionNotFound):
+		return status.Error(codes.NotFound, "session not found")
+	case errors.Is(err, authSvc.ErrAPIKeyNotFound):
+		return status.Error(codes.NotFound, "api key not found")
+	case errors.Is(err, authSvc.ErrTooManyAPIKeys):
+		return status.Error(codes.FailedPrecondition, "too many api keys")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func mapUser(u *user.User) *userpb.User {
+	var createdUnix int64
+	var updatedUnix int64
+	if !u.CreatedAt.IsZero() {
+		createdUnix = u.CreatedAt.Unix()
+	}
+	if !u.UpdatedAt.IsZero() {
+		updatedUnix = u.UpdatedAt.Unix()
+	}
+
+	return &userpb.User{
+		Id:            u.ID.String(),
+		Email:         u.Email,
+		Username:      u.Username,
+		DisplayName:   u.DisplayName,
+		Bio:           u.Bio,
+		AvatarUrl:     u.AvatarURL,
+		CreatedAtUnix: createdUnix,
+		UpdatedAtUnix: updatedUnix,
+		EmailVerified: u.EmailVerified(),
+		Role:          u.Role,
+	}
+}
+
+func (s *UserServer) ListSessions(ctx context.Context, _ *userpb.ListSessionsRequest) (*userpb.ListSessionsResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+	current, _ := interceptor.SessionIDFromContext(ctx)
+
+	sessions, err := s.service.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, userError(err)
+	}
+
+	resp := &userpb.ListSessionsResponse{Sessions: make([]*userpb.Session, 0, len(sessions))}
+	for _, sess := range sessions {
+		resp.Sessions = append(resp.Sessions, &userpb.Session{
+			Id:             sess.ID.String(),
+			UserAgent:      sess.UserAgent,
+			Ip:             sess.IP,
+			CreatedAtUnix:  sess.CreatedAt.Unix(),
+			LastUsedAtUnix: sess.LastUsedAt.Unix(),
+			ExpiresAtUnix:  sess.ExpiresAt.Unix(),
+			Current:        sess.ID == current,
+		})
+	}
+	return resp, nil
+}
+
+func (s *UserServer) RevokeSession(ctx context.Context, req *userpb.RevokeSessionRequest) (*userpb.RevokeSessionResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	if err := s.service.RevokeSession(ctx, userID, id); err != nil {
+		return nil, userError(err)
+	}
+	return &userpb.RevokeSessionResponse{Status: "ok"}, nil
+}
+
+func (s *UserServer) RevokeAllSessions(ctx context.Context, _ *userpb.RevokeAllSessionsRequest) (*userpb.RevokeAllSessionsResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	if err := s.service.RevokeAllSessions(ctx, userID, uuid.Nil); err != nil {
+		return nil, userError(err)
+	}
+	return &userpb.RevokeAllSessionsResponse{Status: "ok"}, nil
+}
+
+func (s *UserServer) Logout(ctx context.Context, _ *userpb.LogoutRequest) (*userpb.LogoutResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	sessionID, ok2 := interceptor.SessionIDFromContext(ctx)
+	if !ok || !ok2 {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	if err := s.service.RevokeSession(ctx, userID, sessionID); err != nil && !errors.Is(err, authSvc.ErrSessionNotFound) {
+		return nil, userError(err)
+	}
+	return &userpb.LogoutResponse{Status: "ok"}, nil
+}
+
+func (s *UserServer) CreateAPIKey(ctx context.Context, req *userpb.CreateAPIKeyRequest) (*userpb.CreateAPIKeyResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAtUnix > 0 {
+		t := time.Unix(req.ExpiresAtUnix, 0)
+		expiresAt = &t
+	}
+
+	created, err := s.service.CreateAPIKey(ctx, userID, req.Name, req.Scopes, expiresAt)
+	if err != nil {
+		return nil, userError(err)
+	}
+
+	return &userpb.CreateAPIKeyResponse{ApiKey: mapAPIKey(created.APIKey), Key: created.Key}, nil
+}
+
+func (s *UserServer) ListAPIKeys(ctx context.Context, _ *userpb.ListAPIKeysRequest) (*userpb.ListAPIKeysResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	keys, err := s.service.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, userError(err)
+	}
+
+	resp := &userpb.ListAPIKeysResponse{ApiKeys: make([]*userpb.APIKey, 0, len(keys))}
+	for i := range keys {
+		resp.ApiKeys = append(resp.ApiKeys, mapAPIKey(&keys[i]))
+	}
+	return resp, nil
+}
+
+func (s *UserServer) RevokeAPIKey(ctx context.Context, req *userpb.RevokeAPIKeyRequest) (*userpb.RevokeAPIKeyResponse, error) {
+	userID, ok := interceptor.UserIDFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing auth")
+	}
+
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	if err := s.service.RevokeAPIKey(ctx, userID, id); err != nil {
+		return nil, userError(err)
+	}
+	return &userpb.RevokeAPIKeyResponse{Status: "ok"}, nil
+}
+
+func mapAPIKey(k *user.APIKey) *userpb.APIKey {
+	res := &userpb.APIKey{
+		Id:            k.ID.String(),
+		Name:          k.Name,
+		Prefix:        k.Prefix,
+		Scopes:        k.Scopes,
+		CreatedAtUnix: k.CreatedAt.Unix(),
+	}
+	if k.ExpiresAt != nil {
+		res.ExpiresAtUnix = k.ExpiresAt.Unix()
+	}
+	if k.LastUsedAt != nil {
+		res.LastUsedAtUnix = k.LastUsedAt.Unix()
+	}
+	return res
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/domain/audit"
+	"gopress/internal/domain/user"
+	"gopress/internal/transport/http/middleware"
+	"gopress/pkg/httpx"
+)
+
+type adminUserResponse struct {
+	getMeResponse
+	SuspendedAt   *time.Time `json:"suspended_at"`
+	SuspendReason string     `json:"suspend_reason"`
+}
+
+type suspendRequest struct {
+	Reason string `json:"reason"`
+}
+
+type setRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// AdminUsers: GET /admin/users?q=&role=&suspended=&limit=&offset=.
+func (h *AuthHandler) AdminUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	actorID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	f := user.ListFilter{
+		Query:  q.Get("q"),
+		Role:   q.Get("role"),
+		Limit:  httpx.QueryInt(q, "limit", 0),
+		Offset: httpx.QueryInt(q, "offset", 0),
+	}
+	if v := q.Get("suspended"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "invalid suspended", http.StatusBadRequest)
+			return
+		}
+		f.Suspended = &b
+	}
+
+	users, err := h.service.ListUsers(ctx, actorID, f)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	resp := make([]adminUserResponse, 0, len(users))
+	for _, u := range users {
+		resp = append(resp, mapAdminUser(u))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// AdminUserByID serves /admin/users/{id} (GET, DELETE) and its actions:
+// POST .../suspend, POST .../unsuspend, POST .../password-reset, PUT .../role.
+func (h *AuthHandler) AdminUserByID(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/admin/users/"
+	rest := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), prefix)
+	rawID, action, _ := strings.Cut(rest, "/")
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	actorID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var (
+		u    *user.User
+		want string
+	)
+	switch action {
+	case "":
+		switch r.Method {
+		case http.MethodGet:
+			u, err = h.service.GetUser(ctx, actorID, id)
+		case http.MethodDelete:
+			err = h.service.DeleteUser(ctx, actorID, id)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	case "suspend":
+		want = http.MethodPost
+		if r.Method == want {
+			var req suspendRequest
+			// the reason is optional, an empty body is fine
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && r.ContentLength != 0 {
+				http.Error(w, "invalid json", http.StatusBadRequest)
+				return
+			}
+			u, err = h.service.SuspendUser(ctx, actorID, id, req.Reason)
+		}
+	case "unsuspend":
+		want = http.MethodPost
+		if r.Method == want {
+			u, err = h.service.UnsuspendUser(ctx, actorID, id)
+		}
+	case "password-reset":
+		want = http.MethodPost
+		if r.Method == want {
+			err = h.service.ForcePasswordReset(ctx, actorID, id)
+		}
+	case "role":
+		want = http.MethodPut
+		if r.Method == want {
+			var req setRoleRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid json", http.StatusBadRequest)
+				return
+			}
+			u, err = h.service.SetUserRole(ctx, actorID, id, req.Role)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if want != "" && r.Method != want {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if u == nil {
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(mapAdminUser(u))
+}
+
+type auditEventResponse struct {
+	ID        int64          `json:"id"`
+	Action    string         `json:"action"`
+	ActorID   *uuid.UUID     `json:"actor_id"`
+	Target    string         `json:"target"`
+	IP        string         `json:"ip"`
+	RequestID string         `json:"request_id"`
+	Details   map[string]any `json:"details"`
+	Before    map[string]any `json:"before"`
+	After     map[string]any `json:"after"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// AuditLog: GET /admin/audit?actor=&action=&target=&since=&until=&before_id=&limit=,
+// since and until in RFC 3339.
+func (h *AuthHandler) AuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	actorID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	f := audit.Filter{
+		Action:   q.Get("action"),
+		Target:   q.Get("target"),
+		BeforeID: int64(httpx.QueryInt(q, "before_id", 0)),
+		Limit:    httpx.QueryInt(q, "limit", 0),
+	}
+	if v := q.Get("actor"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, "invalid actor", http.StatusBadRequest)
+			return
+		}
+		f.ActorID = &id
+	}
+	for name, t := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		if v := q.Get(name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				http.Error(w, "invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*t = parsed
+		}
+	}
+
+	events, err := h.service.ListAuditEvents(ctx, actorID, f)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	resp := make([]auditEventResponse, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, auditEventResponse{
+			ID:        e.ID,
+			Action:    e.Action,
+			ActorID:   e.ActorID,
+			Target:    e.Target,
+			IP:        e.IP,
+			RequestID: e.RequestID,
+			Details:   e.Details,
+			Before:    e.Before,
+			After:     e.After,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authSvc.ErrForbidden):
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case errors.Is(err, authSvc.ErrSelfAction):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, authSvc.ErrInvalidRole):
+		http.Error(w, "invalid role", http.StatusBadRequest)
+	case errors.Is(err, authSvc.ErrInvalidData):
+		http.Error(w, "invalid data", http.StatusBadRequest)
+	case errors.Is(err, authSvc.ErrUserNotFound):
+		http.Error(w, "user not found", http.StatusNotFound)
+	default:
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func mapAdminUser(u *user.User) adminUserResponse {
+	return adminUserResponse{
+		getMeResponse: mapMe(u),
+		SuspendedAt:   u.SuspendedAt,
+		SuspendReason: u.SuspendReason,
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/domain/user"
+	"gopress/internal/transport/http/middleware"
+)
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type apiKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type createAPIKeyResponse struct {
+	apiKeyResponse
+	// shown once
+	Key string `json:"key"`
+}
+
+// APIKeys: GET lists the caller's API keys, POST creates one.
+func (h *AuthHandler) APIKeys(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listAPIKeys(w, r)
+	case http.MethodPost:
+		h.createAPIKey(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// APIKeyByID: DELETE /me/api-keys/{id} revokes a key.
+func (h *AuthHandler) APIKeyByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	const prefix = "/me/api-keys/"
+	id, err := uuid.Parse(strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), prefix))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(ctx, userID, id); err != nil {
+		if errors.Is(err, authSvc.ErrAPIKeyNotFound) {
+			http.Error(w, "api key not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	keys, err := h.service.ListAPIKeys(ctx, userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]apiKeyResponse, 0, len(keys))
+	for i := range keys {
+		resp = append(resp, mapAPIKey(&keys[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AuthHandler) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.CreateAPIKey(ctx, userID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, authSvc.ErrInvalidData):
+			http.Error(w, "name, valid scopes and a future expires_at required", http.StatusBadRequest)
+		case errors.Is(err, authSvc.ErrTooManyAPIKeys):
+			http.Error(w, "too many api keys", http.StatusConflict)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(createAPIKeyResponse{
+		apiKeyResponse: mapAPIKey(created.APIKey),
+		Key:            created.Key,
+	})
+}
+
+func mapAPIKey(k *user.APIKey) apiKeyResponse {
+	return apiKeyResponse{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
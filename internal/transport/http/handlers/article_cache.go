@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopress/internal/app/ports"
+	"gopress/internal/transport/http/httpcache"
+	"gopress/internal/transport/http/middleware"
+)
+
+const cacheRetryDelay = 5 * time.Second
+
+// CacheConfig controls HTTP caching of article reads.
+type CacheConfig struct {
+	// Cache keeps rendered articles, nil reads every one from the database.
+	Cache httpcache.Cache
+	// MaxAge is how long clients and shared caches may reuse an anonymous
+	// read without asking again.
+	MaxAge time.Duration
+}
+
+func articleCacheKey(id int64) string {
+	return "article:" + strconv.FormatInt(id, 10)
+}
+
+// cachedArticle returns GET /articles/{id} with its validators, from the
+// cache if possible.
+func (h *ArticleHandler) cachedArticle(ctx context.Context, id int64) (*httpcache.Response, error) {
+	key := articleCacheKey(id)
+	if h.cache.Cache != nil {
+		if resp, ok := h.cache.Cache.Get(key); ok {
+			return resp, nil
+		}
+	}
+
+	a, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &httpcache.Response{
+		Header: http.Header{
+			"Content-Type":  {"application/json"},
+			"Etag":          {representationETag(a, body)},
+			"Last-Modified": {a.UpdatedAt.UTC().Format(http.TimeFormat)},
+		},
+		Body: append(body, '\n'),
+	}
+	if h.cache.Cache != nil {
+		h.cache.Cache.Set(key, resp)
+	}
+	return resp, nil
+}
+
+// setCacheControl lets anyone cache anonymous reads for MaxAge. Answers to
+// authenticated requests stay private and are revalidated each time.
+func (h *ArticleHandler) setCacheControl(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Vary", "Authorization, Cookie")
+	if _, ok := middleware.UserIDFromContext(r.Context()); ok {
+		w.Header().Set("Cache-Control", "private, no-cache")
+		return
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.cache.MaxAge/time.Second)))
+}
+
+// invalidate drops the article at once after a change made here; changes
+// from elsewhere arrive through RunCacheInvalidation.
+func (h *ArticleHandler) invalidate(id int64) {
+	if h.cache.Cache != nil {
+		h.cache.Cache.Delete(articleCacheKey(id))
+	}
+}
+
+// RunCacheInvalidation drops the articles changed on any instance, or
+// through gRPC, from the cache until ctx is done. The events come straight
+// from the bus, nothing is loaded or rendered for them. By-slug reads are
+// cached under the ID their slug resolves to, so the ID is the only key.
+func (h *ArticleHandler) RunCacheInvalidation(ctx context.Context, bus ports.ArticleEventBus) {
+	if h.cache.Cache == nil {
+		return
+	}
+	for {
+		events, err := bus.Subscribe(ctx)
+		if err != nil {
+			log.Printf("article cache: subscribe: %v", err)
+		} else {
+			for ev := range events {
+				h.invalidate(ev.ArticleID)
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		// events may have been missed
+		h.cache.Cache.Purge()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cacheRetryDelay):
+		}
+	}
+}
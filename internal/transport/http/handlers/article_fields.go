@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"gopress/internal/domain/article"
+)
+
+// memberName matches article members in requests ignoring case and
+// underscores: "ContentHTML", "content_html" and "contenthtml" are one.
+func memberName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "")
+}
+
+// articleMembers are the member names of an article response.
+var articleMembers = sync.OnceValue(func() map[string]bool {
+	b, _ := json.Marshal(article.Article{})
+	var m map[string]json.RawMessage
+	_ = json.Unmarshal(b, &m)
+
+	names := make(map[string]bool, len(m))
+	for k := range m {
+		names[memberName(k)] = true
+	}
+	return names
+})
+
+// summaryOmits are left out of the summary view.
+var summaryOmits = map[string]bool{"content": true, "contenthtml": true}
+
+// parseFields reads the fields query parameter, a comma separated list of
+// article members. It returns nil for none.
+func parseFields(list string) (map[string]bool, error) {
+	if list == "" {
+		return nil, nil
+	}
+	fields := make(map[string]bool)
+	for _, f := range strings.Split(list, ",") {
+		name := memberName(strings.TrimSpace(f))
+		if !articleMembers()[name] {
+			return nil, errors.New("unknown field: " + f)
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// selectMembers encodes the articles with only the members keep accepts.
+func selectMembers(articles []*article.Article, keep func(name string) bool) ([]map[string]json.RawMessage, error) {
+	res := make([]map[string]json.RawMessage, 0, len(articles))
+	for _, a := range articles {
+		b, err := json.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]json.RawMessage
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+		for k := range m {
+			if !keep(memberName(k)) {
+				delete(m, k)
+			}
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
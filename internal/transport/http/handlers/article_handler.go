@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -15,22 +16,24 @@ import (
 
 type ArticleHandler struct {
 	service *articleSvc.Service
+	cache   CacheConfig
 }
 
-func NewArticleHandler(service *articleSvc.Service) *ArticleHandler {
-	return &ArticleHandler{service: service}
+func NewArticleHandler(service *articleSvc.Service, cache CacheConfig) *ArticleHandler {
+	return &ArticleHandler{service: service, cache: cache}
 }
 
 type newArticleRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title         string         `json:"title"`
+	Content       string         `json:"content"`
+	ContentFormat article.Format `json:"content_format"`
 }
 
 func (h *ArticleHandler) Articles(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
 		h.create(w, r)
-	case http.MethodGet:
+	case http.MethodGet, http.MethodHead:
 		h.list(w, r)
 	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
@@ -52,14 +55,21 @@ func (h *ArticleHandler) create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.service.Create(ctx, userID, req.Title, req.Content)
+	a, err := h.service.Create(ctx, userID, req.Title, req.Content, req.ContentFormat)
 	if err != nil {
-		http.Error(w, "internal error", http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, articleSvc.ErrInvalidData):
+			http.Error(w, "title and content required, content_format markdown, html or plain", http.StatusBadRequest)
+		case errors.Is(err, articleSvc.ErrEmailNotVerified):
+			http.Error(w, "email not verified", http.StatusForbidden)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "ok", "id": a.ID})
 }
 
 func (h *ArticleHandler) list(w http.ResponseWriter, r *http.Request) {
@@ -69,7 +79,31 @@ func (h *ArticleHandler) list(w http.ResponseWriter, r *http.Request) {
 	limit := httpx.QueryInt(q, "limit", 20)
 	offset := httpx.QueryInt(q, "offset", 0)
 
-	articles, err := h.service.List(ctx, limit, offset)
+	// fields picks the members, view=summary all but the content
+	fields, err := parseFields(q.Get("fields"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	summary := false
+	switch q.Get("view") {
+	case "", "full":
+	case "summary":
+		summary = true
+	default:
+		http.Error(w, "view must be full or summary", http.StatusBadRequest)
+		return
+	}
+	keep := func(name string) bool { return !summary || !summaryOmits[name] }
+	if fields != nil {
+		keep = func(name string) bool { return fields[name] }
+	}
+
+	view := articleSvc.ViewFull
+	if !keep("content") && !keep("contenthtml") {
+		view = articleSvc.ViewSummary
+	}
+	articles, err := h.service.List(ctx, limit, offset, view)
 	if err != nil {
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
@@ -79,8 +113,17 @@ func (h *ArticleHandler) list(w http.ResponseWriter, r *http.Request) {
 		articles = make([]*article.Article, 0)
 	}
 
+	var body any = articles
+	if fields != nil || summary {
+		if body, err = selectMembers(articles, keep); err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	h.setCacheControl(w, r)
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(articles)
+	_ = json.NewEncoder(w).Encode(body)
 }
 
 func (h *ArticleHandler) ArticlesByID(w http.ResponseWriter, r *http.Request) {
@@ -94,10 +137,12 @@ func (h *ArticleHandler) ArticlesByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	switch r.Method {
-	case http.MethodGet:
+	case http.MethodGet, http.MethodHead:
 		h.get(w, r, id)
 	case http.MethodPut:
 		h.update(w, r, id)
+	case http.MethodPatch:
+		h.patch(w, r, id)
 	case http.MethodDelete:
 		h.delete(w, r, id)
 	default:
@@ -106,9 +151,7 @@ func (h *ArticleHandler) ArticlesByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *ArticleHandler) get(w http.ResponseWriter, r *http.Request, id int64) {
-	ctx := r.Context()
-
-	a, err := h.service.GetByID(ctx, id)
+	resp, err := h.cachedArticle(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, articleSvc.ErrNotFound) {
 			http.Error(w, "not found", http.StatusNotFound)
@@ -118,8 +161,17 @@ func (h *ArticleHandler) get(w http.ResponseWriter, r *http.Request, id int64) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(a)
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	h.setCacheControl(w, r)
+
+	if notModified(r, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")) {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	_, _ = w.Write(resp.Body)
 }
 
 func (h *ArticleHandler) update(w http.ResponseWriter, r *http.Request, id int64) {
@@ -137,14 +189,27 @@ func (h *ArticleHandler) update(w http.ResponseWriter, r *http.Request, id int64
 		return
 	}
 
-	err := h.service.Update(ctx, userID, id, req.Title, req.Content)
+	version, ok := h.expectedVersion(w, r, id)
+	if !ok {
+		return
+	}
+
+	a, err := h.service.Update(ctx, userID, id, req.Title, req.Content, req.ContentFormat, version)
 	if err != nil {
-		http.Error(w, "not found or forbidden", http.StatusNotFound)
+		switch {
+		case writeVersionError(w, err):
+		case errors.Is(err, articleSvc.ErrInvalidData):
+			http.Error(w, "title and content required, content_format markdown, html or plain", http.StatusBadRequest)
+		default:
+			http.Error(w, "not found or forbidden", http.StatusNotFound)
+		}
 		return
 	}
+	h.invalidate(id)
 
+	w.Header().Set("ETag", articleETag(a))
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "ok", "version": a.Version})
 }
 
 func (h *ArticleHandler) delete(w http.ResponseWriter, r *http.Request, id int64) {
@@ -156,12 +221,65 @@ func (h *ArticleHandler) delete(w http.ResponseWriter, r *http.Request, id int64
 		return
 	}
 
-	err := h.service.Delete(ctx, userID, id)
-	if err != nil {
-		http.Error(w, "not found or forbidden", http.StatusNotFound)
+	version, ok := h.expectedVersion(w, r, id)
+	if !ok {
 		return
 	}
 
+	if err := h.service.Delete(ctx, userID, id, version); err != nil {
+		if !writeVersionError(w, err) {
+			http.Error(w, "not found or forbidden", http.StatusNotFound)
+		}
+		return
+	}
+	h.invalidate(id)
+
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
+
+// expectedVersion turns If-Match into the version the change applies to,
+// 0 for any. With several tags the current version is used if listed. It
+// answers 412 itself and returns false when nothing can match.
+func (h *ArticleHandler) expectedVersion(w http.ResponseWriter, r *http.Request, id int64) (int64, bool) {
+	versions, ok := ifMatchVersions(r)
+	if !ok {
+		http.Error(w, "precondition failed", http.StatusPreconditionFailed)
+		return 0, false
+	}
+	switch len(versions) {
+	case 0:
+		return 0, true
+	case 1:
+		return versions[0], true
+	}
+
+	a, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			http.Error(w, "not found or forbidden", http.StatusNotFound)
+			return 0, false
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return 0, false
+	}
+	if !slices.Contains(versions, a.Version) {
+		http.Error(w, "precondition failed", http.StatusPreconditionFailed)
+		return 0, false
+	}
+	return a.Version, true
+}
+
+// writeVersionError answers the optimistic locking errors and reports
+// whether err was one.
+func writeVersionError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, articleSvc.ErrVersionMismatch):
+		http.Error(w, "precondition failed", http.StatusPreconditionFailed)
+	case errors.Is(err, articleSvc.ErrVersionRequired):
+		http.Error(w, "If-Match required", http.StatusPreconditionRequired)
+	default:
+		return false
+	}
+	return true
+}
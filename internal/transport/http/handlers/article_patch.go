@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"sync"
+
+	articleSvc "gopress/internal/app/article"
+	"gopress/internal/domain/article"
+	"gopress/internal/transport/http/middleware"
+)
+
+const mergePatchType = "application/merge-patch+json"
+
+// patch applies a JSON Merge Patch (RFC 7396): members present change,
+// absent ones are kept. Keys match as in memberName.
+func (h *ArticleHandler) patch(w http.ResponseWriter, r *http.Request, id int64) {
+	ctx := r.Context()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || (mt != mergePatchType && mt != "application/json") {
+		w.Header().Set("Accept-Patch", mergePatchType)
+		http.Error(w, "unsupported media type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil || doc == nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+	p, err := decodeArticlePatch(doc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, ok := h.expectedVersion(w, r, id)
+	if !ok {
+		return
+	}
+
+	a, err := h.service.Patch(ctx, userID, id, p, version)
+	if err != nil {
+		switch {
+		case writeVersionError(w, err):
+		case errors.Is(err, articleSvc.ErrInvalidData):
+			http.Error(w, "title and content must not be empty, content_format markdown, html or plain", http.StatusBadRequest)
+		default:
+			http.Error(w, "not found or forbidden", http.StatusNotFound)
+		}
+		return
+	}
+	h.invalidate(id)
+
+	w.Header().Set("ETag", articleETag(a))
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": "ok", "version": a.Version})
+}
+
+// articlePatchFields maps member names to the editable fields of an
+// article.
+var articlePatchFields = sync.OnceValue(func() map[string]string {
+	names := article.PatchFieldNames()
+	fields := make(map[string]string, len(names))
+	for _, name := range names {
+		fields[memberName(name)] = name
+	}
+	return fields
+})
+
+// decodeArticlePatch maps merge patch members to an article.Patch. null
+// removes a member, which the required fields do not allow. Members of an
+// article that are not editable are read-only.
+func decodeArticlePatch(doc map[string]json.RawMessage) (article.Patch, error) {
+	var p article.Patch
+	for key, raw := range doc {
+		name := memberName(key)
+		field, ok := articlePatchFields()[name]
+		if !ok {
+			if articleMembers()[name] {
+				return p, errors.New("read-only field: " + key)
+			}
+			return p, errors.New("unknown field: " + key)
+		}
+
+		if err := p.Set(field, raw); err != nil {
+			switch {
+			case errors.Is(err, article.ErrDuplicateField):
+				return p, errors.New("duplicate field: " + key)
+			case errors.Is(err, article.ErrRequiredField):
+				return p, errors.New(key + " cannot be removed")
+			default:
+				return p, errors.New("invalid value of " + key)
+			}
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopress/internal/domain/article"
+)
+
+func TestDecodeArticlePatch(t *testing.T) {
+	title, content := "New title", "New content"
+	html := article.FormatHTML
+
+	tests := []struct {
+		name string
+		doc  string
+		want article.Patch
+		err  string
+	}{
+		{"empty", `{}`, article.Patch{}, ""},
+		{"title", `{"title":"New title"}`, article.Patch{Title: &title}, ""},
+		{"all", `{"title":"New title","content":"New content","content_format":"html"}`,
+			article.Patch{Title: &title, Content: &content, ContentFormat: &html}, ""},
+		{"camel case", `{"contentFormat":"html"}`, article.Patch{ContentFormat: &html}, ""},
+		{"any case", `{"TITLE":"New title"}`, article.Patch{Title: &title}, ""},
+		{"remove required", `{"title":null}`, article.Patch{}, "title cannot be removed"},
+		{"wrong type", `{"content":42}`, article.Patch{}, "invalid value of content"},
+		{"duplicate spelling", `{"content_format":"html","contentFormat":"plain"}`, article.Patch{}, "duplicate field"},
+		{"read only", `{"version":3}`, article.Patch{}, "read-only field: version"},
+		{"read only snake case", `{"author_username":"eve"}`, article.Patch{}, "read-only field: author_username"},
+		{"unknown", `{"tags":["go"]}`, article.Patch{}, "unknown field: tags"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.doc), &doc); err != nil {
+				t.Fatal(err)
+			}
+			p, err := decodeArticlePatch(doc)
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Errorf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !samePtr(p.Title, tt.want.Title) || !samePtr(p.Content, tt.want.Content) || !samePtr(p.ContentFormat, tt.want.ContentFormat) {
+				t.Errorf("patch = %+v, want %+v", p, tt.want)
+			}
+		})
+	}
+}
+
+func samePtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	articleSvc "gopress/internal/app/article"
+)
+
+const slugPrefix = "/articles/by-slug/"
+
+// ArticleBySlug answers GET /articles/by-slug/{slug} like GET
+// /articles/{id}. A former slug is redirected permanently to the current one.
+func (h *ArticleHandler) ArticleBySlug(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	slug := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), slugPrefix)
+	if slug == "" || strings.Contains(slug, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	id, current, err := h.service.ResolveSlug(r.Context(), slug)
+	if err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if current != slug {
+		h.setCacheControl(w, r)
+		http.Redirect(w, r, slugPrefix+url.PathEscape(current), http.StatusMovedPermanently)
+		return
+	}
+	h.get(w, r, id)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopress/internal/domain/article"
+)
+
+const sseHeartbeat = 15 * time.Second
+
+type articleEventResponse struct {
+	ID         int64             `json:"id"`
+	Type       article.EventType `json:"type"`
+	ArticleID  int64             `json:"article_id"`
+	Article    *article.Article  `json:"article,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+// Stream is a Server-Sent Events feed of article changes. Clients resume
+// with the standard Last-Event-ID header (or the last_event_id query param).
+func (h *ArticleHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	var lastEventID int64
+	if lastID != "" {
+		v, err := strconv.ParseInt(lastID, 10, 64)
+		if err != nil || v < 0 {
+			http.Error(w, "invalid last event id", http.StatusBadRequest)
+			return
+		}
+		lastEventID = v
+	}
+
+	rc := http.NewResponseController(w)
+	// the server WriteTimeout would cut the stream, keep pushing it forward
+	extend := func() error {
+		return rc.SetWriteDeadline(time.Now().Add(2 * sseHeartbeat))
+	}
+	if err := extend(); err != nil {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	ctx := r.Context()
+	events := make(chan *article.Event)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.service.Watch(ctx, lastEventID, func(e *article.Event) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	ticker := time.NewTicker(sseHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-errCh:
+			if err != nil && ctx.Err() == nil {
+				log.Printf("article stream: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := extend(); err != nil {
+				return
+			}
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case e := <-events:
+			data, err := json.Marshal(articleEventResponse{
+				ID:         e.ID,
+				Type:       e.Type,
+				ArticleID:  e.ArticleID,
+				Article:    e.Article,
+				OccurredAt: e.OccurredAt,
+			})
+			if err != nil {
+				return
+			}
+			if err := extend(); err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+				return
+			}
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
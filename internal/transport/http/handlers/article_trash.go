@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	articleSvc "gopress/internal/app/article"
+	"gopress/internal/domain/article"
+	"gopress/internal/transport/http/middleware"
+)
+
+// Trash: GET /articles/trash lists the caller's deleted articles.
+func (h *ArticleHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	articles, err := h.service.ListTrash(ctx, userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if articles == nil {
+		articles = make([]*article.Article, 0)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(articles)
+}
+
+// TrashByID: POST /articles/trash/{id}/restore, DELETE /articles/trash/{id}
+// deletes for good.
+func (h *ArticleHandler) TrashByID(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/articles/trash/"
+	rest := strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), prefix)
+	idStr, action, _ := strings.Cut(rest, "/")
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodDelete:
+		err = h.service.Purge(ctx, userID, id)
+	case action == "restore" && r.Method == http.MethodPost:
+		err = h.service.Restore(ctx, userID, id)
+	case action == "" || action == "restore":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		if errors.Is(err, articleSvc.ErrNotFound) {
+			http.Error(w, "not found or forbidden", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
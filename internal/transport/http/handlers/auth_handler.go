@@ -5,8 +5,10 @@ import (
 	"errors"
 
 	authSvc "gopress/internal/app/auth"
+	"gopress/internal/domain/user"
 	"gopress/internal/transport/http/middleware"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -37,29 +39,40 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.service.Login(r.Context(), req.Username, req.Password)
+	res, err := h.service.Login(r.Context(), req.Username, req.Password)
 	if err != nil {
+		if errors.Is(err, authSvc.ErrTooManyAttempts) {
+			writeThrottled(w, err)
+			return
+		}
 		if errors.Is(err, authSvc.ErrInvalidData) {
 			http.Error(w, "invalid username or password", http.StatusUnauthorized)
 			return
 		}
+		if errors.Is(err, authSvc.ErrSuspended) {
+			http.Error(w, "account suspended", http.StatusForbidden)
+			return
+		}
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	if token == "" {
+	if res.MFAToken != "" {
+		// second step: POST /login/mfa
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"status":    "mfa_required",
+			"mfa_token": res.MFAToken,
+		})
+		return
+	}
+
+	if res.Token == "" {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "token",
-		Value:    token,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-	})
+	setTokenCookie(w, res.Token)
 
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
@@ -100,6 +113,10 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "invalid data", http.StatusBadRequest)
 			return
 		}
+		if errors.Is(err, authSvc.ErrWeakPassword) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, "internal error", http.StatusInternalServerError)
 		return
 	}
@@ -115,9 +132,16 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 }
 
 type getMeResponse struct {
-	Email     string    `json:"email"`
-	Username  string    `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	Email       string    `json:"email"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	AvatarURL   string    `json:"avatar_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	Role        string    `json:"role"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
 }
 
 func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
@@ -144,9 +168,55 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(getMeResponse{
-		Email:     u.Email,
-		Username:  u.Username,
-		CreatedAt: u.CreatedAt,
+	_ = json.NewEncoder(w).Encode(mapMe(u))
+}
+
+func mapMe(u *user.User) getMeResponse {
+	return getMeResponse{
+		ID:          u.ID.String(),
+		Email:       u.Email,
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		AvatarURL:   u.AvatarURL,
+		CreatedAt:   u.CreatedAt,
+		Role:        u.Role,
+
+		EmailVerifiedAt: u.EmailVerifiedAt,
+	}
+}
+
+func setTokenCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "token",
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearTokenCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "token",
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
 	})
 }
+
+// writeThrottled answers 429 with Retry-After in whole seconds.
+func writeThrottled(w http.ResponseWriter, err error) {
+	var te *authSvc.ThrottleError
+	if errors.As(err, &te) {
+		secs := int(te.RetryAfter.Round(time.Second) / time.Second)
+		if secs < 1 {
+			secs = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+	}
+	http.Error(w, "too many attempts", http.StatusTooManyRequests)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopress/internal/domain/article"
+)
+
+// articleETag is the strong entity tag of an article version, sent by
+// writes for the next If-Match.
+func articleETag(a *article.Article) string {
+	return `"` + strconv.FormatInt(a.Version, 10) + `"`
+}
+
+// representationETag tags a GET body: the version, for If-Match, and a
+// hash of the body, as the author's name and the rendered HTML can change
+// while the version does not.
+func representationETag(a *article.Article, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + strconv.FormatInt(a.Version, 10) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// ifMatchVersions parses If-Match into the article versions it names, from
+// the tags of writes ("3") and of reads ("3-<hash>"). It returns nil for no
+// header or "*". ok is false if no version of ours can match: weak or
+// foreign tags fail the strong comparison If-Match needs.
+func ifMatchVersions(r *http.Request) (versions []int64, ok bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) || len(tag) < 2 {
+			continue
+		}
+		version, _, _ := strings.Cut(tag[1:len(tag)-1], "-")
+		v, err := strconv.ParseInt(version, 10, 64)
+		if err != nil || v <= 0 {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return versions, len(versions) > 0
+}
+
+// notModified reports whether a GET can be answered 304. If-None-Match
+// uses the weak comparison and, when present, If-Modified-Since is
+// ignored (RFC 9110, 13.2.2).
+func notModified(r *http.Request, etag, lastModified string) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, tag := range strings.Split(header, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since.Truncate(time.Second))
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"gopress/internal/domain/article"
+)
+
+func TestIfMatchVersions(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []int64
+		ok     bool
+	}{
+		{"absent", "", nil, true},
+		{"any", "*", nil, true},
+		{"write tag", `"3"`, []int64{3}, true},
+		{"read tag", `"3-0123456789abcdef"`, []int64{3}, true},
+		{"list", ` "3", "4-ab" ,"5"`, []int64{3, 4, 5}, true},
+		{"weak", `W/"3"`, nil, false},
+		{"weak and strong", `W/"3", "4"`, []int64{4}, true},
+		{"unquoted", `3`, nil, false},
+		{"lone quote", `"`, nil, false},
+		{"not a version", `"abc"`, nil, false},
+		{"zero", `"0"`, nil, false},
+		{"negative", `"-1"`, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/articles/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+			got, ok := ifMatchVersions(r)
+			if !slices.Equal(got, tt.want) || ok != tt.ok {
+				t.Errorf("ifMatchVersions(%q) = %v, %v, want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestETagsMatchIfMatch(t *testing.T) {
+	a := &article.Article{Version: 7}
+	for _, etag := range []string{articleETag(a), representationETag(a, []byte(`{"id":1}`))} {
+		r := httptest.NewRequest("PUT", "/articles/1", nil)
+		r.Header.Set("If-Match", etag)
+		if got, ok := ifMatchVersions(r); !ok || !slices.Equal(got, []int64{7}) {
+			t.Errorf("If-Match %s = %v, %v, want [7]", etag, got, ok)
+		}
+	}
+	if representationETag(a, []byte("a")) == representationETag(a, []byte("b")) {
+		t.Error("representationETag ignores the body")
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	const (
+		etag     = `"3-0123456789abcdef"`
+		modified = "Wed, 01 Jul 2026 10:00:00 GMT"
+	)
+	tests := []struct {
+		name        string
+		noneMatch   string
+		modSince    string
+		notModified bool
+	}{
+		{"no headers", "", "", false},
+		{"same tag", etag, "", true},
+		{"weak same tag", "W/" + etag, "", true},
+		{"in list", `"2-ff", ` + etag, "", true},
+		{"any", "*", "", true},
+		{"other tag", `"2-ff"`, "", false},
+		{"tag wins over date", `"2-ff"`, "Thu, 02 Jul 2026 10:00:00 GMT", false},
+		{"since later", "", "Thu, 02 Jul 2026 10:00:00 GMT", true},
+		{"since same", "", modified, true},
+		{"since earlier", "", "Tue, 30 Jun 2026 10:00:00 GMT", false},
+		{"bad date", "", "yesterday", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/articles/1", nil)
+			if tt.noneMatch != "" {
+				r.Header.Set("If-None-Match", tt.noneMatch)
+			}
+			if tt.modSince != "" {
+				r.Header.Set("If-Modified-Since", tt.modSince)
+			}
+			if got := notModified(r, etag, modified); got != tt.notModified {
+				t.Errorf("notModified = %v, want %v", got, tt.notModified)
+			}
+		})
+	}
+}
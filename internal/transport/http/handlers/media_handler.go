@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	mediaSvc "gopress/internal/app/media"
+	"gopress/internal/domain/media"
+	"gopress/internal/transport/http/middleware"
+)
+
+const (
+	// room for the multipart boundaries and part headers around the file
+	multipartOverhead = 64 << 10
+	// the server ReadTimeout and WriteTimeout are too short for large files
+	// on slow links; the write deadline runs from the start of the request
+	uploadTimeout = 5 * time.Minute
+)
+
+type MediaHandler struct {
+	service *mediaSvc.Service
+}
+
+func NewMediaHandler(service *mediaSvc.Service) *MediaHandler {
+	return &MediaHandler{service: service}
+}
+
+type mediaResponse struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	SHA256      string    `json:"sha256"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type listMediaResponse struct {
+	Media []mediaResponse `json:"media"`
+	Used  int64           `json:"used"`
+	// 0 means no limit
+	Quota int64 `json:"quota"`
+}
+
+func mapMedia(m *media.Media) mediaResponse {
+	return mediaResponse{
+		ID:          m.ID.String(),
+		URL:         "/media/" + m.ID.String(),
+		Filename:    m.Filename,
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		SHA256:      m.SHA256,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+// Media: GET lists the caller's files with the storage used, POST uploads
+// one as the "file" field of a multipart/form-data body.
+func (h *MediaHandler) Media(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listMedia(w, r)
+	case http.MethodPost:
+		h.uploadMedia(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// MediaByID: GET /media/{id} serves a file to anyone, DELETE removes one of
+// the caller's.
+func (h *MediaHandler) MediaByID(w http.ResponseWriter, r *http.Request) {
+	const prefix = "/media/"
+	id, err := uuid.Parse(strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), prefix))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.serveMedia(w, r, id)
+	case http.MethodDelete:
+		h.deleteMedia(w, r, id)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *MediaHandler) listMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	files, err := h.service.List(ctx, userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	used, quota, err := h.service.Usage(ctx, userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := listMediaResponse{Media: make([]mediaResponse, 0, len(files)), Used: used, Quota: quota}
+	for _, m := range files {
+		resp.Media = append(resp.Media, mapMedia(m))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *MediaHandler) uploadMedia(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if r.ContentLength > h.service.MaxSize()+multipartOverhead {
+		http.Error(w, mediaSvc.ErrTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(uploadTimeout)
+	_ = rc.SetReadDeadline(deadline)
+	// or the 201 of a slow upload is lost after it was stored, and the
+	// client retries into a duplicate
+	_ = rc.SetWriteDeadline(deadline.Add(time.Minute))
+	r.Body = http.MaxBytesReader(w, r.Body, h.service.MaxSize()+multipartOverhead)
+
+	// streamed part by part, nothing goes to temporary files
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "multipart/form-data expected", http.StatusBadRequest)
+		return
+	}
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				http.Error(w, `missing "file" field`, http.StatusBadRequest)
+				return
+			}
+			writeUploadError(w, err)
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		m, err := h.service.Upload(ctx, userID, part.FileName(), part)
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/media/"+m.ID.String())
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(mapMedia(m))
+		return
+	}
+}
+
+func writeUploadError(w http.ResponseWriter, err error) {
+	var maxErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, mediaSvc.ErrTooLarge), errors.As(err, &maxErr):
+		http.Error(w, mediaSvc.ErrTooLarge.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, mediaSvc.ErrUnsupportedType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, mediaSvc.ErrQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+	case errors.Is(err, mediaSvc.ErrInvalidData):
+		http.Error(w, "empty file", http.StatusBadRequest)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		http.Error(w, "invalid multipart body", http.StatusBadRequest)
+	default:
+		log.Printf("upload media: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+func (h *MediaHandler) serveMedia(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	ctx := r.Context()
+	m, err := h.service.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, mediaSvc.ErrNotFound) {
+			http.Error(w, "media not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	// files never change, a new upload gets a new ID
+	etag := `"` + m.SHA256 + `"`
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	if notModified(r, etag, "") {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", m.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(m.Size, 10))
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": m.Filename}))
+	// the sniffed type is final; no scripts even if a browser thinks otherwise
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	rc, err := h.service.Open(ctx, m)
+	if err != nil {
+		header.Del("Content-Length")
+		header.Del("Cache-Control")
+		if errors.Is(err, mediaSvc.ErrNotFound) {
+			http.Error(w, "media not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	defer rc.Close()
+	if _, err := io.Copy(w, rc); err != nil {
+		log.Printf("serve media %s: %v", m.ID, err)
+	}
+}
+
+func (h *MediaHandler) deleteMedia(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, mediaSvc.ErrNotFound) {
+			http.Error(w, "media not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
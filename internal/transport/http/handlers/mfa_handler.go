@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/transport/http/middleware"
+)
+
+type loginMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type totpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type totpEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginMFA is the second login step for accounts with TOTP enabled.
+func (h *AuthHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req loginMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.service.LoginMFA(r.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	setTokenCookie(w, token)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// TOTP starts enrollment (POST) or disables TOTP (DELETE).
+func (h *AuthHandler) TOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		e, err := h.service.StartTOTP(ctx, userID)
+		if err != nil {
+			writeMFAError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(totpEnrollmentResponse{Secret: e.Secret, URI: e.URI})
+	case http.MethodDelete:
+		var req totpCodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+
+		if err := h.service.DisableTOTP(ctx, userID, req.Code); err != nil {
+			writeMFAError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AuthHandler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req totpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.service.ConfirmTOTP(ctx, userID, req.Code)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func writeMFAError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authSvc.ErrTooManyAttempts):
+		writeThrottled(w, err)
+	case errors.Is(err, authSvc.ErrInvalidData):
+		http.Error(w, "invalid data", http.StatusBadRequest)
+	case errors.Is(err, authSvc.ErrInvalidToken):
+		http.Error(w, "invalid or expired mfa token", http.StatusUnauthorized)
+	case errors.Is(err, authSvc.ErrInvalidCode):
+		http.Error(w, "invalid code", http.StatusUnauthorized)
+	case errors.Is(err, authSvc.ErrMFAEnabled):
+		http.Error(w, "two-factor authentication already enabled", http.StatusConflict)
+	case errors.Is(err, authSvc.ErrMFANotEnabled):
+		http.Error(w, "two-factor authentication not enabled", http.StatusConflict)
+	case errors.Is(err, authSvc.ErrUserNotFound):
+		http.Error(w, "user not found", http.StatusUnauthorized)
+	case errors.Is(err, authSvc.ErrSuspended):
+		http.Error(w, "account suspended", http.StatusForbidden)
+	default:
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/domain/user"
+	"gopress/internal/transport/http/middleware"
+)
+
+type createOAuthClientRequest struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Scopes       []string `json:"scopes"`
+	// false for SPAs and native apps, which can't keep a secret
+	Confidential bool `json:"confidential"`
+}
+
+type oauthClientResponse struct {
+	ClientID     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	Confidential bool      `json:"confidential"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type createOAuthClientResponse struct {
+	oauthClientResponse
+	// shown once, confidential clients only
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type authorizedAppResponse struct {
+	ClientID   string     `json:"client_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// OAuthClients: GET lists the caller's registered applications, POST
+// registers one.
+func (h *AuthHandler) OAuthClients(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listOAuthClients(w, r)
+	case http.MethodPost:
+		h.createOAuthClient(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// OAuthClientByID: DELETE /oauth/clients/{id} deletes an application and
+// revokes every token issued to it.
+func (h *AuthHandler) OAuthClientByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	const prefix = "/oauth/clients/"
+	id, err := uuid.Parse(strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), prefix))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteOAuthClient(ctx, userID, id); err != nil {
+		if errors.Is(err, authSvc.ErrOAuthClientNotFound) {
+			http.Error(w, "client not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) listOAuthClients(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	clients, err := h.service.ListOAuthClients(ctx, userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]oauthClientResponse, 0, len(clients))
+	for i := range clients {
+		resp = append(resp, mapOAuthClient(&clients[i]))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AuthHandler) createOAuthClient(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req createOAuthClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.service.RegisterOAuthClient(ctx, userID, req.Name, req.RedirectURIs, req.Scopes, req.Confidential)
+	if err != nil {
+		switch {
+		case errors.Is(err, authSvc.ErrInvalidData):
+			http.Error(w, "name, valid redirect_uris and scopes required", http.StatusBadRequest)
+		case errors.Is(err, authSvc.ErrTooManyOAuthClients):
+			http.Error(w, "too many clients", http.StatusConflict)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(createOAuthClientResponse{
+		oauthClientResponse: mapOAuthClient(created.OAuthClient),
+		ClientSecret:        created.Secret,
+	})
+}
+
+// AuthorizedApps: GET /me/oauth/apps lists the applications the caller
+// has granted access to.
+func (h *AuthHandler) AuthorizedApps(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	apps, err := h.service.ListAuthorizedApps(ctx, userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := make([]authorizedAppResponse, 0, len(apps))
+	for _, a := range apps {
+		resp = append(resp, authorizedAppResponse{
+			ClientID:   a.ClientID.String(),
+			Name:       a.Name,
+			Scopes:     a.Scopes,
+			CreatedAt:  a.CreatedAt,
+			LastUsedAt: a.LastUsedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// AuthorizedAppByID: DELETE /me/oauth/apps/{client_id} withdraws access.
+func (h *AuthHandler) AuthorizedAppByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	const prefix = "/me/oauth/apps/"
+	clientID, err := uuid.Parse(strings.TrimPrefix(strings.TrimSuffix(r.URL.Path, "/"), prefix))
+	if err != nil {
+		http.Error(w, "invalid id", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.RevokeAuthorizedApp(ctx, userID, clientID); err != nil {
+		if errors.Is(err, authSvc.ErrOAuthClientNotFound) {
+			http.Error(w, "app not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func mapOAuthClient(c *user.OAuthClient) oauthClientResponse {
+	return oauthClientResponse{
+		ClientID:     c.ID.String(),
+		Name:         c.Name,
+		RedirectURIs: c.RedirectURIs,
+		Scopes:       c.Scopes,
+		Confidential: c.Confidential(),
+		CreatedAt:    c.CreatedAt,
+	}
+}
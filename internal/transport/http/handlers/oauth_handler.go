@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/transport/http/middleware"
+)
+
+type authorizeRequest struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	// the user's decision on the consent screen
+	Approve bool `json:"approve"`
+}
+
+type consentResponse struct {
+	Client struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"client"`
+	Scopes      []string `json:"scopes"`
+	RedirectURI string   `json:"redirect_uri"`
+	// already authorized, the consent screen may be skipped
+	Granted bool `json:"granted"`
+}
+
+type oauthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+type introspectionResponse struct {
+	Active    bool   `json:"active"`
+	TokenType string `json:"token_type,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Username  string `json:"username,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+}
+
+type oauthErrorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	// where to send the user to report the error to the client
+	RedirectTo string `json:"redirect_to,omitempty"`
+}
+
+// Authorize is the API behind the consent screen. GET takes the
+// authorization request query and describes it; POST takes the same
+// parameters plus the user's decision and answers where to redirect.
+func (h *AuthHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.checkAuthorization(w, r)
+	case http.MethodPost:
+		h.authorize(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AuthHandler) checkAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	consent, err := h.service.CheckAuthorization(ctx, userID, authSvc.AuthorizeRequest{
+		ResponseType:        q.Get("response_type"),
+		ClientID:            q.Get("client_id"),
+		RedirectURI:         q.Get("redirect_uri"),
+		Scope:               q.Get("scope"),
+		State:               q.Get("state"),
+		CodeChallenge:       q.Get("code_challenge"),
+		CodeChallengeMethod: q.Get("code_challenge_method"),
+	})
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	var resp consentResponse
+	resp.Client.ID = consent.Client.ID.String()
+	resp.Client.Name = consent.Client.Name
+	resp.Scopes = consent.Scopes
+	resp.RedirectURI = consent.RedirectURI
+	resp.Granted = consent.Granted
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AuthHandler) authorize(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req authorizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid json", http.StatusBadRequest)
+		return
+	}
+
+	redirectTo, err := h.service.Authorize(ctx, userID, authSvc.AuthorizeRequest{
+		ResponseType:        req.ResponseType,
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		State:               req.State,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}, req.Approve)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"redirect_to": redirectTo})
+}
+
+// Token is the token endpoint (RFC 6749): grant_type authorization_code
+// or refresh_token, form encoded, client authenticated with HTTP Basic or
+// client_id/client_secret parameters.
+func (h *AuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, &authSvc.OAuthError{Code: "invalid_request", Description: "invalid form"})
+		return
+	}
+
+	cc := clientCredentials(r)
+	ctx := r.Context()
+
+	var (
+		tokens *authSvc.OAuthTokens
+		err    error
+	)
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		tokens, err = h.service.ExchangeOAuthCode(ctx, cc,
+			r.PostForm.Get("code"), r.PostForm.Get("redirect_uri"), r.PostForm.Get("code_verifier"))
+	case "refresh_token":
+		tokens, err = h.service.RefreshOAuthToken(ctx, cc, r.PostForm.Get("refresh_token"), r.PostForm.Get("scope"))
+	default:
+		err = &authSvc.OAuthError{Code: "unsupported_grant_type", Description: "use authorization_code or refresh_token"}
+	}
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(oauthTokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		RefreshToken: tokens.RefreshToken,
+		Scope:        joinScopes(tokens.Scopes),
+	})
+}
+
+// Introspect is the token introspection endpoint (RFC 7662) for the
+// client's own tokens.
+func (h *AuthHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, &authSvc.OAuthError{Code: "invalid_request", Description: "invalid form"})
+		return
+	}
+
+	info, err := h.service.IntrospectOAuthToken(r.Context(), clientCredentials(r), r.PostForm.Get("token"))
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	resp := introspectionResponse{Active: info.Active}
+	if info.Active {
+		resp.TokenType = info.TokenType
+		resp.ClientID = info.ClientID.String()
+		resp.Sub = info.UserID.String()
+		resp.Username = info.Username
+		resp.Scope = joinScopes(info.Scopes)
+		resp.Exp = info.ExpiresAt.Unix()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// Revoke is the token revocation endpoint (RFC 7009). It answers 200 for
+// unknown tokens too.
+func (h *AuthHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, &authSvc.OAuthError{Code: "invalid_request", Description: "invalid form"})
+		return
+	}
+
+	if err := h.service.RevokeOAuthToken(r.Context(), clientCredentials(r), r.PostForm.Get("token")); err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// clientCredentials takes HTTP Basic (form-urlencoded id and secret, RFC
+// 6749 2.3.1) over the client_id/client_secret parameters.
+func clientCredentials(r *http.Request) authSvc.ClientCredentials {
+	if id, secret, ok := r.BasicAuth(); ok {
+		if uid, err := url.QueryUnescape(id); err == nil {
+			id = uid
+		}
+		if usecret, err := url.QueryUnescape(secret); err == nil {
+			secret = usecret
+		}
+		return authSvc.ClientCredentials{ID: id, Secret: secret}
+	}
+	return authSvc.ClientCredentials{
+		ID:     r.PostForm.Get("client_id"),
+		Secret: r.PostForm.Get("client_secret"),
+	}
+}
+
+func writeOAuthError(w http.ResponseWriter, err error) {
+	resp := oauthErrorResponse{Error: "server_error"}
+	status := http.StatusInternalServerError
+
+	var oe *authSvc.OAuthError
+	if errors.As(err, &oe) {
+		resp = oauthErrorResponse{Error: oe.Code, Description: oe.Description, RedirectTo: oe.RedirectTo}
+		status = http.StatusBadRequest
+		if oe.Code == "invalid_client" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="gopress"`)
+			status = http.StatusUnauthorized
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func joinScopes(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/transport/http/middleware"
+)
+
+const (
+	oidcPathPrefix  = "/login/oidc/"
+	oidcStateCookie = "oidc_state"
+)
+
+type identityResponse struct {
+	Provider    string    `json:"provider"`
+	Email       string    `json:"email"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// OIDCProviders: GET /login/oidc lists the configured identity providers.
+func (h *AuthHandler) OIDCProviders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string][]string{"providers": h.service.OIDCProviders()})
+}
+
+// OIDCLogin serves GET /login/oidc/{provider}, which redirects to the
+// provider, and GET /login/oidc/{provider}/callback, where it sends the
+// user back.
+func (h *AuthHandler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, oidcPathPrefix)
+	provider, sub, _ := strings.Cut(rest, "/")
+	switch {
+	case provider == "":
+		http.NotFound(w, r)
+	case sub == "":
+		h.startOIDC(w, r, provider)
+	case sub == "callback":
+		h.finishOIDC(w, r, provider)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *AuthHandler) startOIDC(w http.ResponseWriter, r *http.Request, provider string) {
+	authURL, state, err := h.service.StartOIDCLogin(r.Context(), provider)
+	if err != nil {
+		writeOIDCError(w, err)
+		return
+	}
+
+	// binds the login to this browser, checked in the callback
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     oidcPathPrefix,
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (h *AuthHandler) finishOIDC(w http.ResponseWriter, r *http.Request, provider string) {
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		log.Printf("oidc %s: provider returned %s: %s", provider, e, q.Get("error_description"))
+		http.Error(w, "sign-in was cancelled or denied", http.StatusUnauthorized)
+		return
+	}
+
+	state := q.Get("state")
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		http.Error(w, "invalid or expired login state", http.StatusBadRequest)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     oidcPathPrefix,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	res, err := h.service.FinishOIDCLogin(r.Context(), provider, state, q.Get("code"))
+	if err != nil {
+		writeOIDCError(w, err)
+		return
+	}
+
+	if res.MFAToken != "" {
+		// second step: POST /login/mfa
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"status":    "mfa_required",
+			"mfa_token": res.MFAToken,
+		})
+		return
+	}
+
+	setTokenCookie(w, res.Token)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+func writeOIDCError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, authSvc.ErrUnknownProvider):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, authSvc.ErrInvalidData), errors.Is(err, authSvc.ErrInvalidToken):
+		http.Error(w, "invalid or expired login state", http.StatusBadRequest)
+	case errors.Is(err, authSvc.ErrIdentityConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, authSvc.ErrSuspended):
+		http.Error(w, "account suspended", http.StatusForbidden)
+	case errors.Is(err, authSvc.ErrProvisioningDisabled):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, authSvc.ErrExternalLogin):
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// Identities: GET /me/identities lists the linked external accounts.
+func (h *AuthHandler) Identities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ids, err := h.service.ListIdentities(ctx, userID)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	res := make([]identityResponse, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, identityResponse{
+			Provider:    id.Provider,
+			Email:       id.Email,
+			CreatedAt:   id.CreatedAt,
+			LastLoginAt: id.LastLoginAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
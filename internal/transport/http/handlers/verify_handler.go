@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	authSvc "gopress/internal/app/auth"
+	"gopress/internal/transport/http/middleware"
+)
+
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// VerifyEmail confirms an email address. GET is for the link in the email
+// (?token=...), POST takes the token in a JSON body.
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req verifyEmailRequest
+	switch r.Method {
+	case http.MethodGet:
+		req.Token = r.URL.Query().Get("token")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := h.service.VerifyEmail(r.Context(), req.Token); err != nil {
+		switch {
+		case errors.Is(err, authSvc.ErrInvalidData):
+			http.Error(w, "invalid data", http.StatusBadRequest)
+		case errors.Is(err, authSvc.ErrInvalidToken):
+			http.Error(w, "invalid or expired token", http.StatusBadRequest)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.ResendVerification(ctx, userID); err != nil {
+		switch {
+		case errors.Is(err, authSvc.ErrVerified):
+			http.Error(w, "email already verified", http.StatusConflict)
+		case errors.Is(err, authSvc.ErrTooManyEmails):
+			http.Error(w, "too many emails, try later", http.StatusTooManyRequests)
+		case errors.Is(err, authSvc.ErrUserNotFound):
+			http.Error(w, "user not found", http.StatusNotFound)
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
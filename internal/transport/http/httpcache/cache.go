@@ -0,0 +1,42 @@
+// Package httpcache keeps rendered HTTP responses so repeated reads skip
+// the database.
+package httpcache
+
+import (
+	"net/http"
+	"time"
+
+	"gopress/pkg/lru"
+)
+
+// Response is a stored 200 answer.
+type Response struct {
+	Header http.Header
+	Body   []byte
+}
+
+// Cache stores responses by key. Implementations must be safe for
+// concurrent use; a shared one (e.g. Redis) can replace Memory when
+// several instances should see the same entries.
+type Cache interface {
+	Get(key string) (*Response, bool)
+	Set(key string, r *Response)
+	Delete(key string)
+	// Purge drops everything, for when invalidations may have been missed.
+	Purge()
+}
+
+type memory struct {
+	entries *lru.Cache[string, *Response]
+}
+
+// NewMemory keeps up to size responses in process for at most ttl. The
+// ttl bounds how long an entry can outlive a missed invalidation.
+func NewMemory(size int, ttl time.Duration) Cache {
+	return &memory{entries: lru.New[string, *Response](size, ttl)}
+}
+
+func (m *memory) Get(key string) (*Response, bool) { return m.entries.Get(key) }
+func (m *memory) Set(key string, r *Response)      { m.entries.Add(key, r) }
+func (m *memory) Delete(key string)                { m.entries.Remove(key) }
+func (m *memory) Purge()                           { m.entries.Purge() }
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"net/http"
+	"strings"
 
-	jwtpkg "gopress/pkg/jwt"
+	authSvc "gopress/internal/app/auth"
 )
 
 type ctxKey string
@@ -13,35 +15,99 @@ type ctxKey string
 const (
 	ctxUserIDKey   ctxKey = "userId"
 	ctxUsernameKey ctxKey = "username"
+	ctxSessionKey  ctxKey = "sessionId"
 )
 
-func RequireAuth(jwtManager *jwtpkg.Manager, next http.Handler) http.Handler {
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*authSvc.Identity, error)
+}
+
+// RequireAuth accepts session tokens only, API keys and OAuth client
+// tokens get 403.
+func RequireAuth(auth Authenticator, next http.Handler) http.Handler {
+	return requireAuth(auth, nil, next)
+}
+
+// RequireScopes also accepts API keys and OAuth client tokens: GET and
+// HEAD need the read scope, other methods the write scope. An empty scope
+// keeps them out.
+func RequireScopes(auth Authenticator, read, write string, next http.Handler) http.Handler {
+	return requireAuth(auth, func(r *http.Request) string {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			return read
+		}
+		return write
+	}, next)
+}
+
+// PublicReads is RequireScopes that also lets GET and HEAD through without
+// credentials; UserIDFromContext then reports no user. Bad credentials are
+// still refused.
+func PublicReads(auth Authenticator, read, write string, next http.Handler) http.Handler {
+	scoped := RequireScopes(auth, read, write, next)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie("token")
-		if err != nil || cookie.Value == "" {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) && requestToken(r) == "" {
+			next.ServeHTTP(w, r)
 			return
 		}
+		scoped.ServeHTTP(w, r)
+	})
+}
 
-		claims, err := jwtManager.ParseToken(cookie.Value)
-		if err != nil {
-			http.Error(w, "invalid token", http.StatusUnauthorized)
+func requireAuth(auth Authenticator, scopeFor func(*http.Request) string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r)
+		if token == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		userID, err := uuid.Parse(claims.UserID)
+		id, err := auth.Authenticate(r.Context(), token)
 		if err != nil {
-			http.Error(w, "invalid token", http.StatusUnauthorized)
+			if errors.Is(err, authSvc.ErrUnauthorized) {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, "internal error", http.StatusInternalServerError)
 			return
 		}
 
+		if id.Scoped() {
+			var scope string
+			if scopeFor != nil {
+				scope = scopeFor(r)
+			}
+			if scope == "" || !id.Can(scope) {
+				http.Error(w, "insufficient scope", http.StatusForbidden)
+				return
+			}
+		}
+
 		ctx := r.Context()
-		ctx = context.WithValue(ctx, ctxUserIDKey, userID)
-		ctx = context.WithValue(ctx, ctxUsernameKey, claims.Username)
+		ctx = context.WithValue(ctx, ctxUserIDKey, id.UserID)
+		ctx = context.WithValue(ctx, ctxUsernameKey, id.Username)
+		ctx = context.WithValue(ctx, ctxSessionKey, id.SessionID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// requestToken takes "Authorization: Bearer" (API keys, non-browser
+// clients) over the "token" cookie.
+func requestToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		scheme, token, ok := strings.Cut(strings.TrimSpace(h), " ")
+		if ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+
+	cookie, err := r.Cookie("token")
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
 func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	v := ctx.Value(ctxUserIDKey)
 	if v == nil {
@@ -50,3 +116,8 @@ func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	id, ok := v.(uuid.UUID)
 	return id, ok
 }
+
+func SessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ctxSessionKey).(uuid.UUID)
+	return id, ok
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopress/internal/app/requestinfo"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestInfo records client IP, user agent and request ID for the app
+// layer. Proxy headers (X-Forwarded-For, X-Real-IP) are only trusted when
+// trustProxy is set, i.e. the server runs behind a reverse proxy.
+func RequestInfo(trustProxy bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID := r.Header.Get(requestIDHeader)
+		if reqID == "" || len(reqID) > 128 {
+			reqID = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, reqID)
+
+		ctx := requestinfo.With(r.Context(), requestinfo.Info{
+			IP:        clientIP(r, trustProxy),
+			UserAgent: r.UserAgent(),
+			RequestID: reqID,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			first, _, _ := strings.Cut(fwd, ",")
+			if ip := net.ParseIP(strings.TrimSpace(first)); ip != nil {
+				return ip.String()
+			}
+		}
+		if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+			return ip.String()
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -1,34 +1,84 @@
 package http
 
 import (
+	"gopress/internal/domain/user"
 	"gopress/internal/transport/http/handlers"
 	"gopress/internal/transport/http/middleware"
-	jwtpkg "gopress/pkg/jwt"
 	"net/http"
 )
 
 type Handlers struct {
 	Auth    *handlers.AuthHandler
 	Article *handlers.ArticleHandler
+	Media   *handlers.MediaHandler
 }
 
 type Router struct {
-	mux *http.ServeMux
+	mux        *http.ServeMux
+	trustProxy bool
 }
 
-func NewRouter(h Handlers, jwtManager *jwtpkg.Manager) *Router {
+// NewRouter builds the HTTP routes. trustProxy makes client IPs come from
+// X-Forwarded-For / X-Real-IP, only enable it behind a reverse proxy.
+func NewRouter(h Handlers, auth middleware.Authenticator, trustProxy bool) *Router {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/login", h.Auth.Login)
+	mux.HandleFunc("/login/mfa", h.Auth.LoginMFA)
+	mux.HandleFunc("/login/oidc", h.Auth.OIDCProviders)
+	mux.HandleFunc("/login/oidc/", h.Auth.OIDCLogin)
 	mux.HandleFunc("/register", h.Auth.Register)
-	mux.Handle("/me", middleware.RequireAuth(jwtManager, http.HandlerFunc(h.Auth.GetMe)))
+	mux.HandleFunc("/password/forgot", h.Auth.ForgotPassword)
+	mux.HandleFunc("/password/reset", h.Auth.ResetPassword)
+	mux.HandleFunc("/email/verify", h.Auth.VerifyEmail)
+	mux.Handle("/email/verify/resend", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.ResendVerification)))
+	mux.Handle("/me", middleware.RequireScopes(auth, user.ScopeProfileRead, "", http.HandlerFunc(h.Auth.Me)))
+	mux.Handle("/me/password", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.ChangePassword)))
+	mux.Handle("/logout", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.Logout)))
+	mux.Handle("/me/sessions", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.Sessions)))
+	mux.Handle("/me/sessions/", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.SessionByID)))
+	mux.Handle("/me/api-keys", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.APIKeys)))
+	mux.Handle("/me/api-keys/", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.APIKeyByID)))
+	mux.Handle("/me/identities", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.Identities)))
+	mux.Handle("/me/oauth/apps", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.AuthorizedApps)))
+	mux.Handle("/me/oauth/apps/", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.AuthorizedAppByID)))
+	mux.Handle("/me/mfa/totp", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.TOTP)))
+	mux.Handle("/me/mfa/totp/confirm", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.ConfirmTOTP)))
 
-	mux.Handle("/articles", middleware.RequireAuth(jwtManager, http.HandlerFunc(h.Article.Articles)))
-	mux.Handle("/articles/", middleware.RequireAuth(jwtManager, http.HandlerFunc(h.Article.ArticlesByID)))
+	// OAuth authorization server: the consent API and client registration
+	// need a login session, the protocol endpoints authenticate clients
+	mux.Handle("/oauth/authorize", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.Authorize)))
+	mux.Handle("/oauth/clients", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.OAuthClients)))
+	mux.Handle("/oauth/clients/", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.OAuthClientByID)))
+	mux.HandleFunc("/oauth/token", h.Auth.Token)
+	mux.HandleFunc("/oauth/introspect", h.Auth.Introspect)
+	mux.HandleFunc("/oauth/revoke", h.Auth.Revoke)
 
-	return &Router{mux: mux}
+	// admin checks the role itself, see auth.Service.requireAdmin
+	mux.Handle("/admin/users", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.AdminUsers)))
+	mux.Handle("/admin/users/", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.AdminUserByID)))
+	mux.Handle("/admin/audit", middleware.RequireAuth(auth, http.HandlerFunc(h.Auth.AuditLog)))
+
+	// API keys can work with articles, see user.Scopes
+	// reading articles needs no account, like the public gRPC methods
+	mux.Handle("/articles", middleware.PublicReads(auth, user.ScopeArticlesRead, user.ScopeArticlesWrite, http.HandlerFunc(h.Article.Articles)))
+	mux.Handle("/articles/stream", articleAuth(auth, h.Article.Stream))
+	mux.Handle("/articles/by-slug/", middleware.PublicReads(auth, user.ScopeArticlesRead, user.ScopeArticlesWrite, http.HandlerFunc(h.Article.ArticleBySlug)))
+	mux.Handle("/articles/", middleware.PublicReads(auth, user.ScopeArticlesRead, user.ScopeArticlesWrite, http.HandlerFunc(h.Article.ArticlesByID)))
+	mux.Handle("/articles/trash", articleAuth(auth, h.Article.Trash))
+	mux.Handle("/articles/trash/", articleAuth(auth, h.Article.TrashByID))
+
+	// uploads are for articles and share their scopes; files are public
+	mux.Handle("/media", articleAuth(auth, h.Media.Media))
+	mux.Handle("/media/", middleware.PublicReads(auth, user.ScopeArticlesRead, user.ScopeArticlesWrite, http.HandlerFunc(h.Media.MediaByID)))
+
+	return &Router{mux: mux, trustProxy: trustProxy}
+}
+
+func articleAuth(auth middleware.Authenticator, h http.HandlerFunc) http.Handler {
+	return middleware.RequireScopes(auth, user.ScopeArticlesRead, user.ScopeArticlesWrite, h)
 }
 
 func (r *Router) Handler() http.Handler {
-	return r.mux
+	return middleware.RequestInfo(r.trustProxy, r.mux)
 }
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE article_events (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(20) NOT NULL,
+    article_id INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE FUNCTION notify_article_event() RETURNS trigger AS $$
+DECLARE
+    ev article_events;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO article_events (type, article_id) VALUES ('created', NEW.id) RETURNING * INTO ev;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO article_events (type, article_id) VALUES ('updated', NEW.id) RETURNING * INTO ev;
+    ELSE
+        INSERT INTO article_events (type, article_id) VALUES ('deleted', OLD.id) RETURNING * INTO ev;
+    END IF;
+
+    PERFORM pg_notify('article_events', json_build_object(
+        'id', ev.id,
+        'type', ev.type,
+        'article_id', ev.article_id,
+        'created_at', ev.created_at
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER articles_notify_event
+    AFTER INSERT OR UPDATE OR DELETE ON articles
+    FOR EACH ROW EXECUTE FUNCTION notify_article_event();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS articles_notify_event ON articles;
+DROP FUNCTION IF EXISTS notify_article_event();
+DROP TABLE IF EXISTS article_events;
+-- +goose StatementEnd
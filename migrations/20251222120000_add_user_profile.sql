@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN bio TEXT NOT NULL DEFAULT '',
+    ADD COLUMN avatar_url VARCHAR(2048) NOT NULL DEFAULT '',
+    -- bumped on password change, tokens with an older version are rejected
+    ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN IF EXISTS token_version,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS display_name;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE login_throttle (
+    -- "user:<username>", "ip:<addr>", "mfa:<user id>"
+    key VARCHAR(300) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    blocked_until TIMESTAMPTZ,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_throttle;
+-- +goose StatementEnd
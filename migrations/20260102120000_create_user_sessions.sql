@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX user_sessions_user_id_idx ON user_sessions (user_id);
+
+-- every replica drops revoked sessions from its cache
+CREATE FUNCTION notify_session_revoked() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' OR (NEW.revoked_at IS NOT NULL AND OLD.revoked_at IS NULL) THEN
+        PERFORM pg_notify('session_revoked', OLD.id::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_sessions_notify_revoked
+    AFTER UPDATE OF revoked_at OR DELETE ON user_sessions
+    FOR EACH ROW EXECUTE FUNCTION notify_session_revoked();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS user_sessions_notify_revoked ON user_sessions;
+DROP FUNCTION IF EXISTS notify_session_revoked();
+DROP TABLE IF EXISTS user_sessions;
+-- +goose StatementEnd
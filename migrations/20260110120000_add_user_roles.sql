@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    ADD COLUMN suspended_at TIMESTAMPTZ,
+    ADD COLUMN suspend_reason TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX users_role_idx ON users (role) WHERE role <> 'user';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_role_idx;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS suspend_reason,
+    DROP COLUMN IF EXISTS suspended_at,
+    DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,81 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX articles_trash_idx ON articles (author_id, deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- for event subscribers moving an article to the trash deletes it and
+-- restoring creates it again; purging a trashed article is silent
+CREATE OR REPLACE FUNCTION notify_article_event() RETURNS trigger AS $$
+DECLARE
+    ev article_events;
+    ev_type VARCHAR(20);
+    ev_article INTEGER;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        ev_type := 'created';
+        ev_article := NEW.id;
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            ev_type := 'deleted';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            ev_type := 'created';
+        ELSIF NEW.deleted_at IS NULL THEN
+            ev_type := 'updated';
+        ELSE
+            RETURN NULL;
+        END IF;
+        ev_article := NEW.id;
+    ELSE
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        ev_type := 'deleted';
+        ev_article := OLD.id;
+    END IF;
+
+    INSERT INTO article_events (type, article_id) VALUES (ev_type, ev_article) RETURNING * INTO ev;
+
+    PERFORM pg_notify('article_events', json_build_object(
+        'id', ev.id,
+        'type', ev.type,
+        'article_id', ev.article_id,
+        'created_at', ev.created_at
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- trashed articles are already gone for subscribers
+DELETE FROM articles WHERE deleted_at IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION notify_article_event() RETURNS trigger AS $$
+DECLARE
+    ev article_events;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO article_events (type, article_id) VALUES ('created', NEW.id) RETURNING * INTO ev;
+    ELSIF TG_OP = 'UPDATE' THEN
+        INSERT INTO article_events (type, article_id) VALUES ('updated', NEW.id) RETURNING * INTO ev;
+    ELSE
+        INSERT INTO article_events (type, article_id) VALUES ('deleted', OLD.id) RETURNING * INTO ev;
+    END IF;
+
+    PERFORM pg_notify('article_events', json_build_object(
+        'id', ev.id,
+        'type', ev.type,
+        'article_id', ev.article_id,
+        'created_at', ev.created_at
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX IF EXISTS articles_trash_idx;
+ALTER TABLE articles DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every replica drops changed users from its cache
+CREATE FUNCTION notify_user_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('user_changed', OLD.id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_notify_changed
+    AFTER UPDATE OR DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION notify_user_changed();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS users_notify_changed ON users;
+DROP FUNCTION IF EXISTS notify_user_changed();
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- existing content is taken as Markdown, plain text renders the same
+ALTER TABLE articles ADD COLUMN content_format VARCHAR(16) NOT NULL DEFAULT 'markdown'
+    CHECK (content_format IN ('markdown', 'html', 'plain'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles DROP COLUMN IF EXISTS content_format;
+-- +goose StatementEnd
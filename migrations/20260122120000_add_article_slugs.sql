@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles ADD COLUMN slug VARCHAR(100);
+
+-- existing articles get slugs by the rules of article.Slugify, except that
+-- accented Latin letters become separators
+CREATE TEMP TABLE article_slug_bases AS
+SELECT id, COALESCE(NULLIF(trim(BOTH '-' FROM left(regexp_replace(
+    translate(
+        replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(lower(title),
+            'щ', 'shch'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'), 'ш', 'sh'),
+            'ю', 'yu'), 'я', 'ya'), 'є', 'ye'), 'ї', 'yi'),
+        'абвгдеёзийклмнопрстуфыэіґўъь', 'abvgdeeziyklmnoprstufyeigu'),
+    '[^a-z0-9]+', '-', 'g'), 80)), ''), 'article') AS base
+FROM articles;
+
+-- the oldest article of each base keeps it, so "Foo 2" stays "foo-2"
+UPDATE articles a
+SET slug = b.base
+FROM (SELECT DISTINCT ON (base) id, base FROM article_slug_bases ORDER BY base, id) b
+WHERE b.id = a.id;
+
+-- trashed articles keep their slug, so a restore cannot clash
+CREATE UNIQUE INDEX articles_slug_idx ON articles (slug);
+
+-- the others get the first free "-n", as uniqueSlug picks it
+DO $$
+DECLARE
+    r RECORD;
+    n INTEGER;
+    candidate TEXT;
+BEGIN
+    FOR r IN
+        SELECT b.id, b.base FROM article_slug_bases b JOIN articles a ON a.id = b.id
+        WHERE a.slug IS NULL
+        ORDER BY b.id
+    LOOP
+        n := 2;
+        LOOP
+            candidate := r.base || '-' || n;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM articles WHERE slug = candidate);
+            n := n + 1;
+        END LOOP;
+        UPDATE articles SET slug = candidate WHERE id = r.id;
+    END LOOP;
+END $$;
+
+DROP TABLE article_slug_bases;
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+
+-- former slugs of articles, answered with a redirect to the current one
+CREATE TABLE article_slug_redirects (
+    slug VARCHAR(100) PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX article_slug_redirects_article_idx ON article_slug_redirects (article_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS article_slug_redirects;
+ALTER TABLE articles DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE articles
+    ADD COLUMN excerpt TEXT NOT NULL DEFAULT '',
+    ADD COLUMN word_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN reading_minutes INTEGER NOT NULL DEFAULT 0;
+
+-- an approximation for existing articles: tags are dropped but Markdown
+-- marks are kept. The next update stores the exact values.
+WITH text AS (
+    SELECT id, trim(regexp_replace(regexp_replace(content, '<[^>]*>', ' ', 'g'), '\s+', ' ', 'g')) AS t
+    FROM articles
+), counted AS (
+    SELECT id, t, CASE WHEN t = '' THEN 0 ELSE array_length(string_to_array(t, ' '), 1) END AS words
+    FROM text
+)
+UPDATE articles a
+SET excerpt = CASE WHEN char_length(c.t) > 280 THEN left(c.t, 280) || '…' ELSE c.t END,
+    word_count = c.words,
+    reading_minutes = (c.words + 199) / 200
+FROM counted c
+WHERE c.id = a.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS excerpt,
+    DROP COLUMN IF EXISTS word_count,
+    DROP COLUMN IF EXISTS reading_minutes;
+-- +goose StatementEnd
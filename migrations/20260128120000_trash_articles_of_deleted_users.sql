@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- articles of a deleted user go to the trash instead of vanishing: they
+-- fire "deleted" events like any other delete and are purged after the
+-- trash retention
+ALTER TABLE articles
+    DROP CONSTRAINT articles_author_id_fkey,
+    ADD CONSTRAINT articles_author_id_fkey
+        FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE FUNCTION trash_articles_of_user() RETURNS trigger AS $$
+BEGIN
+    UPDATE articles SET deleted_at = now()
+    WHERE author_id = OLD.id AND deleted_at IS NULL;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_trash_articles
+    BEFORE DELETE ON users
+    FOR EACH ROW EXECUTE FUNCTION trash_articles_of_user();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS users_trash_articles ON users;
+DROP FUNCTION IF EXISTS trash_articles_of_user();
+
+ALTER TABLE articles
+    DROP CONSTRAINT articles_author_id_fkey,
+    ADD CONSTRAINT articles_author_id_fkey
+        FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE;
+-- +goose StatementEnd
@@ -0,0 +1,108 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_article_event() RETURNS trigger AS $$
+DECLARE
+    ev article_events;
+    ev_type VARCHAR(20);
+    ev_article INTEGER;
+BEGIN
+    -- runs at commit; one committing transaction at a time takes event
+    -- IDs, and the lock is only released once it is visible, so IDs grow
+    -- in commit order and a reader following "id > last" misses nothing
+    PERFORM pg_advisory_xact_lock(hashtext('article_events'));
+
+    IF TG_OP = 'INSERT' THEN
+        ev_type := 'created';
+        ev_article := NEW.id;
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            ev_type := 'deleted';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            ev_type := 'created';
+        ELSIF NEW.deleted_at IS NULL THEN
+            ev_type := 'updated';
+        ELSE
+            RETURN NULL;
+        END IF;
+        ev_article := NEW.id;
+    ELSE
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        ev_type := 'deleted';
+        ev_article := OLD.id;
+    END IF;
+
+    INSERT INTO article_events (type, article_id) VALUES (ev_type, ev_article) RETURNING * INTO ev;
+
+    PERFORM pg_notify('article_events', json_build_object(
+        'id', ev.id,
+        'type', ev.type,
+        'article_id', ev.article_id,
+        'created_at', ev.created_at
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER articles_notify_event ON articles;
+CREATE CONSTRAINT TRIGGER articles_notify_event
+    AFTER INSERT OR UPDATE OR DELETE ON articles
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION notify_article_event();
+
+-- for the retention job
+CREATE INDEX article_events_created_at_idx ON article_events (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS article_events_created_at_idx;
+
+DROP TRIGGER articles_notify_event ON articles;
+CREATE TRIGGER articles_notify_event
+    AFTER INSERT OR UPDATE OR DELETE ON articles
+    FOR EACH ROW EXECUTE FUNCTION notify_article_event();
+
+CREATE OR REPLACE FUNCTION notify_article_event() RETURNS trigger AS $$
+DECLARE
+    ev article_events;
+    ev_type VARCHAR(20);
+    ev_article INTEGER;
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        ev_type := 'created';
+        ev_article := NEW.id;
+    ELSIF TG_OP = 'UPDATE' THEN
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+            ev_type := 'deleted';
+        ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+            ev_type := 'created';
+        ELSIF NEW.deleted_at IS NULL THEN
+            ev_type := 'updated';
+        ELSE
+            RETURN NULL;
+        END IF;
+        ev_article := NEW.id;
+    ELSE
+        IF OLD.deleted_at IS NOT NULL THEN
+            RETURN NULL;
+        END IF;
+        ev_type := 'deleted';
+        ev_article := OLD.id;
+    END IF;
+
+    INSERT INTO article_events (type, article_id) VALUES (ev_type, ev_article) RETURNING * INTO ev;
+
+    PERFORM pg_notify('article_events', json_build_object(
+        'id', ev.id,
+        'type', ev.type,
+        'article_id', ev.article_id,
+        'created_at', ev.created_at
+    )::text);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- files of a deleted user lose their owner instead of vanishing with the
+-- user, so the media purge can still find and delete their blobs
+ALTER TABLE media
+    ALTER COLUMN owner_id DROP NOT NULL,
+    DROP CONSTRAINT media_owner_id_fkey,
+    ADD CONSTRAINT media_owner_id_fkey
+        FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX media_orphaned_idx ON media (created_at) WHERE owner_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS media_orphaned_idx;
+-- their blobs are left behind, as before
+DELETE FROM media WHERE owner_id IS NULL;
+
+ALTER TABLE media
+    DROP CONSTRAINT media_owner_id_fkey,
+    ADD CONSTRAINT media_owner_id_fkey
+        FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE,
+    ALTER COLUMN owner_id SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,118 @@
+// Package migrations holds the goose migrations. The tests apply them to
+// the database in TEST_DATABASE_URL, each in a schema of its own, and are
+// skipped without one.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// connect opens a connection whose objects live in a fresh schema, dropped
+// when the test ends.
+func connect(t *testing.T) *pgx.Conn {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("migrations_test_%d", os.Getpid())
+	if _, err := conn.Exec(ctx, "DROP SCHEMA IF EXISTS "+schema+" CASCADE; CREATE SCHEMA "+schema+"; SET search_path = "+schema+", public"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = conn.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		_ = conn.Close(ctx)
+	})
+	return conn
+}
+
+// migrateUp applies the Up part of the migrations from from up to, not
+// including, to; empty bounds are open.
+func migrateUp(t *testing.T, conn *pgx.Conn, from, to string) {
+	t.Helper()
+	files, err := filepath.Glob("*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(files)
+
+	for _, file := range files {
+		if file < from || to != "" && file >= to {
+			continue
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, _, _ := strings.Cut(string(b), "-- +goose Down")
+		if _, err := conn.Exec(context.Background(), up); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+	}
+}
+
+func TestMigrateUp(t *testing.T) {
+	conn := connect(t)
+	migrateUp(t, conn, "", "")
+}
+
+func TestArticleSlugBackfill(t *testing.T) {
+	const migration = "20260122120000_add_article_slugs.sql"
+
+	tests := []struct {
+		name   string
+		titles []string
+		want   []string
+	}{
+		{"distinct", []string{"Hello, World!", "Привет, мир", "---"}, []string{"hello-world", "privet-mir", "article"}},
+		{"duplicates", []string{"Foo", "Foo", "foo!"}, []string{"foo", "foo-2", "foo-3"}},
+		{"suffix taken by a title", []string{"Foo", "Foo", "Foo 2"}, []string{"foo", "foo-3", "foo-2"}},
+		{"suffix taken twice", []string{"Foo 2", "Foo", "Foo", "Foo 3", "Foo"}, []string{"foo-2", "foo", "foo-4", "foo-3", "foo-5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			conn := connect(t)
+			migrateUp(t, conn, "", migration)
+
+			var userID string
+			err := conn.QueryRow(ctx, `INSERT INTO users (email, username, password_hash) VALUES ('a@example.com', 'a', 'x') RETURNING id`).Scan(&userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, title := range tt.titles {
+				if _, err := conn.Exec(ctx, `INSERT INTO articles (title, content, author_id) VALUES ($1, 'text', $2)`, title, userID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			migrateUp(t, conn, migration, "20260124120000")
+
+			rows, err := conn.Query(ctx, `SELECT slug FROM articles ORDER BY id`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("slugs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
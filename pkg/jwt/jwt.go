@@ -11,6 +11,18 @@ import (
 type Claims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
+	// TokenVersion must match the user's current version, see users.token_version
+	TokenVersion int `json:"tv"`
+	// SessionID identifies the login, see user_sessions
+	SessionID string `json:"sid,omitempty"`
+	// ClientID and GrantID are set on tokens issued to OAuth clients, which
+	// are limited to the space separated Scope.
+	ClientID string `json:"cid,omitempty"`
+	GrantID  string `json:"gid,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+	// Purpose is empty for access tokens. Purpose tokens (e.g. an MFA
+	// challenge) are never accepted by ParseToken.
+	Purpose string `json:"purpose,omitempty"`
 	jwtlib.RegisteredClaims
 }
 
@@ -26,19 +38,63 @@ func NewManager(secret string, ttl time.Duration) *Manager {
 	}
 }
 
-func (m *Manager) GenerateToken(userId uuid.UUID, username string) (string, error) {
+// TTL is the lifetime of access tokens.
+func (m *Manager) TTL() time.Duration {
+	return m.ttl
+}
+
+func (m *Manager) GenerateToken(userId uuid.UUID, username string, tokenVersion int, sessionID uuid.UUID, expiresAt time.Time) (string, error) {
+	return m.sign(&Claims{
+		UserID:       userId.String(),
+		Username:     username,
+		TokenVersion: tokenVersion,
+		SessionID:    sessionID.String(),
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			Subject:   userId.String(),
+			IssuedAt:  jwtlib.NewNumericDate(time.Now()),
+			ExpiresAt: jwtlib.NewNumericDate(expiresAt),
+		},
+	})
+}
+
+// GenerateClientToken issues an access token for an OAuth client acting
+// on behalf of the user.
+func (m *Manager) GenerateClientToken(userId uuid.UUID, username string, tokenVersion int, clientID, grantID uuid.UUID, scope string, ttl time.Duration) (string, error) {
 	now := time.Now()
 
-	claims := &Claims{
-		UserID:   userId.String(),
-		Username: username,
+	return m.sign(&Claims{
+		UserID:       userId.String(),
+		Username:     username,
+		TokenVersion: tokenVersion,
+		ClientID:     clientID.String(),
+		GrantID:      grantID.String(),
+		Scope:        scope,
 		RegisteredClaims: jwtlib.RegisteredClaims{
 			Subject:   userId.String(),
+			Audience:  jwtlib.ClaimStrings{clientID.String()},
 			IssuedAt:  jwtlib.NewNumericDate(now),
-			ExpiresAt: jwtlib.NewNumericDate(now.Add(m.ttl)),
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(ttl)),
 		},
-	}
+	})
+}
+
+// GeneratePurposeToken issues a short-lived token that only
+// ParsePurposeToken with the same purpose accepts.
+func (m *Manager) GeneratePurposeToken(userId uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	return m.sign(&Claims{
+		UserID:  userId.String(),
+		Purpose: purpose,
+		RegisteredClaims: jwtlib.RegisteredClaims{
+			Subject:   userId.String(),
+			IssuedAt:  jwtlib.NewNumericDate(now),
+			ExpiresAt: jwtlib.NewNumericDate(now.Add(ttl)),
+		},
+	})
+}
 
+func (m *Manager) sign(claims *Claims) (string, error) {
 	token := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims)
 
 	signedToken, err := token.SignedString(m.secret)
@@ -49,6 +105,28 @@ func (m *Manager) GenerateToken(userId uuid.UUID, username string) (string, erro
 }
 
 func (m *Manager) ParseToken(tokenString string) (*Claims, error) {
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, fmt.Errorf("not an access token")
+	}
+	return claims, nil
+}
+
+func (m *Manager) ParsePurposeToken(tokenString, purpose string) (*Claims, error) {
+	claims, err := m.parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purpose {
+		return nil, fmt.Errorf("unexpected token purpose %q", claims.Purpose)
+	}
+	return claims, nil
+}
+
+func (m *Manager) parse(tokenString string) (*Claims, error) {
 	token, err := jwtlib.ParseWithClaims(tokenString, &Claims{}, func(token *jwtlib.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwtlib.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %T", token.Method)
@@ -0,0 +1,160 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEviction(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		ops  func(c *Cache[string, int])
+		want map[string]bool // key -> present
+	}{
+		{
+			name: "oldest evicted",
+			size: 2,
+			ops: func(c *Cache[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Add("c", 3)
+			},
+			want: map[string]bool{"a": false, "b": true, "c": true},
+		},
+		{
+			name: "get refreshes",
+			size: 2,
+			ops: func(c *Cache[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Get("a")
+				c.Add("c", 3)
+			},
+			want: map[string]bool{"a": true, "b": false, "c": true},
+		},
+		{
+			name: "re-add refreshes",
+			size: 2,
+			ops: func(c *Cache[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Add("a", 10)
+				c.Add("c", 3)
+			},
+			want: map[string]bool{"a": true, "b": false, "c": true},
+		},
+		{
+			name: "size below one",
+			size: 0,
+			ops: func(c *Cache[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+			},
+			want: map[string]bool{"a": false, "b": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New[string, int](tt.size, 0)
+			tt.ops(c)
+			n := 0
+			for key, present := range tt.want {
+				if _, ok := c.Get(key); ok != present {
+					t.Errorf("Get(%q) present = %v, want %v", key, ok, present)
+				}
+				if present {
+					n++
+				}
+			}
+			if c.Len() != n {
+				t.Errorf("Len = %d, want %d", c.Len(), n)
+			}
+		})
+	}
+}
+
+func TestAddReplacesValue(t *testing.T) {
+	c := New[string, int](2, 0)
+	c.Add("a", 1)
+	c.Add("a", 2)
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Errorf("Get = %d, %v, want 2, true", v, ok)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, want 1", c.Len())
+	}
+}
+
+func TestTTL(t *testing.T) {
+	c := New[string, int](10, 20*time.Millisecond)
+	c.Add("a", 1)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("fresh entry missing")
+	}
+	time.Sleep(30 * time.Millisecond)
+	c.Add("b", 2)
+	if _, ok := c.Get("a"); ok {
+		t.Error("expired entry returned")
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Error("fresh entry missing")
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, want 1 after the expired entry was read", c.Len())
+	}
+}
+
+func TestRemove(t *testing.T) {
+	c := New[int, string](10, 0)
+	for i := range 6 {
+		c.Add(i, fmt.Sprint(i))
+	}
+
+	c.Remove(0)
+	c.Remove(100)
+	c.RemoveFunc(func(k int, _ string) bool { return k%2 == 0 })
+
+	for i := range 6 {
+		if _, ok := c.Get(i); ok != (i%2 == 1) {
+			t.Errorf("Get(%d) present = %v", i, ok)
+		}
+	}
+	if c.Len() != 3 {
+		t.Errorf("Len = %d, want 3", c.Len())
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Len after Purge = %d", c.Len())
+	}
+	if _, ok := c.Get(1); ok {
+		t.Error("Get after Purge found an entry")
+	}
+	c.Add(7, "7")
+	if v, ok := c.Get(7); !ok || v != "7" {
+		t.Error("cache unusable after Purge")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	c := New[int, int](16, time.Minute)
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				c.Add(i%32, g)
+				c.Get((i + g) % 32)
+				if i%100 == 0 {
+					c.Remove(i % 32)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if c.Len() > 16 {
+		t.Errorf("Len = %d, want at most 16", c.Len())
+	}
+}
@@ -0,0 +1,102 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// cheap parameters, the defaults take a while per hash
+var testArgon2Params = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}
+
+// pinned output of these parameters for "password" and salt "somesalt",
+// catches changes to the encoding
+const testArgon2Hash = "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$pMlwOs/gn+zdvoB/AGiRNA"
+
+func TestArgon2idVerifyPinned(t *testing.T) {
+	s := NewArgon2id(testArgon2Params)
+	for plain, want := range map[string]bool{"password": true, "Password": false, "": false} {
+		ok, err := s.Verify(testArgon2Hash, plain)
+		if err != nil || ok != want {
+			t.Errorf("Verify(%q) = %v, %v, want %v", plain, ok, err, want)
+		}
+	}
+}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	s := NewArgon2id(testArgon2Params)
+	hash, err := s.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") || !s.Matches(hash) {
+		t.Errorf("Hash = %q", hash)
+	}
+	if ok, err := s.Verify(hash, "correct horse"); !ok || err != nil {
+		t.Errorf("Verify(right) = %v, %v", ok, err)
+	}
+	if ok, err := s.Verify(hash, "correct horse!"); ok || err != nil {
+		t.Errorf("Verify(wrong) = %v, %v", ok, err)
+	}
+	other, _ := s.Hash("correct horse")
+	if other == hash {
+		t.Error("two hashes share a salt")
+	}
+}
+
+func TestDecodeArgon2id(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+		want Argon2Params
+		err  bool
+	}{
+		{"valid", testArgon2Hash, Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}, false},
+		{"argon2i", "$argon2i$v=19$m=64,t=1,p=1$c29tZXNhbHQ$pMlwOs/gn+zdvoB/AGiRNA", Argon2Params{}, true},
+		{"old version", "$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$pMlwOs/gn+zdvoB/AGiRNA", Argon2Params{}, true},
+		{"missing params", "$argon2id$v=19$m=64$c29tZXNhbHQ$pMlwOs/gn+zdvoB/AGiRNA", Argon2Params{}, true},
+		{"too few fields", "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ", Argon2Params{}, true},
+		{"bad salt", "$argon2id$v=19$m=64,t=1,p=1$!!!$pMlwOs/gn+zdvoB/AGiRNA", Argon2Params{}, true},
+		{"padded key", "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$pMlwOs/gn+zdvoB/AGiRNA==", Argon2Params{}, true},
+		{"empty key", "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$", Argon2Params{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _, _, err := decodeArgon2id(tt.hash)
+			if tt.err {
+				if !errors.Is(err, ErrMalformedHash) {
+					t.Errorf("err = %v, want ErrMalformedHash", err)
+				}
+				return
+			}
+			if err != nil || p != tt.want {
+				t.Errorf("decode = %+v, %v, want %+v", p, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgon2idNeedsRehash(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Argon2Params
+		want   bool
+	}{
+		{"same", testArgon2Params, false},
+		{"more memory", Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}, true},
+		{"more iterations", Argon2Params{Memory: 64, Iterations: 2, Parallelism: 1, SaltLength: 8, KeyLength: 16}, true},
+		{"more lanes", Argon2Params{Memory: 64, Iterations: 1, Parallelism: 2, SaltLength: 8, KeyLength: 16}, true},
+		{"longer salt", Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 16}, true},
+		{"longer key", Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 32}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewArgon2id(tt.params).NeedsRehash(testArgon2Hash); got != tt.want {
+				t.Errorf("NeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !NewArgon2id(testArgon2Params).NeedsRehash("$argon2id$garbage") {
+		t.Error("NeedsRehash(malformed) = false")
+	}
+}
@@ -0,0 +1,51 @@
+package password
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const DefaultBcryptCost = 12
+
+type bcryptScheme struct {
+	cost int
+}
+
+// NewBcrypt hashes with bcrypt. Passwords over 72 bytes are rejected with
+// bcrypt.ErrPasswordTooLong instead of being truncated.
+func NewBcrypt(cost int) Scheme {
+	return &bcryptScheme{cost: cost}
+}
+
+func (s *bcryptScheme) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") ||
+		strings.HasPrefix(hash, "$2b$") ||
+		strings.HasPrefix(hash, "$2y$")
+}
+
+func (s *bcryptScheme) Hash(plain string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), s.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (s *bcryptScheme) Verify(hash, plain string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (s *bcryptScheme) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != s.cost
+}
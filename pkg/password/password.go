@@ -1,18 +1,62 @@
 package password
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"errors"
+)
 
-const cost = 12
+var (
+	ErrUnknownScheme = errors.New("password: unknown hash scheme")
+	ErrMalformedHash = errors.New("password: malformed hash")
+)
 
-func Hash(plain string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(plain), cost)
-	if err != nil {
-		return "", err
-	}
-	return string(hash), nil
+// Scheme is one hashing algorithm. Stored hashes are self-describing
+// (PHC "$argon2id$...", modular crypt "$2a$..." for bcrypt), so the scheme
+// of an old hash can always be recognised.
+type Scheme interface {
+	// Matches reports whether hash was produced by this scheme.
+	Matches(hash string) bool
+	Hash(plain string) (string, error)
+	Verify(hash, plain string) (bool, error)
+	// NeedsRehash reports a hash made with other parameters than the
+	// scheme is configured with now.
+	NeedsRehash(hash string) bool
+}
+
+// Hasher hashes new passwords with the current scheme and still verifies
+// hashes of legacy schemes.
+type Hasher struct {
+	current Scheme
+	legacy  []Scheme
+}
+
+func NewHasher(current Scheme, legacy ...Scheme) *Hasher {
+	return &Hasher{current: current, legacy: legacy}
+}
+
+// Default is Argon2id with DefaultArgon2Params, bcrypt for old hashes.
+func Default() *Hasher {
+	return NewHasher(NewArgon2id(DefaultArgon2Params), NewBcrypt(DefaultBcryptCost))
 }
 
-func Check(hash string, plain string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
-	return err == nil
+func (h *Hasher) Hash(plain string) (string, error) {
+	return h.current.Hash(plain)
+}
+
+// Verify checks plain against hash. rehash is set when the password is
+// correct but hash uses a legacy scheme or outdated parameters; the caller
+// should then store h.Hash(plain).
+func (h *Hasher) Verify(hash, plain string) (ok, rehash bool, err error) {
+	if h.current.Matches(hash) {
+		ok, err = h.current.Verify(hash, plain)
+		return ok, ok && h.current.NeedsRehash(hash), err
+	}
+
+	for _, s := range h.legacy {
+		if s.Matches(hash) {
+			ok, err = s.Verify(hash, plain)
+			return ok, ok, err
+		}
+	}
+
+	return false, false, ErrUnknownScheme
 }
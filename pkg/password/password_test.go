@@ -0,0 +1,89 @@
+package password
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHasherVerify(t *testing.T) {
+	current := NewArgon2id(testArgon2Params)
+	legacy := NewBcrypt(bcrypt.MinCost)
+	h := NewHasher(current, legacy)
+
+	argonHash, err := current.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := legacy.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	outdated, err := NewArgon2id(Argon2Params{Memory: 32, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		hash       string
+		plain      string
+		wantOK     bool
+		wantRehash bool
+		wantErr    error
+	}{
+		{"current", argonHash, "secret", true, false, nil},
+		{"current wrong", argonHash, "Secret", false, false, nil},
+		{"outdated params", outdated, "secret", true, true, nil},
+		{"outdated params wrong", outdated, "Secret", false, false, nil},
+		{"legacy bcrypt", bcryptHash, "secret", true, true, nil},
+		{"legacy bcrypt wrong", bcryptHash, "Secret", false, false, nil},
+		{"unknown scheme", "$1$abc$def", "secret", false, false, ErrUnknownScheme},
+		{"plain text", "secret", "secret", false, false, ErrUnknownScheme},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, rehash, err := h.Verify(tt.hash, tt.plain)
+			if ok != tt.wantOK || rehash != tt.wantRehash || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify = %v, %v, %v, want %v, %v, %v", ok, rehash, err, tt.wantOK, tt.wantRehash, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHasherHashUsesCurrent(t *testing.T) {
+	h := NewHasher(NewArgon2id(testArgon2Params), NewBcrypt(bcrypt.MinCost))
+	hash, err := h.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash, err := h.Verify(hash, "secret"); !ok || rehash || err != nil {
+		t.Errorf("Verify(own hash) = %v, %v, %v", ok, rehash, err)
+	}
+}
+
+func TestBcryptMatches(t *testing.T) {
+	s := NewBcrypt(bcrypt.MinCost)
+	for hash, want := range map[string]bool{
+		"$2a$04$abc":     true,
+		"$2b$04$abc":     true,
+		"$2y$04$abc":     true,
+		"$2x$04$abc":     false,
+		"$argon2id$v=19": false,
+	} {
+		if got := s.Matches(hash); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", hash, got, want)
+		}
+	}
+}
+
+func TestBcryptTooLong(t *testing.T) {
+	long := make([]byte, 73)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if _, err := NewBcrypt(bcrypt.MinCost).Hash(string(long)); !errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		t.Errorf("Hash(73 bytes) err = %v, want ErrPasswordTooLong", err)
+	}
+}
@@ -0,0 +1,93 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrPolicy = errors.New("password does not satisfy policy")
+
+// MaxLengthLimit is the highest MaxLength a policy may set.
+const MaxLengthLimit = 1024
+
+// PolicyError names the rule a password broke; it unwraps to ErrPolicy.
+type PolicyError struct {
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return "password " + e.Reason
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicy
+}
+
+type Policy struct {
+	// MinLength and MaxLength count characters, not bytes.
+	MinLength int
+	MaxLength int
+	// MinScore is the lowest accepted Strength score, 0 disables the check.
+	MinScore int
+	// DisallowUserInfo rejects passwords containing the username or the
+	// local part of the email.
+	DisallowUserInfo bool
+}
+
+var DefaultPolicy = Policy{
+	MinLength:        10,
+	MaxLength:        128,
+	MinScore:         3,
+	DisallowUserInfo: true,
+}
+
+// Validate checks plain against the policy. userInputs are the username,
+// email and the like: they must not appear in the password and count as
+// cheap guesses when scoring.
+func (p Policy) Validate(plain string, userInputs ...string) error {
+	n := utf8.RuneCountInString(plain)
+	if n < p.MinLength {
+		return &PolicyError{Reason: "is too short"}
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return &PolicyError{Reason: "is too long"}
+	}
+
+	inputs := userInputTokens(userInputs)
+	if p.DisallowUserInfo {
+		lower := strings.ToLower(plain)
+		for _, in := range inputs {
+			if strings.Contains(lower, in) {
+				return &PolicyError{Reason: "must not contain your username or email"}
+			}
+		}
+	}
+
+	if p.MinScore > 0 && Strength(plain, inputs...) < p.MinScore {
+		return &PolicyError{Reason: "is too weak"}
+	}
+	return nil
+}
+
+// userInputTokens lowercases the inputs and splits emails into the local
+// part and the domain name, dropping pieces too short to matter.
+func userInputTokens(inputs []string) []string {
+	var out []string
+	add := func(s string) {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if utf8.RuneCountInString(s) >= 3 {
+			out = append(out, s)
+		}
+	}
+
+	for _, in := range inputs {
+		local, domain, isEmail := strings.Cut(in, "@")
+		add(local)
+		if isEmail {
+			name, _, _ := strings.Cut(domain, ".")
+			add(name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package password
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		plain  string
+		inputs []string
+		reason string
+	}{
+		{"ok", DefaultPolicy, "X7#kq9!vLm2@pR", []string{"alice", "alice@example.com"}, ""},
+		{"too short", DefaultPolicy, "X7#kq9!", nil, "is too short"},
+		{"short in bytes is runes", Policy{MinLength: 4}, "ёжик", nil, ""},
+		{"too long", DefaultPolicy, strings.Repeat("X7#kq9!vLm", 13), nil, "is too long"},
+		{"no max length", Policy{MinLength: 1}, strings.Repeat("x", 2000), nil, ""},
+		{"username", DefaultPolicy, "X7#Alice9!vLm", []string{"alice"}, "must not contain your username or email"},
+		{"email local part", DefaultPolicy, "X7#kq9!jsmith", []string{"jsmith@example.com"}, "must not contain your username or email"},
+		{"email domain", DefaultPolicy, "X7#kq9!Acme2@pR", []string{"bob@acme.org"}, "must not contain your username or email"},
+		{"user info allowed", Policy{MinLength: 1, DisallowUserInfo: false}, "alice", []string{"alice"}, ""},
+		{"too weak", DefaultPolicy, "password123", nil, "is too weak"},
+		{"score disabled", Policy{MinLength: 1}, "password", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.plain, tt.inputs...)
+			if tt.reason == "" {
+				if err != nil {
+					t.Errorf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			var pe *PolicyError
+			if !errors.As(err, &pe) || pe.Reason != tt.reason {
+				t.Fatalf("Validate = %v, want %q", err, tt.reason)
+			}
+			if !errors.Is(err, ErrPolicy) {
+				t.Error("error does not unwrap to ErrPolicy")
+			}
+		})
+	}
+}
+
+func TestUserInputTokens(t *testing.T) {
+	tests := []struct {
+		inputs []string
+		want   []string
+	}{
+		{nil, nil},
+		{[]string{"Alice"}, []string{"alice"}},
+		{[]string{"  Bob  "}, []string{"bob"}},
+		{[]string{"jo"}, nil},
+		{[]string{"J.Smith@Example.com"}, []string{"j.smith", "example"}},
+		{[]string{"jo@io.io"}, nil},
+		{[]string{"alice", "alice@mail.example.org"}, []string{"alice", "alice", "mail"}},
+	}
+	for _, tt := range tests {
+		if got := userInputTokens(tt.inputs); !slices.Equal(got, tt.want) {
+			t.Errorf("userInputTokens(%q) = %q, want %q", tt.inputs, got, tt.want)
+		}
+	}
+}
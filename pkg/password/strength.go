@@ -0,0 +1,235 @@
+package password
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common.txt
+var commonList string
+
+// commonRank maps a common password or word to its rank (1 = most common).
+var commonRank = func() map[string]int {
+	m := make(map[string]int)
+	for i, w := range strings.Fields(commonList) {
+		if _, ok := m[w]; !ok {
+			m[w] = i + 1
+		}
+	}
+	return m
+}()
+
+var keyboardRows = []string{
+	"`1234567890-=",
+	"qwertyuiop[]\\",
+	"asdfghjkl;'",
+	"zxcvbnm,./",
+	"йцукенгшщзхъ",
+	"фывапролджэ",
+	"ячсмитьбю",
+}
+
+var leet = strings.NewReplacer("0", "o", "1", "l", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i")
+
+const maxScoredBytes = 256
+
+// truncate cuts s to at most n bytes without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// Strength scores a password from 0 (trivial) to 4 (very strong) in the
+// spirit of zxcvbn: the password is split greedily into the cheapest
+// patterns an attacker tries first (common words, user inputs, repeats,
+// sequences, keyboard runs), the remainder is brute force, and the
+// estimated number of guesses is mapped to a score.
+//
+// Only the first maxScoredBytes are scored: the matchers are polynomial in
+// the length and a prefix is never stronger than the whole password.
+func Strength(plain string, userInputs ...string) int {
+	plain = truncate(plain, maxScoredBytes)
+	guesses := estimateGuesses([]rune(strings.ToLower(plain)), cardinality(plain), userInputs)
+
+	switch lg := math.Log10(guesses); {
+	case lg < 3:
+		return 0
+	case lg < 6:
+		return 1
+	case lg < 8:
+		return 2
+	case lg < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func estimateGuesses(r []rune, card float64, userInputs []string) float64 {
+	guesses := 1.0
+	tokens := 0
+
+	for i := 0; i < len(r); {
+		n, g := bestMatch(r[i:], userInputs)
+		if n == 0 {
+			n, g = 1, card
+		}
+		guesses *= g
+		tokens++
+		i += n
+	}
+
+	// the attacker also has to guess how the patterns are combined
+	for k := 2; k <= tokens; k++ {
+		guesses *= 1.5
+	}
+	return guesses
+}
+
+// bestMatch returns the length and guess count of the longest pattern at
+// the start of r, or 0 when nothing matches.
+func bestMatch(r []rune, userInputs []string) (int, float64) {
+	bestLen, bestGuesses := 0, 0.0
+	consider := func(n int, g float64) {
+		if n > bestLen || (n == bestLen && g < bestGuesses) {
+			bestLen, bestGuesses = n, g
+		}
+	}
+
+	for _, in := range userInputs {
+		if in != "" && strings.HasPrefix(string(r), in) {
+			consider(len([]rune(in)), 10)
+		}
+	}
+
+	// dictionary words, also in leetspeak; shortest useful word is 3 runes
+	for n := len(r); n >= 3; n-- {
+		w := string(r[:n])
+		if rank, ok := commonRank[w]; ok {
+			consider(n, float64(rank)+1)
+			break
+		}
+		if rank, ok := commonRank[leet.Replace(w)]; ok {
+			consider(n, float64(rank)*2+1)
+			break
+		}
+	}
+
+	if n := repeatLen(r); n >= 3 {
+		consider(n, float64(n)*10)
+	}
+	if n := sequenceLen(r); n >= 3 {
+		consider(n, float64(n)*20)
+	}
+	if n := keyboardLen(r); n >= 3 {
+		consider(n, float64(n)*40)
+	}
+
+	return bestLen, bestGuesses
+}
+
+// repeatLen is the length of a run of one character, or of a repeated
+// block like "abcabc".
+func repeatLen(r []rune) int {
+	best := 0
+	for block := 1; block <= len(r)/2; block++ {
+		n := block
+		for n+block <= len(r) && string(r[n:n+block]) == string(r[:block]) {
+			n += block
+		}
+		if n > block && n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+// sequenceLen is the length of an ascending or descending run like
+// "abcd" or "9876".
+func sequenceLen(r []rune) int {
+	if len(r) < 2 {
+		return len(r)
+	}
+	step := r[1] - r[0]
+	if step != 1 && step != -1 {
+		return 1
+	}
+	n := 2
+	for n < len(r) && r[n]-r[n-1] == step {
+		n++
+	}
+	return n
+}
+
+func keyboardLen(r []rune) int {
+	best := 0
+	for _, row := range keyboardRows {
+		for _, line := range []string{row, reverse(row)} {
+			lr := []rune(line)
+			for start := range lr {
+				n := 0
+				for n < len(r) && start+n < len(lr) && r[n] == lr[start+n] {
+					n++
+				}
+				if n > best {
+					best = n
+				}
+			}
+		}
+	}
+	return best
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// cardinality is the size of the alphabet a brute-force attacker needs for
+// the character classes used in plain.
+func cardinality(plain string) float64 {
+	var lower, upper, digit, symbol, other bool
+	for _, c := range plain {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case unicode.IsDigit(c):
+			digit = true
+		case c < 128:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	card := 0.0
+	if lower {
+		card += 26
+	}
+	if upper {
+		card += 26
+	}
+	if digit {
+		card += 10
+	}
+	if symbol {
+		card += 33
+	}
+	if other {
+		card += 100
+	}
+	return card
+}
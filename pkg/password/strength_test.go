@@ -0,0 +1,67 @@
+package password
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestStrength(t *testing.T) {
+	tests := []struct {
+		plain  string
+		inputs []string
+		min    int
+		max    int
+	}{
+		{"", nil, 0, 0},
+		{"password", nil, 0, 0},
+		{"p@ssw0rd", nil, 0, 1},
+		{"aaaaaaaaaaaa", nil, 0, 1},
+		{"abcdefghijkl", nil, 0, 1},
+		{"qwertyuiop", nil, 0, 1},
+		{"йцукенгшщз", nil, 0, 1},
+		{"johnsmith1", []string{"johnsmith"}, 0, 2},
+		{"correct-horse-battery-staple", nil, 3, 4},
+		{"X7#kq9!vLm2@pR", nil, 4, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.plain, func(t *testing.T) {
+			if got := Strength(tt.plain, tt.inputs...); got < tt.min || got > tt.max {
+				t.Errorf("Strength(%q) = %d, want %d..%d", tt.plain, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestStrengthLongInput(t *testing.T) {
+	long := strings.Repeat("aZ7#", 100_000)
+	start := time.Now()
+	Strength(long, "someone@example.com")
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Strength of %d bytes took %v", len(long), d)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"abc", 5, "abc"},
+		{"abcdef", 3, "abc"},
+		{"абв", 6, "абв"},
+		{"абв", 5, "аб"},
+		{"абв", 3, "а"},
+		{"абв", 1, ""},
+		{"a😀b", 4, "a"},
+		{"a😀b", 5, "a😀"},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.n)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
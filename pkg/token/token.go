@@ -0,0 +1,26 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const size = 32
+
+// Generate returns a random URL-safe token and its hash. Only the hash
+// should be stored.
+func Generate() (plain string, hash string, err error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	plain = base64.RawURLEncoding.EncodeToString(b)
+	return plain, Hash(plain), nil
+}
+
+func Hash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
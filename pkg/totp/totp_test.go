@@ -0,0 +1,115 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the SHA-1 key of RFC 6238 appendix B, "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238(t *testing.T) {
+	// appendix B values, last 6 of the 8 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestCodeLowercaseSecret(t *testing.T) {
+	want, _ := Code(rfcSecret, 1)
+	got, err := Code(strings.ToLower(rfcSecret), 1)
+	if err != nil || got != want {
+		t.Errorf("Code(lowercase) = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Error("Code with invalid secret: want error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+	prev, _ := Code(rfcSecret, step-1)
+	old, _ := Code(rfcSecret, step-2)
+
+	tests := []struct {
+		name     string
+		code     string
+		skew     int
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current", "050471", 0, step, true},
+		{"surrounding spaces", " 050471 ", 0, step, true},
+		{"previous within skew", prev, 1, step - 1, true},
+		{"previous without skew", prev, 0, 0, false},
+		{"outside skew", old, 1, 0, false},
+		{"wrong", "000000", 1, 0, false},
+		{"short", "05047", 1, 0, false},
+		{"long", "0504710", 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Validate(rfcSecret, tt.code, now, tt.skew)
+			if ok != tt.wantOK || got != tt.wantStep {
+				t.Errorf("Validate(%q) = %d, %v, want %d, %v", tt.code, got, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateSecret()
+	if a == b {
+		t.Error("two secrets are equal")
+	}
+	key, err := encoding.DecodeString(a)
+	if err != nil || len(key) != secretSize {
+		t.Errorf("secret %q decodes to %d bytes, %v", a, len(key), err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("go press", "ann@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Errorf("URI = %s, want otpauth://totp/...", u)
+	}
+	if u.Path != "/go press:ann@example.com" {
+		t.Errorf("label = %q", u.Path)
+	}
+	q := u.Query()
+	for k, want := range map[string]string{"secret": rfcSecret, "issuer": "go press", "digits": "6", "period": "30", "algorithm": "SHA1"} {
+		if q.Get(k) != want {
+			t.Errorf("%s = %q, want %q", k, q.Get(k), want)
+		}
+	}
+}